@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	authgrpc "immxrtalbeast/order_microservices/api-gateway/internal/clients/auth"
 	inventorygrpc "immxrtalbeast/order_microservices/api-gateway/internal/clients/inventory"
 	ordergrpc "immxrtalbeast/order_microservices/api-gateway/internal/clients/order"
@@ -9,11 +10,13 @@ import (
 	"immxrtalbeast/order_microservices/api-gateway/internal/controller"
 	"immxrtalbeast/order_microservices/api-gateway/internal/middleware"
 	"immxrtalbeast/order_microservices/api-gateway/internal/tracing"
+	kafka "immxrtalbeast/order_microservices/internal/pkg/kafka"
 	"log/slog"
+	"net/http"
 	"os"
-	"strings"
+	"os/signal"
+	"syscall"
 
-	kafka "github.com/ozzus/order_kafka"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
 	"github.com/gin-contrib/cors"
@@ -25,6 +28,8 @@ func main() {
 	cfg := config.MustLoad()
 
 	log := setupLogger()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
 	tracer, err := tracing.InitTracer("api-gateway", cfg.Clients.Jaeger.Address)
 	if err != nil {
@@ -50,6 +55,7 @@ func main() {
 		cfg.Clients.Auth.RetriesCount,
 	)
 	authMiddleware := middleware.AuthMiddleware(appSecret)
+	optionalAuthMiddleware := middleware.OptionalAuthMiddleware(appSecret)
 
 	if err != nil {
 		log.Error("failed to connect auth service", slog.Any("error", err))
@@ -76,29 +82,31 @@ func main() {
 		log.Error("failed to connect order service", slog.Any("error", err))
 		panic("failed to connect order service")
 	}
-	orderStatusProducer := kafka.NewProducer(
-		[]string{os.Getenv("KAFKA_ADDRESS")},
-		"saga-replies",
-	)
-	defer orderStatusProducer.Close()
+	if err := kafka.EnsureTopics(ctx, []string{os.Getenv("KAFKA_ADDRESS")},
+		kafka.TopicsWithDeadLetters(cfg.Kafka.Partitions, cfg.Kafka.ReplicationFactor, "saga-commands", "saga-replies")...,
+	); err != nil {
+		panic(err)
+	}
+	deadLetters := kafka.NewDeadLetterQueue([]string{os.Getenv("KAFKA_ADDRESS")})
+	defer deadLetters.Close()
 
-	userController := controller.NewUserController(authClient, cfg.TokenTTL)
+	userController := controller.NewUserController(authClient, orderClient, cfg.TokenTTL)
 	inventoryController := controller.NewInventoryController(inventoryClient)
-	orderController := controller.NewOrderController(orderClient, orderStatusProducer)
+	orderController := controller.NewOrderController(orderClient)
+	cartController := controller.NewCartController(orderClient)
+	dlqController := controller.NewDLQController(deadLetters)
 
 	router := gin.Default()
 
 	corsConfig := cors.DefaultConfig()
-	corsConfig.AllowOriginFunc = func(origin string) bool {
-		return strings.HasPrefix(origin, "http://localhost:") ||
-			origin == "http://80.253.249.143"
-	}
+	corsConfig.AllowOriginFunc = middleware.AllowedOrigin
 	corsConfig.AllowCredentials = true
 	corsConfig.AllowHeaders = []string{
 		"Authorization",
 		"Content-Type",
 		"Origin",
 		"Accept",
+		"Idempotency-Key",
 	}
 	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
 	router.Use(cors.New(corsConfig))
@@ -115,26 +123,63 @@ func main() {
 		inventory.POST("/add-good", middleware.AdminOnlyMiddleware(), inventoryController.AddGood)
 		inventory.PATCH("/update-good", middleware.AdminOnlyMiddleware(), inventoryController.UpdateGood)
 		inventory.DELETE("/:id", middleware.AdminOnlyMiddleware(), inventoryController.DeleteGood)
+		inventory.GET("/modifier-groups", middleware.AdminOnlyMiddleware(), inventoryController.ListModifierGroups)
+		inventory.POST("/modifier-groups", middleware.AdminOnlyMiddleware(), inventoryController.CreateModifierGroup)
+		inventory.PUT("/modifier-groups/:id", middleware.AdminOnlyMiddleware(), inventoryController.UpdateModifierGroup)
+		inventory.PUT("/goods/:id/modifier-groups", middleware.AdminOnlyMiddleware(), inventoryController.SetGoodModifierGroups)
 	}
 	order := api.Group("/order")
 	order.Use(authMiddleware)
 	{
 		order.POST("/create-order", orderController.CreateOrder)
 		order.GET("/order/:id", orderController.GetOrder)
+		order.GET("/order/:id/history", orderController.OrderStatusHistory)
 		order.GET("/list-orders/:id", orderController.ListOrders)
 		order.PATCH("/:id/cancel", orderController.CancelOrder)
 		order.DELETE("/:id", orderController.DeleteOrder)
+		order.GET("/:id/events", orderController.WatchOrderEvents)
+		order.GET("/:id/ws", orderController.WatchOrderSocket)
+	}
+	cart := api.Group("/cart")
+	cart.Use(optionalAuthMiddleware)
+	{
+		cart.GET("", cartController.GetCart)
+		cart.POST("/items", cartController.AddItem)
+		cart.PATCH("/items/:product_id", cartController.UpdateItem)
+		cart.DELETE("/items/:product_id", cartController.RemoveItem)
+		cart.POST("/checkout", cartController.Checkout)
 	}
 	admin := api.Group("/admin")
 	admin.Use(authMiddleware, middleware.AdminOnlyMiddleware())
 	{
 		admin.GET("/orders", orderController.ListAllOrders)
 		admin.PATCH("/orders/:id/status", orderController.UpdateOrderStatus)
+		admin.POST("/orders/:id/restore", orderController.RestoreOrder)
+		admin.POST("/orders/:id/refunds", orderController.RefundOrder)
+		admin.GET("/orders/:id/refunds", orderController.ListRefunds)
+		admin.GET("/dlq/:topic", dlqController.ListDeadLetters)
+		admin.POST("/dlq/:topic/redrive", dlqController.RedriveDeadLetter)
 	}
-	if err := router.Run(":8080"); err != nil {
-		panic(err)
+	srv := &http.Server{
+		Addr:    ":8080",
+		Handler: router,
+	}
+	// Shutdown waits for open requests and skips hijacked connections, so
+	// status streams are ended explicitly.
+	srv.RegisterOnShutdown(orderController.CloseWatches)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			panic(err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Info("shutting down api-gateway")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Error("failed to shut down http server", slog.Any("error", err))
 	}
-
 }
 
 func setupLogger() *slog.Logger {
@@ -17,14 +17,20 @@ require (
 	github.com/grpc-ecosystem/go-grpc-middleware/v2 v2.3.2
 	github.com/ilyakaznacheev/cleanenv v1.5.0
 	github.com/joho/godotenv v1.5.1
-	github.com/ozzus/order_kafka v0.0.0-20260621120956-f08d9605a6c7
 	github.com/ozzus/order_protos v0.0.0-20260621120947-f44d30eaffd6
 	go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin v0.62.0
 	go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc v0.63.0
 	go.opentelemetry.io/otel v1.38.0
 	go.opentelemetry.io/otel/exporters/jaeger v1.17.0
 	go.opentelemetry.io/otel/sdk v1.38.0
+	golang.org/x/net v0.44.0
 	google.golang.org/grpc v1.75.1
+	google.golang.org/protobuf v1.36.9
+	immxrtalbeast/order_microservices/internal/pkg/events v0.0.0-00010101000000-000000000000
+	immxrtalbeast/order_microservices/internal/pkg/inventorypb v0.0.0-00010101000000-000000000000
+	immxrtalbeast/order_microservices/internal/pkg/kafka v0.0.0-00010101000000-000000000000
+	immxrtalbeast/order_microservices/internal/pkg/money v0.0.0-00010101000000-000000000000
+	immxrtalbeast/order_microservices/internal/pkg/orderpb v0.0.0-00010101000000-000000000000
 )
 
 require (
@@ -73,11 +79,19 @@ require (
 	go.opentelemetry.io/otel/trace v1.38.0 // indirect
 	golang.org/x/arch v0.18.0 // indirect
 	golang.org/x/crypto v0.42.0 // indirect
-	golang.org/x/net v0.44.0 // indirect
 	golang.org/x/sys v0.36.0 // indirect
 	golang.org/x/text v0.29.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250908214217-97024824d090 // indirect
-	google.golang.org/protobuf v1.36.9 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 	olympos.io/encoding/edn v0.0.0-20201019073823-d3554ca0b0a3 // indirect
 )
+
+replace immxrtalbeast/order_microservices/internal/pkg/inventorypb => ../../internal/pkg/inventorypb
+
+replace immxrtalbeast/order_microservices/internal/pkg/kafka => ../../internal/pkg/kafka
+
+replace immxrtalbeast/order_microservices/internal/pkg/events => ../../internal/pkg/events
+
+replace immxrtalbeast/order_microservices/internal/pkg/orderpb => ../../internal/pkg/orderpb
+
+replace immxrtalbeast/order_microservices/internal/pkg/money => ../../internal/pkg/money
@@ -3,6 +3,8 @@ package auth
 import (
 	"context"
 	"fmt"
+	"immxrtalbeast/order_microservices/internal/pkg/inventorypb"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
 	"net"
 	"time"
 
@@ -18,7 +20,9 @@ import (
 )
 
 type Client struct {
-	api inventory.InventoryClient
+	api       inventory.InventoryClient
+	modifiers inventorypb.ModifierServiceClient
+	stock     inventorypb.StockServiceClient
 }
 
 func New(ctx context.Context, addr string, timeout time.Duration, retriesCount int) (*Client, error) {
@@ -46,11 +50,13 @@ func New(ctx context.Context, addr string, timeout time.Duration, retriesCount i
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 	return &Client{
-		api: inventory.NewInventoryClient(conn),
+		api:       inventory.NewInventoryClient(conn),
+		modifiers: inventorypb.NewModifierServiceClient(conn),
+		stock:     inventorypb.NewStockServiceClient(conn),
 	}, nil
 }
 
-func (c *Client) AddGood(ctx context.Context, name, category, description, imageLink string, price, quantityInStock int, volume int32) error {
+func (c *Client) AddGood(ctx context.Context, name, category, description, imageLink string, price money.Money, quantityInStock int, volume int32) error {
 	const op = "grpc.AddGood"
 
 	_, err := c.api.AddGood(ctx, &inventory.AddGoodRequest{
@@ -58,7 +64,7 @@ func (c *Client) AddGood(ctx context.Context, name, category, description, image
 		Category:        category,
 		Description:     description,
 		ImageLink:       imageLink,
-		Price:           float64(price),
+		Price:           price.Float(),
 		Volume:          volume,
 		QuantityInStock: int64(quantityInStock),
 	})
@@ -78,6 +84,17 @@ func (c *Client) ListProducts(ctx context.Context) ([]*inventory.Product, error)
 	return resp.Products, nil
 }
 
+// ListStock returns the on hand, held and available stock of every good.
+func (c *Client) ListStock(ctx context.Context) ([]*inventorypb.GoodStock, error) {
+	const op = "grpc.ListStock"
+
+	resp, err := c.stock.ListStock(ctx, &inventorypb.ListStockRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return resp.GetGoods(), nil
+}
+
 func (c *Client) DeleteGood(ctx context.Context, goodID uuid.UUID) error {
 	const op = "grpc.DeleteGood"
 
@@ -90,7 +107,7 @@ func (c *Client) DeleteGood(ctx context.Context, goodID uuid.UUID) error {
 	return nil
 }
 
-func (c *Client) UpdateGood(ctx context.Context, goodID uuid.UUID, name, category, description, imageLink string, price, quantityInStock int) error {
+func (c *Client) UpdateGood(ctx context.Context, goodID uuid.UUID, name, category, description, imageLink string, price money.Money, quantityInStock int) error {
 	const op = "grpc.UpdateGood"
 
 	_, err := c.api.UpdateGood(ctx, &inventory.UpdateGoodRequest{
@@ -99,7 +116,7 @@ func (c *Client) UpdateGood(ctx context.Context, goodID uuid.UUID, name, categor
 		Category:        category,
 		Description:     description,
 		ImageLink:       imageLink,
-		Price:           float64(price),
+		Price:           price.Float(),
 		QuantityInStock: int64(quantityInStock),
 	})
 	if err != nil {
@@ -107,3 +124,51 @@ func (c *Client) UpdateGood(ctx context.Context, goodID uuid.UUID, name, categor
 	}
 	return nil
 }
+
+func (c *Client) ListModifierGroups(ctx context.Context) ([]*inventorypb.ModifierGroup, error) {
+	const op = "grpc.ListModifierGroups"
+
+	resp, err := c.modifiers.ListModifierGroups(ctx, &inventorypb.ListModifierGroupsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return resp.GetGroups(), nil
+}
+
+// SaveModifierGroup creates group when it has no ID and replaces the stored
+// group otherwise.
+func (c *Client) SaveModifierGroup(ctx context.Context, group *inventorypb.ModifierGroup) (*inventorypb.ModifierGroup, error) {
+	const op = "grpc.SaveModifierGroup"
+
+	resp, err := c.modifiers.SaveModifierGroup(ctx, &inventorypb.SaveModifierGroupRequest{
+		Group: group,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return resp, nil
+}
+
+func (c *Client) SetGoodModifierGroups(ctx context.Context, goodID uuid.UUID, groupIDs []string) (*inventorypb.GoodModifiers, error) {
+	const op = "grpc.SetGoodModifierGroups"
+
+	resp, err := c.modifiers.SetGoodModifierGroups(ctx, &inventorypb.SetGoodModifierGroupsRequest{
+		GoodId:   goodID.String(),
+		GroupIds: groupIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return resp, nil
+}
+
+// ListGoodModifiers returns the modifier groups of every good.
+func (c *Client) ListGoodModifiers(ctx context.Context) ([]*inventorypb.GoodModifiers, error) {
+	const op = "grpc.ListGoodModifiers"
+
+	resp, err := c.modifiers.ListGoodModifiers(ctx, &inventorypb.ListGoodModifiersRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return resp.GetGoods(), nil
+}
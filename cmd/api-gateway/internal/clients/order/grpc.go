@@ -3,6 +3,7 @@ package order
 import (
 	"context"
 	"fmt"
+	"immxrtalbeast/order_microservices/internal/pkg/orderpb"
 	"net"
 	"time"
 
@@ -14,10 +15,15 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 )
 
 type Client struct {
-	api order.OrderServiceClient
+	api    order.OrderServiceClient
+	status orderpb.OrderStatusServiceClient
+	query  orderpb.OrderQueryServiceClient
+	admin  orderpb.OrderAdminServiceClient
+	cart   orderpb.CartServiceClient
 }
 
 func New(ctx context.Context, addr string, timeout time.Duration, retriesCount int) (*Client, error) {
@@ -45,14 +51,23 @@ func New(ctx context.Context, addr string, timeout time.Duration, retriesCount i
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 	return &Client{
-		api: order.NewOrderServiceClient(conn),
+		api:    order.NewOrderServiceClient(conn),
+		status: orderpb.NewOrderStatusServiceClient(conn),
+		query:  orderpb.NewOrderQueryServiceClient(conn),
+		admin:  orderpb.NewOrderAdminServiceClient(conn),
+		cart:   orderpb.NewCartServiceClient(conn),
 	}, nil
 
 }
 
-func (c *Client) CreateOrder(ctx context.Context, userID string, items []*order.OrderItem) (*order.CreateOrderResponse, error) {
+// CreateOrder creates an order. A non-empty idempotencyKey is sent as
+// metadata so order-service returns the original order on a retry.
+func (c *Client) CreateOrder(ctx context.Context, userID string, items []*order.OrderItem, idempotencyKey string) (*order.CreateOrderResponse, error) {
 	const op = "grpc.CreateOrder"
 
+	if idempotencyKey != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "idempotency-key", idempotencyKey)
+	}
 	resp, err := c.api.CreateOrder(ctx, &order.CreateOrderRequest{
 		UserId: userID,
 		Items:  items,
@@ -75,10 +90,54 @@ func (c *Client) DeleteOrder(ctx context.Context, orderID uuid.UUID) error {
 	return nil
 }
 
-func (c *Client) GetOrder(ctx context.Context, orderID string) (*order.OrderResponse, error) {
+func (c *Client) GetOrder(ctx context.Context, orderID string) (*orderpb.Order, error) {
 	const op = "grpc.GetOrder"
 
-	resp, err := c.api.Order(ctx, &order.OrderRequest{
+	resp, err := c.query.GetOrder(ctx, &orderpb.GetOrderRequest{
+		OrderId: orderID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return resp, nil
+}
+
+func (c *Client) ListOrders(ctx context.Context, req *orderpb.ListOrdersRequest) (*orderpb.ListOrdersResponse, error) {
+	const op = "grpc.ListOrders"
+
+	resp, err := c.query.ListOrders(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return resp, nil
+}
+
+func (c *Client) RestoreOrder(ctx context.Context, orderID string) (*orderpb.Order, error) {
+	const op = "grpc.RestoreOrder"
+
+	resp, err := c.admin.RestoreOrder(ctx, &orderpb.RestoreOrderRequest{
+		OrderId: orderID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return resp, nil
+}
+
+func (c *Client) RefundOrder(ctx context.Context, req *orderpb.RefundOrderRequest) (*orderpb.Refund, error) {
+	const op = "grpc.RefundOrder"
+
+	resp, err := c.admin.RefundOrder(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return resp, nil
+}
+
+func (c *Client) ListRefunds(ctx context.Context, orderID string) (*orderpb.ListRefundsResponse, error) {
+	const op = "grpc.ListRefunds"
+
+	resp, err := c.admin.ListRefunds(ctx, &orderpb.ListRefundsRequest{
 		OrderId: orderID,
 	})
 	if err != nil {
@@ -87,13 +146,110 @@ func (c *Client) GetOrder(ctx context.Context, orderID string) (*order.OrderResp
 	return resp, nil
 }
 
-func (c *Client) ListOrdersByUser(ctx context.Context, userID string, limit, offset int32) (*order.ListOrdersResponse, error) {
-	const op = "grpc.ListOrdersByUser"
+// WatchOrder opens a stream of the order's status changes. It lasts until ctx
+// is cancelled or the order reaches a terminal status.
+func (c *Client) WatchOrder(ctx context.Context, orderID string) (grpc.ServerStreamingClient[orderpb.OrderStatusEvent], error) {
+	const op = "grpc.WatchOrder"
+
+	stream, err := c.query.WatchOrder(ctx, &orderpb.WatchOrderRequest{
+		OrderId: orderID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return stream, nil
+}
+
+func (c *Client) UpdateOrderStatus(ctx context.Context, orderID string, status orderpb.OrderStatus, actor orderpb.StatusActor, actorID, reason string) (*orderpb.UpdateOrderStatusResponse, error) {
+	const op = "grpc.UpdateOrderStatus"
+
+	resp, err := c.status.UpdateOrderStatus(ctx, &orderpb.UpdateOrderStatusRequest{
+		OrderId: orderID,
+		Status:  status,
+		Actor:   actor,
+		ActorId: actorID,
+		Reason:  reason,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return resp, nil
+}
+
+func (c *Client) OrderStatusHistory(ctx context.Context, orderID string) (*orderpb.OrderStatusHistoryResponse, error) {
+	const op = "grpc.OrderStatusHistory"
+
+	resp, err := c.status.OrderStatusHistory(ctx, &orderpb.OrderStatusHistoryRequest{
+		OrderId: orderID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return resp, nil
+}
+
+func (c *Client) GetCart(ctx context.Context, owner *orderpb.CartOwner) (*orderpb.Cart, error) {
+	const op = "grpc.GetCart"
+
+	resp, err := c.cart.GetCart(ctx, &orderpb.GetCartRequest{
+		Owner: owner,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return resp, nil
+}
+
+func (c *Client) AddCartItem(ctx context.Context, owner *orderpb.CartOwner, productID string, modifierIDs []string, quantity int32) (*orderpb.Cart, error) {
+	const op = "grpc.AddCartItem"
+
+	resp, err := c.cart.AddCartItem(ctx, &orderpb.AddCartItemRequest{
+		Owner:       owner,
+		ProductId:   productID,
+		Quantity:    quantity,
+		ModifierIds: modifierIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return resp, nil
+}
+
+func (c *Client) UpdateCartItem(ctx context.Context, owner *orderpb.CartOwner, productID string, modifierIDs []string, quantity int32) (*orderpb.Cart, error) {
+	const op = "grpc.UpdateCartItem"
+
+	resp, err := c.cart.UpdateCartItem(ctx, &orderpb.UpdateCartItemRequest{
+		Owner:       owner,
+		ProductId:   productID,
+		Quantity:    quantity,
+		ModifierIds: modifierIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return resp, nil
+}
+
+func (c *Client) RemoveCartItem(ctx context.Context, owner *orderpb.CartOwner, productID string, modifierIDs []string) (*orderpb.Cart, error) {
+	const op = "grpc.RemoveCartItem"
+
+	resp, err := c.cart.RemoveCartItem(ctx, &orderpb.RemoveCartItemRequest{
+		Owner:       owner,
+		ProductId:   productID,
+		ModifierIds: modifierIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return resp, nil
+}
+
+func (c *Client) MergeCarts(ctx context.Context, cartID, userID string) (*orderpb.Cart, error) {
+	const op = "grpc.MergeCarts"
 
-	resp, err := c.api.ListOrders(ctx, &order.ListOrdersRequest{
+	resp, err := c.cart.MergeCarts(ctx, &orderpb.MergeCartsRequest{
+		CartId: cartID,
 		UserId: userID,
-		Limit:  limit,
-		Offset: offset,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -101,12 +257,12 @@ func (c *Client) ListOrdersByUser(ctx context.Context, userID string, limit, off
 	return resp, nil
 }
 
-func (c *Client) ListAllOrders(ctx context.Context, limit, offset int32) (*order.ListOrdersResponse, error) {
-	const op = "grpc.ListAllOrders"
+func (c *Client) CheckoutCart(ctx context.Context, userID, idempotencyKey string) (*orderpb.CheckoutCartResponse, error) {
+	const op = "grpc.CheckoutCart"
 
-	resp, err := c.api.ListAllOrders(ctx, &order.ListAllOrdersRequest{
-		Limit:  limit,
-		Offset: offset,
+	resp, err := c.cart.CheckoutCart(ctx, &orderpb.CheckoutCartRequest{
+		UserId:         userID,
+		IdempotencyKey: idempotencyKey,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -9,9 +9,16 @@ import (
 )
 
 type Config struct {
-	Env      string        `yaml:"env" env-default:"local"`
-	Clients  ClientsConfig `yaml:"clients"`
-	TokenTTL time.Duration `yaml:"token_ttl" env-default:"1h"`
+	Env             string        `yaml:"env" env-default:"local"`
+	Clients         ClientsConfig `yaml:"clients"`
+	TokenTTL        time.Duration `yaml:"token_ttl" env-default:"1h"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env-default:"20s"`
+	Kafka           KafkaConfig   `yaml:"kafka"`
+}
+
+type KafkaConfig struct {
+	Partitions        int `yaml:"partitions" env-default:"6"`
+	ReplicationFactor int `yaml:"replication_factor" env-default:"1"`
 }
 
 type Client struct {
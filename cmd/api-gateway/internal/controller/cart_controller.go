@@ -0,0 +1,232 @@
+package controller
+
+import (
+	ordergrpc "immxrtalbeast/order_microservices/api-gateway/internal/clients/order"
+	"immxrtalbeast/order_microservices/internal/pkg/orderpb"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// cartCookie holds the ID of an anonymous visitor's cart. It is merged into
+// the user's cart on login.
+const cartCookie = "cart_id"
+
+type CartController struct {
+	orderService *ordergrpc.Client
+}
+
+func NewCartController(orderService *ordergrpc.Client) *CartController {
+	return &CartController{orderService: orderService}
+}
+
+func (c *CartController) GetCart(ctx *gin.Context) {
+	cart, err := c.orderService.GetCart(ctx, cartOwner(ctx))
+	if err != nil {
+		writeStatusError(ctx, "failed to get cart", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, newCartView(cart))
+}
+
+// AddItem adds a product with a choice of modifiers to the cart. Anonymous
+// visitors get a cart on their first add, remembered in the cart_id cookie.
+func (c *CartController) AddItem(ctx *gin.Context) {
+	type request struct {
+		ProductID   string   `json:"product_id" binding:"required"`
+		ModifierIDs []string `json:"modifier_ids"`
+		Quantity    int32    `json:"quantity" binding:"required,min=1"`
+	}
+	var req request
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+	if _, err := uuid.Parse(req.ProductID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid product ID format"})
+		return
+	}
+	if !validIDs(req.ModifierIDs) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid modifier ID format"})
+		return
+	}
+
+	cart, err := c.orderService.AddCartItem(ctx, cartOwner(ctx), req.ProductID, req.ModifierIDs, req.Quantity)
+	if err != nil {
+		writeStatusError(ctx, "failed to add cart item", err)
+		return
+	}
+	writeCart(ctx, cart)
+}
+
+// UpdateItem sets the quantity of the cart line of a product and its
+// modifier_ids; 0 removes it.
+func (c *CartController) UpdateItem(ctx *gin.Context) {
+	productID := ctx.Param("product_id")
+	if _, err := uuid.Parse(productID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid product ID format"})
+		return
+	}
+	type request struct {
+		ModifierIDs []string `json:"modifier_ids"`
+		Quantity    *int32   `json:"quantity" binding:"required,min=0"`
+	}
+	var req request
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+	if !validIDs(req.ModifierIDs) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid modifier ID format"})
+		return
+	}
+
+	cart, err := c.orderService.UpdateCartItem(ctx, cartOwner(ctx), productID, req.ModifierIDs, *req.Quantity)
+	if err != nil {
+		writeStatusError(ctx, "failed to update cart item", err)
+		return
+	}
+	writeCart(ctx, cart)
+}
+
+// RemoveItem removes the cart line of a product and the modifier_ids query
+// parameters.
+func (c *CartController) RemoveItem(ctx *gin.Context) {
+	productID := ctx.Param("product_id")
+	if _, err := uuid.Parse(productID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid product ID format"})
+		return
+	}
+	modifierIDs := ctx.QueryArray("modifier_ids")
+	if !validIDs(modifierIDs) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid modifier ID format"})
+		return
+	}
+
+	cart, err := c.orderService.RemoveCartItem(ctx, cartOwner(ctx), productID, modifierIDs)
+	if err != nil {
+		writeStatusError(ctx, "failed to remove cart item", err)
+		return
+	}
+	writeCart(ctx, cart)
+}
+
+// Checkout places an order for the user's cart. Like CreateOrder it honours
+// an Idempotency-Key header.
+func (c *CartController) Checkout(ctx *gin.Context) {
+	userID, _ := ctx.Get("userID")
+	userIDStr, _ := userID.(string)
+	if userIDStr == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "log in to check out"})
+		return
+	}
+	idempotencyKey := ctx.GetHeader("Idempotency-Key")
+	if len(idempotencyKey) > maxIdempotencyKeyLen {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key must be at most 255 characters"})
+		return
+	}
+
+	resp, err := c.orderService.CheckoutCart(ctx, userIDStr, idempotencyKey)
+	if err != nil {
+		writeStatusError(ctx, "failed to check out cart", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"order_id": resp.GetOrderId(),
+		"status":   statusName(resp.GetStatus()),
+	})
+}
+
+// cartOwner picks the user's cart for a logged in request and the cart in the
+// cart_id cookie otherwise.
+func cartOwner(ctx *gin.Context) *orderpb.CartOwner {
+	userID, _ := ctx.Get("userID")
+	if userIDStr, _ := userID.(string); userIDStr != "" {
+		return &orderpb.CartOwner{UserId: userIDStr}
+	}
+	cartID, _ := ctx.Cookie(cartCookie)
+	return &orderpb.CartOwner{CartId: cartID}
+}
+
+func validIDs(ids []string) bool {
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// writeCart answers with cart and, for an anonymous visitor, keeps the
+// cart_id cookie pointing at it until the cart expires.
+func writeCart(ctx *gin.Context, cart *orderpb.Cart) {
+	if cart.GetUserId() == "" && cart.GetId() != "" {
+		ctx.SetSameSite(http.SameSiteLaxMode)
+		ctx.SetCookie(
+			cartCookie,
+			cart.GetId(),
+			int(time.Until(cart.GetExpiresAt().AsTime()).Seconds()),
+			"/",
+			"",
+			os.Getenv("COOKIE_SECURE") == "true",
+			true,
+		)
+	}
+	ctx.JSON(http.StatusOK, newCartView(cart))
+}
+
+type cartItemView struct {
+	ProductID         string                      `json:"product_id"`
+	Quantity          int32                       `json:"quantity"`
+	Name              string                      `json:"name,omitempty"`
+	Volume            int32                       `json:"volume,omitempty"`
+	Modifiers         []*orderpb.CartItemModifier `json:"modifiers,omitempty"`
+	UnitPrice         *orderpb.Money              `json:"unit_price,omitempty"`
+	LineTotal         *orderpb.Money              `json:"line_total,omitempty"`
+	AvailableQuantity int64                       `json:"available_quantity"`
+	Availability      string                      `json:"availability"`
+}
+
+type cartView struct {
+	ID            string         `json:"id,omitempty"`
+	UserID        string         `json:"user_id,omitempty"`
+	Items         []cartItemView `json:"items"`
+	Subtotal      *orderpb.Money `json:"subtotal"`
+	CheckoutReady bool           `json:"checkout_ready"`
+	UpdatedAt     *time.Time     `json:"updated_at,omitempty"`
+	ExpiresAt     *time.Time     `json:"expires_at,omitempty"`
+}
+
+func newCartView(cart *orderpb.Cart) cartView {
+	view := cartView{
+		ID:            cart.GetId(),
+		UserID:        cart.GetUserId(),
+		Items:         make([]cartItemView, len(cart.GetItems())),
+		Subtotal:      cart.GetSubtotal(),
+		CheckoutReady: cart.GetCheckoutReady(),
+	}
+	for i, item := range cart.GetItems() {
+		view.Items[i] = cartItemView{
+			ProductID:         item.GetProductId(),
+			Quantity:          item.GetQuantity(),
+			Name:              item.GetName(),
+			Volume:            item.GetVolume(),
+			Modifiers:         item.GetModifiers(),
+			AvailableQuantity: item.GetAvailableQuantity(),
+			Availability:      strings.TrimPrefix(item.GetAvailability().String(), "CART_ITEM_AVAILABILITY_"),
+		}
+		if item.GetAvailability() != orderpb.CartItemAvailability_CART_ITEM_AVAILABILITY_UNAVAILABLE {
+			view.Items[i].UnitPrice = item.GetUnitPrice()
+			view.Items[i].LineTotal = item.GetLineTotal()
+		}
+	}
+	if cart.GetUpdatedAt() != nil {
+		updatedAt, expiresAt := cart.GetUpdatedAt().AsTime(), cart.GetExpiresAt().AsTime()
+		view.UpdatedAt, view.ExpiresAt = &updatedAt, &expiresAt
+	}
+	return view
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"errors"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	mykafka "immxrtalbeast/order_microservices/internal/pkg/kafka"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// dlqTopics are the topics whose dead letters may be inspected and re-driven.
+var dlqTopics = map[string]bool{
+	"saga-commands": true,
+	"saga-replies":  true,
+}
+
+type DLQController struct {
+	deadLetters *mykafka.DeadLetterQueue
+}
+
+func NewDLQController(deadLetters *mykafka.DeadLetterQueue) *DLQController {
+	return &DLQController{deadLetters: deadLetters}
+}
+
+func (c *DLQController) ListDeadLetters(ctx *gin.Context) {
+	topic := ctx.Param("topic")
+	if !dlqTopics[topic] {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "unknown topic"})
+		return
+	}
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 500 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 500"})
+		return
+	}
+
+	letters, err := c.deadLetters.List(ctx, topic, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list dead letters", "details": err.Error()})
+		return
+	}
+	for i := range letters {
+		letters[i] = readable(letters[i])
+	}
+	ctx.JSON(http.StatusOK, gin.H{"dead_letters": letters})
+}
+
+func (c *DLQController) RedriveDeadLetter(ctx *gin.Context) {
+	topic := ctx.Param("topic")
+	if !dlqTopics[topic] {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "unknown topic"})
+		return
+	}
+	type request struct {
+		Partition *int   `json:"partition" binding:"required,min=0"`
+		Offset    *int64 `json:"offset" binding:"required,min=0"`
+	}
+	var req request
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := c.deadLetters.Redrive(ctx, topic, *req.Partition, *req.Offset); err != nil {
+		if errors.Is(err, mykafka.ErrDeadLetterNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "dead letter not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to redrive dead letter", "details": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusAccepted, gin.H{"message": "dead letter re-driven", "topic": topic})
+}
+
+// readable re-encodes a protobuf payload as JSON so it can be inspected. The
+// letter keeps its original content type; redrive republishes the stored bytes.
+func readable(letter mykafka.DeadLetter) mykafka.DeadLetter {
+	if letter.ContentType != events.ContentTypeProtobuf {
+		return letter
+	}
+	env, err := events.Unmarshal([]byte(letter.Payload), letter.ContentType, letter.EventType)
+	if err != nil {
+		return letter
+	}
+	payload, err := events.Marshal(env, events.ContentTypeJSON)
+	if err != nil {
+		return letter
+	}
+	letter.Payload = string(payload)
+	return letter
+}
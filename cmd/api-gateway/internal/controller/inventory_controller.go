@@ -1,16 +1,22 @@
 package controller
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	inventorygrpc "immxrtalbeast/order_microservices/api-gateway/internal/clients/inventory"
 	"immxrtalbeast/order_microservices/api-gateway/internal/lib"
+	"immxrtalbeast/order_microservices/internal/pkg/inventorypb"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	inventory "github.com/ozzus/order_protos/gen/go/inventory"
 )
 
 type InventoryController struct {
@@ -30,7 +36,7 @@ func (c *InventoryController) AddGood(ctx *gin.Context) {
 		Category        string `form:"category" binding:"required"`
 		Description     string `form:"description"`
 		Volume          int    `form:"volume" binding:"required,min=1"`
-		Price           int    `form:"price" binding:"required,min=1"`
+		Price           string `form:"price" binding:"required"`
 		QuantityInStock int    `form:"quantity_in_stock" binding:"min=0"`
 	}
 
@@ -42,6 +48,11 @@ func (c *InventoryController) AddGood(ctx *gin.Context) {
 		})
 		return
 	}
+	price, err := parsePrice(req.Price)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid price", "details": err.Error()})
+		return
+	}
 	file, err := ctx.FormFile("image")
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
@@ -95,7 +106,7 @@ func (c *InventoryController) AddGood(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.inventoryService.AddGood(ctx, req.Name, req.Category, req.Description, publicURL, req.Price, req.QuantityInStock, int32(req.Volume)); err != nil {
+	if err := c.inventoryService.AddGood(ctx, req.Name, req.Category, req.Description, publicURL, price, req.QuantityInStock, int32(req.Volume)); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"error":   "failed to add good",
 			"details": err.Error(),
@@ -126,6 +137,8 @@ func (c *InventoryController) DeleteGood(ctx *gin.Context) {
 	})
 }
 
+// ListGoods returns the goods with their held and available stock and the
+// modifier groups each is offered with.
 func (c *InventoryController) ListGoods(ctx *gin.Context) {
 	goods, err := c.inventoryService.ListProducts(ctx)
 	if err != nil {
@@ -135,19 +148,60 @@ func (c *InventoryController) ListGoods(ctx *gin.Context) {
 		})
 		return
 	}
+	stock, err := c.inventoryService.ListStock(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "failed to get stock of goods",
+			"details": err.Error(),
+		})
+		return
+	}
+	stockByGood := make(map[string]*inventorypb.GoodStock, len(stock))
+	for _, good := range stock {
+		stockByGood[good.GetGoodId()] = good
+	}
+	modifiers, err := c.inventoryService.ListGoodModifiers(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "failed to get modifiers of goods",
+			"details": err.Error(),
+		})
+		return
+	}
+	groupsByGood := make(map[string][]*inventorypb.ModifierGroup, len(modifiers))
+	for _, good := range modifiers {
+		groupsByGood[good.GetGoodId()] = good.GetGroups()
+	}
+	views := make([]goodView, len(goods))
+	for i, good := range goods {
+		views[i] = goodView{
+			Product:           good,
+			QuantityHeld:      stockByGood[good.GetId()].GetQuantityHeld(),
+			QuantityAvailable: stockByGood[good.GetId()].GetQuantityAvailable(),
+			ModifierGroups:    newModifierGroupViews(groupsByGood[good.GetId()]),
+		}
+	}
 	ctx.JSON(http.StatusOK, gin.H{
-		"goods": goods,
+		"goods": views,
 	})
 }
+
+type goodView struct {
+	*inventory.Product
+	QuantityHeld      int64               `json:"quantity_held"`
+	QuantityAvailable int64               `json:"quantity_available"`
+	ModifierGroups    []modifierGroupView `json:"modifier_groups"`
+}
+
 func (c *InventoryController) UpdateGood(ctx *gin.Context) {
 	type UpdateGoodRequest struct {
-		ID              string `json:"id" binding:"required"`
-		Name            string `json:"name" binding:"required"`
-		Category        string `json:"category" binding:"required"`
-		Description     string `json:"description"`
-		Price           int    `json:"price" binding:"required,min=1"`
-		ImageLink       string `json:"image_link"`
-		QuantityInStock int    `json:"quantity_in_stock" binding:"min=0"`
+		ID              string      `json:"id" binding:"required"`
+		Name            string      `json:"name" binding:"required"`
+		Category        string      `json:"category" binding:"required"`
+		Description     string      `json:"description"`
+		Price           json.Number `json:"price" binding:"required"`
+		ImageLink       string      `json:"image_link"`
+		QuantityInStock int         `json:"quantity_in_stock" binding:"min=0"`
 	}
 	var req UpdateGoodRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
@@ -162,7 +216,12 @@ func (c *InventoryController) UpdateGood(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid good ID format"})
 		return
 	}
-	if err := c.inventoryService.UpdateGood(ctx, parsedGoodID, req.Name, req.Category, req.Description, req.ImageLink, req.Price, req.QuantityInStock); err != nil {
+	price, err := parsePrice(req.Price.String())
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid price", "details": err.Error()})
+		return
+	}
+	if err := c.inventoryService.UpdateGood(ctx, parsedGoodID, req.Name, req.Category, req.Description, req.ImageLink, price, req.QuantityInStock); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"error":   "failed to update good",
 			"details": err.Error(),
@@ -173,3 +232,182 @@ func (c *InventoryController) UpdateGood(ctx *gin.Context) {
 		"message": "good updated successfully",
 	})
 }
+
+// parsePrice reads a decimal price in major units, e.g. "1299.90".
+func parsePrice(s string) (money.Money, error) {
+	price, err := money.Parse(s, money.DefaultCurrency)
+	if err != nil {
+		return money.Money{}, err
+	}
+	if price.Amount <= 0 {
+		return money.Money{}, errors.New("price should be greater than 0")
+	}
+	return price, nil
+}
+
+func (c *InventoryController) ListModifierGroups(ctx *gin.Context) {
+	groups, err := c.inventoryService.ListModifierGroups(ctx)
+	if err != nil {
+		writeStatusError(ctx, "failed to get modifier groups", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"groups": newModifierGroupViews(groups),
+	})
+}
+
+func (c *InventoryController) CreateModifierGroup(ctx *gin.Context) {
+	c.saveModifierGroup(ctx, "")
+}
+
+// UpdateModifierGroup replaces a modifier group. Modifiers sent with their id
+// are kept, the ones left out are removed.
+func (c *InventoryController) UpdateModifierGroup(ctx *gin.Context) {
+	groupID := ctx.Param("id")
+	if _, err := uuid.Parse(groupID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid modifier group ID format"})
+		return
+	}
+	c.saveModifierGroup(ctx, groupID)
+}
+
+func (c *InventoryController) saveModifierGroup(ctx *gin.Context, groupID string) {
+	type ModifierRequest struct {
+		ID              string      `json:"id"`
+		Name            string      `json:"name" binding:"required"`
+		PriceDelta      json.Number `json:"price_delta"`
+		TrackStock      bool        `json:"track_stock"`
+		QuantityInStock int64       `json:"quantity_in_stock" binding:"min=0"`
+	}
+	type ModifierGroupRequest struct {
+		Name          string            `json:"name" binding:"required"`
+		SelectionType string            `json:"selection_type" binding:"required,oneof=SINGLE MULTIPLE"`
+		MinSelected   int32             `json:"min_selected" binding:"min=0"`
+		MaxSelected   int32             `json:"max_selected" binding:"min=1"`
+		Modifiers     []ModifierRequest `json:"modifiers" binding:"required,min=1,dive"`
+	}
+	var req ModifierGroupRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	group := &inventorypb.ModifierGroup{
+		Id:            groupID,
+		Name:          req.Name,
+		SelectionType: inventorypb.SelectionType(inventorypb.SelectionType_value["SELECTION_TYPE_"+req.SelectionType]),
+		MinSelected:   req.MinSelected,
+		MaxSelected:   req.MaxSelected,
+		Modifiers:     make([]*inventorypb.Modifier, len(req.Modifiers)),
+	}
+	for i, modifier := range req.Modifiers {
+		if modifier.ID != "" {
+			if _, err := uuid.Parse(modifier.ID); err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid modifier ID format"})
+				return
+			}
+		}
+		priceDelta := money.New(0, money.DefaultCurrency)
+		if modifier.PriceDelta != "" {
+			var err error
+			if priceDelta, err = money.Parse(modifier.PriceDelta.String(), money.DefaultCurrency); err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid price_delta", "details": err.Error()})
+				return
+			}
+		}
+		group.Modifiers[i] = &inventorypb.Modifier{
+			Id:              modifier.ID,
+			Name:            modifier.Name,
+			PriceDelta:      &inventorypb.Money{Amount: priceDelta.Amount, Currency: priceDelta.Currency},
+			TrackStock:      modifier.TrackStock,
+			QuantityInStock: modifier.QuantityInStock,
+		}
+	}
+
+	saved, err := c.inventoryService.SaveModifierGroup(ctx, group)
+	if err != nil {
+		writeStatusError(ctx, "failed to save modifier group", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, newModifierGroupViews([]*inventorypb.ModifierGroup{saved})[0])
+}
+
+// SetGoodModifierGroups replaces the modifier groups a good is offered with,
+// in the order given.
+func (c *InventoryController) SetGoodModifierGroups(ctx *gin.Context) {
+	parsedGoodID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid good ID format"})
+		return
+	}
+	type SetModifierGroupsRequest struct {
+		GroupIDs []string `json:"group_ids" binding:"required"`
+	}
+	var req SetModifierGroupsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	if !validIDs(req.GroupIDs) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid modifier group ID format"})
+		return
+	}
+
+	good, err := c.inventoryService.SetGoodModifierGroups(ctx, parsedGoodID, req.GroupIDs)
+	if err != nil {
+		writeStatusError(ctx, "failed to set modifier groups", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"good_id":         good.GetGoodId(),
+		"modifier_groups": newModifierGroupViews(good.GetGroups()),
+	})
+}
+
+type modifierView struct {
+	ID                string             `json:"id"`
+	Name              string             `json:"name"`
+	PriceDelta        *inventorypb.Money `json:"price_delta"`
+	TrackStock        bool               `json:"track_stock"`
+	QuantityInStock   int64              `json:"quantity_in_stock,omitempty"`
+	QuantityAvailable int64              `json:"quantity_available,omitempty"`
+}
+
+type modifierGroupView struct {
+	ID            string         `json:"id"`
+	Name          string         `json:"name"`
+	SelectionType string         `json:"selection_type"`
+	MinSelected   int32          `json:"min_selected"`
+	MaxSelected   int32          `json:"max_selected"`
+	Modifiers     []modifierView `json:"modifiers"`
+}
+
+func newModifierGroupViews(groups []*inventorypb.ModifierGroup) []modifierGroupView {
+	views := make([]modifierGroupView, len(groups))
+	for i, group := range groups {
+		views[i] = modifierGroupView{
+			ID:            group.GetId(),
+			Name:          group.GetName(),
+			SelectionType: strings.TrimPrefix(group.GetSelectionType().String(), "SELECTION_TYPE_"),
+			MinSelected:   group.GetMinSelected(),
+			MaxSelected:   group.GetMaxSelected(),
+			Modifiers:     make([]modifierView, len(group.GetModifiers())),
+		}
+		for j, modifier := range group.GetModifiers() {
+			views[i].Modifiers[j] = modifierView{
+				ID:                modifier.GetId(),
+				Name:              modifier.GetName(),
+				PriceDelta:        modifier.GetPriceDelta(),
+				TrackStock:        modifier.GetTrackStock(),
+				QuantityInStock:   modifier.GetQuantityInStock(),
+				QuantityAvailable: modifier.GetQuantityAvailable(),
+			}
+		}
+	}
+	return views
+}
@@ -1,33 +1,34 @@
 package controller
 
 import (
-	"context"
 	"errors"
 	ordergrpc "immxrtalbeast/order_microservices/api-gateway/internal/clients/order"
+	"immxrtalbeast/order_microservices/internal/pkg/orderpb"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	order "github.com/ozzus/order_protos/gen/go/order"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	mykafka "github.com/ozzus/order_kafka"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type OrderController struct {
 	orderService *ordergrpc.Client
-	producer     *mykafka.Producer
+	closing      chan struct{}
+	closeOnce    sync.Once
 }
 
-type orderStatusUpdateCommand struct {
-	OrderID uuid.UUID `json:"order_id"`
-	Status  string    `json:"status"`
+func NewOrderController(orderService *ordergrpc.Client) *OrderController {
+	return &OrderController{orderService: orderService, closing: make(chan struct{})}
 }
 
-func NewOrderController(orderService *ordergrpc.Client, producer *mykafka.Producer) *OrderController {
-	return &OrderController{orderService: orderService, producer: producer}
-}
+const maxIdempotencyKeyLen = 255
 
 func (c *OrderController) CreateOrder(ctx *gin.Context) {
 	type OrderItem struct {
@@ -50,6 +51,12 @@ func (c *OrderController) CreateOrder(ctx *gin.Context) {
 		return
 	}
 
+	idempotencyKey := ctx.GetHeader("Idempotency-Key")
+	if len(idempotencyKey) > maxIdempotencyKeyLen {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key must be at most 255 characters"})
+		return
+	}
+
 	var req CreateOrderRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
@@ -68,9 +75,9 @@ func (c *OrderController) CreateOrder(ctx *gin.Context) {
 		}
 	}
 
-	resp, err := c.orderService.CreateOrder(ctx, userIDStr, items)
+	resp, err := c.orderService.CreateOrder(ctx, userIDStr, items, idempotencyKey)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create order", "details": err.Error()})
+		writeStatusError(ctx, "failed to create order", err)
 		return
 	}
 
@@ -92,10 +99,7 @@ func (c *OrderController) DeleteOrder(ctx *gin.Context) {
 		return
 	}
 	if err := c.orderService.DeleteOrder(ctx, parsedOrderID); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error":   "failed to delete order",
-			"details": err.Error(),
-		})
+		writeStatusError(ctx, "failed to delete order", err)
 		return
 	}
 	ctx.JSON(http.StatusOK, gin.H{
@@ -103,6 +107,8 @@ func (c *OrderController) DeleteOrder(ctx *gin.Context) {
 	})
 }
 
+// CancelOrder asks the saga to cancel the order. Its stock is released first,
+// so the order answers CANCELLING and turns CANCELLED shortly after.
 func (c *OrderController) CancelOrder(ctx *gin.Context) {
 	orderID := ctx.Param("id")
 	if _, err := uuid.Parse(orderID); err != nil {
@@ -114,25 +120,36 @@ func (c *OrderController) CancelOrder(ctx *gin.Context) {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
 	}
-	orderResp, err := c.orderService.GetOrder(ctx, orderID)
-	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "order not found", "details": err.Error()})
-		return
-	}
 	userIDStr, ok := userID.(string)
 	if !ok || userIDStr == "" {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user token"})
 		return
 	}
-	if orderResp.Order.GetUserId() != userIDStr {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "order belongs to another user"})
-		return
+	actor, reason := orderpb.StatusActor_STATUS_ACTOR_USER, "cancelled by user"
+	if c.isAdmin(ctx) {
+		actor, reason = orderpb.StatusActor_STATUS_ACTOR_ADMIN, "cancelled by admin"
+	} else {
+		orderResp, err := c.orderService.GetOrder(ctx, orderID)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "order not found", "details": err.Error()})
+			return
+		}
+		if orderResp.GetUserId() != userIDStr {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "order belongs to another user"})
+			return
+		}
 	}
-	if err := c.publishStatus(ctx, orderID, "CANCELLED"); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to cancel order", "details": err.Error()})
+	resp, err := c.orderService.UpdateOrderStatus(ctx, orderID, orderpb.OrderStatus_ORDER_STATUS_CANCELLED,
+		actor, userIDStr, reason)
+	if err != nil {
+		writeStatusError(ctx, "failed to cancel order", err)
 		return
 	}
-	ctx.JSON(http.StatusAccepted, gin.H{"message": "order cancellation accepted", "status": "CANCELLED"})
+	code := http.StatusOK
+	if resp.Status == orderpb.OrderStatus_ORDER_STATUS_CANCELLING {
+		code = http.StatusAccepted
+	}
+	ctx.JSON(code, gin.H{"message": "order cancellation requested", "status": statusName(resp.Status)})
 }
 
 func (c *OrderController) UpdateOrderStatus(ctx *gin.Context) {
@@ -143,49 +160,65 @@ func (c *OrderController) UpdateOrderStatus(ctx *gin.Context) {
 	}
 	type request struct {
 		Status string `json:"status" binding:"required"`
+		Reason string `json:"reason"`
 	}
 	var req request
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
 		return
 	}
-	status, err := normalizeOrderStatus(req.Status)
+	orderStatus, err := parseOrderStatus(req.Status)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if err := c.publishStatus(ctx, orderID, status); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update order status", "details": err.Error()})
+	adminID, _ := ctx.Get("userID")
+	adminIDStr, _ := adminID.(string)
+	resp, err := c.orderService.UpdateOrderStatus(ctx, orderID, orderStatus,
+		orderpb.StatusActor_STATUS_ACTOR_ADMIN, adminIDStr, req.Reason)
+	if err != nil {
+		writeStatusError(ctx, "failed to update order status", err)
 		return
 	}
-	ctx.JSON(http.StatusAccepted, gin.H{"message": "order status update accepted", "status": status})
+	ctx.JSON(http.StatusOK, gin.H{"message": "order status updated", "status": statusName(resp.Status)})
 }
 
-func (c *OrderController) publishStatus(ctx context.Context, orderID string, status string) error {
-	uid, err := uuid.Parse(orderID)
-	if err != nil {
-		return err
+const statusPrefix = "ORDER_STATUS_"
+
+// statusAliases maps legacy and alternative spellings onto the order statuses.
+var statusAliases = map[string]string{
+	"CANCELED":   "CANCELLED",
+	"PROCESSING": "PREPARING",
+}
+
+func parseOrderStatus(raw string) (orderpb.OrderStatus, error) {
+	name := strings.ToUpper(strings.TrimSpace(raw))
+	if alias, ok := statusAliases[name]; ok {
+		name = alias
 	}
-	if c.producer == nil {
-		return errors.New("order status producer is not configured")
+	value, ok := orderpb.OrderStatus_value[statusPrefix+name]
+	if !ok || value == int32(orderpb.OrderStatus_ORDER_STATUS_UNSPECIFIED) {
+		return 0, errors.New("unsupported order status")
 	}
-	return c.producer.PublishEventWithEventType(ctx, "OrderStatusUpdateCommand", orderStatusUpdateCommand{
-		OrderID: uid,
-		Status:  status,
-	}, "OrderStatusUpdateCommand")
+	return orderpb.OrderStatus(value), nil
 }
 
-func normalizeOrderStatus(status string) (string, error) {
-	switch strings.ToUpper(strings.TrimSpace(status)) {
-	case "PROCESSING", "PREPARING":
-		return "PROCESSING", nil
-	case "COMPLETED", "READY":
-		return "COMPLETED", nil
-	case "CANCELLED", "CANCELED":
-		return "CANCELLED", nil
-	default:
-		return "", errors.New("unsupported order status")
-	}
+func statusName(s orderpb.OrderStatus) string {
+	return strings.TrimPrefix(s.String(), statusPrefix)
+}
+
+// writeStatusError translates a failed order-service call into an HTTP response.
+func writeStatusError(ctx *gin.Context, message string, err error) {
+	code := http.StatusInternalServerError
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		code = http.StatusBadRequest
+	case codes.NotFound:
+		code = http.StatusNotFound
+	case codes.FailedPrecondition, codes.AlreadyExists:
+		code = http.StatusConflict
+	}
+	ctx.JSON(code, gin.H{"error": message, "details": err.Error()})
 }
 
 func (c *OrderController) GetOrder(ctx *gin.Context) {
@@ -208,55 +241,196 @@ func (c *OrderController) GetOrder(ctx *gin.Context) {
 			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user token"})
 			return
 		}
-		if resp.Order.GetUserId() != userIDStr {
+		if resp.GetUserId() != userIDStr {
 			ctx.JSON(http.StatusForbidden, gin.H{"error": "order belongs to another user"})
 			return
 		}
 	}
 
-	ctx.JSON(http.StatusOK, resp.Order)
+	ctx.JSON(http.StatusOK, resp)
 }
 
-func (c *OrderController) ListOrders(ctx *gin.Context) {
-	userID, exists := ctx.Get("userID")
-	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+func (c *OrderController) OrderStatusHistory(ctx *gin.Context) {
+	orderID := ctx.Param("id")
+	if _, err := uuid.Parse(orderID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID format"})
+		return
+	}
+	if !c.canAccessOrder(ctx, orderID) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "order belongs to another user"})
 		return
 	}
 
-	limit, offset, ok := parsePagination(ctx, 10, 100)
-	if !ok {
+	resp, err := c.orderService.OrderStatusHistory(ctx, orderID)
+	if err != nil {
+		writeStatusError(ctx, "failed to get order status history", err)
 		return
 	}
 
+	type historyEntry struct {
+		From      string    `json:"from,omitempty"`
+		To        string    `json:"to"`
+		Actor     string    `json:"actor"`
+		ActorID   string    `json:"actor_id,omitempty"`
+		Reason    string    `json:"reason,omitempty"`
+		ChangedAt time.Time `json:"changed_at"`
+	}
+	history := make([]historyEntry, len(resp.Entries))
+	for i, e := range resp.Entries {
+		history[i] = historyEntry{
+			To:        statusName(e.GetTo()),
+			Actor:     strings.ToLower(strings.TrimPrefix(e.GetActor().String(), "STATUS_ACTOR_")),
+			ActorID:   e.GetActorId(),
+			Reason:    e.GetReason(),
+			ChangedAt: e.GetChangedAt().AsTime(),
+		}
+		if e.GetFrom() != orderpb.OrderStatus_ORDER_STATUS_UNSPECIFIED {
+			history[i].From = statusName(e.GetFrom())
+		}
+	}
+	ctx.JSON(http.StatusOK, gin.H{"order_id": orderID, "history": history})
+}
+
+func (c *OrderController) ListOrders(ctx *gin.Context) {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
 	userIDStr, ok := userID.(string)
 	if !ok || userIDStr == "" {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user token"})
 		return
 	}
 
-	resp, err := c.orderService.ListOrdersByUser(ctx, userIDStr, int32(limit), int32(offset))
+	req, err := parseListOrders(ctx, 10, 100)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list orders", "details": err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	req.UserId = userIDStr
 
-	ctx.JSON(http.StatusOK, gin.H{"orders": resp.Orders})
+	c.listOrders(ctx, req)
 }
 
 func (c *OrderController) ListAllOrders(ctx *gin.Context) {
-	limit, offset, ok := parsePagination(ctx, 50, 200)
-	if !ok {
+	req, err := parseListOrders(ctx, 50, 200)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if userID := ctx.Query("user_id"); userID != "" {
+		if _, err := uuid.Parse(userID); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID format"})
+			return
+		}
+		req.UserId = userID
+	}
+	if raw := ctx.Query("include_deleted"); raw != "" {
+		include, err := strconv.ParseBool(raw)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "include_deleted must be true or false"})
+			return
+		}
+		req.Filter.IncludeDeleted = include
+	}
 
-	resp, err := c.orderService.ListAllOrders(ctx, int32(limit), int32(offset))
+	c.listOrders(ctx, req)
+}
+
+// RestoreOrder brings back an archived or soft deleted order.
+func (c *OrderController) RestoreOrder(ctx *gin.Context) {
+	orderID := ctx.Param("id")
+	if _, err := uuid.Parse(orderID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID format"})
+		return
+	}
+
+	restored, err := c.orderService.RestoreOrder(ctx, orderID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list orders", "details": err.Error()})
+		writeStatusError(ctx, "failed to restore order", err)
 		return
 	}
+	ctx.JSON(http.StatusOK, restored)
+}
 
-	ctx.JSON(http.StatusOK, gin.H{"orders": resp.Orders})
+// RefundOrder refunds a completed order, in full when no items are given or
+// for the given quantities of its lines otherwise. The refund is carried out
+// asynchronously, so it is answered with 202 and a PENDING refund.
+func (c *OrderController) RefundOrder(ctx *gin.Context) {
+	orderID := ctx.Param("id")
+	if _, err := uuid.Parse(orderID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID format"})
+		return
+	}
+	type RefundItem struct {
+		ProductID string `json:"product_id" binding:"required"`
+		Quantity  int32  `json:"quantity" binding:"required,min=1"`
+	}
+	type request struct {
+		Items   []RefundItem `json:"items" binding:"dive"`
+		Restock bool         `json:"restock"`
+		Reason  string       `json:"reason"`
+	}
+	var req request
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+	adminID, _ := ctx.Get("userID")
+	adminIDStr, _ := adminID.(string)
+	refundReq := &orderpb.RefundOrderRequest{
+		OrderId: orderID,
+		Restock: req.Restock,
+		Reason:  req.Reason,
+		AdminId: adminIDStr,
+	}
+	for _, item := range req.Items {
+		if _, err := uuid.Parse(item.ProductID); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid product ID format"})
+			return
+		}
+		refundReq.Items = append(refundReq.Items, &orderpb.RefundItem{
+			ProductId: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	refund, err := c.orderService.RefundOrder(ctx, refundReq)
+	if err != nil {
+		writeStatusError(ctx, "failed to refund order", err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, refund)
+}
+
+func (c *OrderController) ListRefunds(ctx *gin.Context) {
+	orderID := ctx.Param("id")
+	if _, err := uuid.Parse(orderID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID format"})
+		return
+	}
+
+	resp, err := c.orderService.ListRefunds(ctx, orderID)
+	if err != nil {
+		writeStatusError(ctx, "failed to list refunds", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"refunds": resp.Refunds})
+}
+
+func (c *OrderController) listOrders(ctx *gin.Context, req *orderpb.ListOrdersRequest) {
+	resp, err := c.orderService.ListOrders(ctx, req)
+	if err != nil {
+		writeStatusError(ctx, "failed to list orders", err)
+		return
+	}
+
+	body := gin.H{"orders": resp.Orders, "next_page_token": resp.NextPageToken}
+	if resp.TotalCount != nil {
+		body["total_count"] = resp.GetTotalCount()
+	}
+	ctx.JSON(http.StatusOK, body)
 }
 
 func (c *OrderController) canAccessOrder(ctx *gin.Context, orderID string) bool {
@@ -269,27 +443,13 @@ func (c *OrderController) canAccessOrder(ctx *gin.Context, orderID string) bool
 		return false
 	}
 	orderResp, err := c.orderService.GetOrder(ctx, orderID)
-	if err != nil || orderResp.Order == nil {
+	if err != nil {
 		return false
 	}
-	return orderResp.Order.GetUserId() == userIDStr
+	return orderResp.GetUserId() == userIDStr
 }
 
 func (c *OrderController) isAdmin(ctx *gin.Context) bool {
 	isAdmin, ok := ctx.Get("isAdmin")
 	return ok && isAdmin == true
 }
-
-func parsePagination(ctx *gin.Context, defaultLimit, maxLimit int64) (int64, int64, bool) {
-	limit, err := strconv.ParseInt(ctx.DefaultQuery("limit", strconv.FormatInt(defaultLimit, 10)), 10, 32)
-	if err != nil || limit < 1 || limit > maxLimit {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and " + strconv.FormatInt(maxLimit, 10)})
-		return 0, 0, false
-	}
-	offset, err := strconv.ParseInt(ctx.DefaultQuery("offset", "0"), 10, 32)
-	if err != nil || offset < 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "offset must be non-negative"})
-		return 0, 0, false
-	}
-	return limit, offset, true
-}
@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
+	"immxrtalbeast/order_microservices/internal/pkg/orderpb"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// orderSorts are the accepted values of the sort query parameter; a leading
+// "-" sorts descending.
+var orderSorts = map[string]orderpb.OrderSortField{
+	"created_at": orderpb.OrderSortField_ORDER_SORT_FIELD_CREATED_AT,
+	"total":      orderpb.OrderSortField_ORDER_SORT_FIELD_TOTAL,
+}
+
+// parseListOrders reads the listing query parameters shared by the user and
+// admin order lists: limit, page_token, status, from, to, product_id,
+// min_total, currency, sort and include_total.
+func parseListOrders(ctx *gin.Context, defaultLimit, maxLimit int) (*orderpb.ListOrdersRequest, error) {
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", strconv.Itoa(defaultLimit)))
+	if err != nil || limit < 1 || limit > maxLimit {
+		return nil, fmt.Errorf("limit must be between 1 and %d", maxLimit)
+	}
+	req := &orderpb.ListOrdersRequest{
+		Limit:     int32(limit),
+		PageToken: ctx.Query("page_token"),
+		Filter:    &orderpb.OrderFilter{},
+		Sort:      &orderpb.OrderSort{},
+	}
+
+	if raw := ctx.Query("status"); raw != "" {
+		for _, name := range strings.Split(raw, ",") {
+			orderStatus, err := parseOrderStatus(name)
+			if err != nil {
+				return nil, err
+			}
+			req.Filter.Statuses = append(req.Filter.Statuses, orderStatus)
+		}
+	}
+	if raw := ctx.Query("from"); raw != "" {
+		from, err := parseDateParam(raw, false)
+		if err != nil {
+			return nil, errors.New("from must be an RFC 3339 time or a YYYY-MM-DD date")
+		}
+		req.Filter.CreatedFrom = timestamppb.New(from)
+	}
+	if raw := ctx.Query("to"); raw != "" {
+		to, err := parseDateParam(raw, true)
+		if err != nil {
+			return nil, errors.New("to must be an RFC 3339 time or a YYYY-MM-DD date")
+		}
+		req.Filter.CreatedTo = timestamppb.New(to)
+	}
+	if raw := ctx.Query("product_id"); raw != "" {
+		if _, err := uuid.Parse(raw); err != nil {
+			return nil, errors.New("invalid product ID format")
+		}
+		req.Filter.ProductId = raw
+	}
+	if raw := ctx.Query("min_total"); raw != "" {
+		currency := strings.ToUpper(ctx.DefaultQuery("currency", money.DefaultCurrency))
+		minTotal, err := money.Parse(raw, currency)
+		if err != nil {
+			return nil, errors.New("min_total must be a decimal amount")
+		}
+		req.Filter.MinTotal = &orderpb.Money{Amount: minTotal.Amount, Currency: minTotal.Currency}
+	}
+
+	if raw := ctx.DefaultQuery("sort", "-created_at"); raw != "" {
+		field, ok := orderSorts[strings.TrimPrefix(raw, "-")]
+		if !ok {
+			return nil, errors.New("sort must be created_at or total, optionally prefixed with -")
+		}
+		req.Sort.Field = field
+		req.Sort.Ascending = !strings.HasPrefix(raw, "-")
+	}
+
+	if raw := ctx.Query("include_total"); raw != "" {
+		include, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, errors.New("include_total must be true or false")
+		}
+		req.IncludeTotalCount = include
+	}
+	return req, nil
+}
+
+// parseDateParam reads an RFC 3339 time or a date. A date used as the end of
+// a range covers the whole day.
+func parseDateParam(raw string, end bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
@@ -0,0 +1,208 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"immxrtalbeast/order_microservices/api-gateway/internal/middleware"
+	"immxrtalbeast/order_microservices/internal/pkg/orderpb"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/net/websocket"
+)
+
+// watchKeepAlive keeps idle status streams from being closed by proxies.
+const watchKeepAlive = 25 * time.Second
+
+type orderStatusEvent struct {
+	OrderID   string    `json:"order_id"`
+	From      string    `json:"from,omitempty"`
+	To        string    `json:"to"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+func newOrderStatusEvent(e *orderpb.OrderStatusEvent) orderStatusEvent {
+	event := orderStatusEvent{
+		OrderID:   e.GetOrderId(),
+		To:        statusName(e.GetTo()),
+		ChangedAt: e.GetChangedAt().AsTime(),
+	}
+	if e.GetFrom() != orderpb.OrderStatus_ORDER_STATUS_UNSPECIFIED {
+		event.From = statusName(e.GetFrom())
+	}
+	return event
+}
+
+type socketMessage struct {
+	Event string `json:"event"`
+	Data  any    `json:"data,omitempty"`
+}
+
+// orderWatch is an open WatchOrder stream.
+type orderWatch struct {
+	orderID string
+	events  <-chan *orderpb.OrderStatusEvent
+	// err holds why the stream ended once events is closed, io.EOF after a
+	// terminal status.
+	err    <-chan error
+	cancel context.CancelFunc
+}
+
+// WatchOrderEvents streams the order's status changes as Server-Sent Events.
+// The first status event is the current status and an end event follows the
+// terminal one. A stream closed without end should be reopened.
+func (c *OrderController) WatchOrderEvents(ctx *gin.Context) {
+	watch, ok := c.openWatch(ctx)
+	if !ok {
+		return
+	}
+	defer watch.cancel()
+
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+	finished := c.relay(ctx.Request.Context().Done(), watch, func(event *orderStatusEvent) error {
+		if event == nil {
+			if _, err := ctx.Writer.WriteString(": keepalive\n\n"); err != nil {
+				return err
+			}
+		} else {
+			ctx.SSEvent("status", event)
+		}
+		ctx.Writer.Flush()
+		return nil
+	})
+	if finished {
+		ctx.SSEvent("end", gin.H{"order_id": watch.orderID})
+		ctx.Writer.Flush()
+	}
+}
+
+// WatchOrderSocket streams the same events as WatchOrderEvents over a
+// WebSocket, one {"event", "data"} JSON message each. The socket is closed
+// after end.
+func (c *OrderController) WatchOrderSocket(ctx *gin.Context) {
+	// Browsers send cookies on cross-site WebSocket handshakes and CORS does
+	// not apply to them.
+	if origin := ctx.GetHeader("Origin"); origin != "" && !middleware.AllowedOrigin(origin) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "origin not allowed"})
+		return
+	}
+	watch, ok := c.openWatch(ctx)
+	if !ok {
+		return
+	}
+	defer watch.cancel()
+
+	server := websocket.Server{
+		// The origin is checked above; clients other than browsers send none.
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+			left := make(chan struct{})
+			go func() {
+				// Incoming messages are ignored, reading only notices the close.
+				defer close(left)
+				var msg []byte
+				for websocket.Message.Receive(ws, &msg) == nil {
+				}
+			}()
+
+			finished := c.relay(left, watch, func(event *orderStatusEvent) error {
+				if event == nil {
+					return websocket.JSON.Send(ws, socketMessage{Event: "keepalive"})
+				}
+				return websocket.JSON.Send(ws, socketMessage{Event: "status", Data: event})
+			})
+			if finished {
+				websocket.JSON.Send(ws, socketMessage{Event: "end", Data: gin.H{"order_id": watch.orderID}})
+			}
+		},
+	}
+	server.ServeHTTP(ctx.Writer, ctx.Request)
+}
+
+// CloseWatches ends the open status streams so a shutdown does not wait for
+// them.
+func (c *OrderController) CloseWatches() {
+	c.closeOnce.Do(func() { close(c.closing) })
+}
+
+// openWatch checks the caller may see the order and opens its status stream.
+// On failure it writes the error response and returns false.
+func (c *OrderController) openWatch(ctx *gin.Context) (*orderWatch, bool) {
+	orderID := ctx.Param("id")
+	if _, err := uuid.Parse(orderID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID format"})
+		return nil, false
+	}
+	if !c.canAccessOrder(ctx, orderID) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "order belongs to another user"})
+		return nil, false
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx.Request.Context())
+	stream, err := c.orderService.WatchOrder(streamCtx, orderID)
+	if err != nil {
+		cancel()
+		writeStatusError(ctx, "failed to watch order", err)
+		return nil, false
+	}
+	// Errors of the call arrive with the first message, while a plain HTTP
+	// response can still be written.
+	first, err := stream.Recv()
+	if err != nil {
+		cancel()
+		writeStatusError(ctx, "failed to watch order", err)
+		return nil, false
+	}
+
+	events := make(chan *orderpb.OrderStatusEvent)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(events)
+		for event := first; ; {
+			select {
+			case events <- event:
+			case <-streamCtx.Done():
+				errs <- streamCtx.Err()
+				return
+			}
+			if event, err = stream.Recv(); err != nil {
+				errs <- err
+				return
+			}
+		}
+	}()
+	return &orderWatch{orderID: orderID, events: events, err: errs, cancel: cancel}, true
+}
+
+// relay passes the watched statuses to send until the stream ends, done is
+// closed or the gateway shuts down. send gets nil for a keepalive. relay
+// reports whether the order reached a terminal status.
+func (c *OrderController) relay(done <-chan struct{}, watch *orderWatch, send func(*orderStatusEvent) error) bool {
+	ticker := time.NewTicker(watchKeepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return false
+		case <-c.closing:
+			return false
+		case <-ticker.C:
+			if send(nil) != nil {
+				return false
+			}
+		case e, ok := <-watch.events:
+			if !ok {
+				return errors.Is(<-watch.err, io.EOF)
+			}
+			event := newOrderStatusEvent(e)
+			if send(&event) != nil {
+				return false
+			}
+		}
+	}
+}
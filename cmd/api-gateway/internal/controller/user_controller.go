@@ -2,6 +2,7 @@ package controller
 
 import (
 	authgrpc "immxrtalbeast/order_microservices/api-gateway/internal/clients/auth"
+	ordergrpc "immxrtalbeast/order_microservices/api-gateway/internal/clients/order"
 	"net/http"
 	"os"
 	"time"
@@ -11,12 +12,13 @@ import (
 )
 
 type UserController struct {
-	authService *authgrpc.Client
-	tokenTTL    time.Duration
+	authService  *authgrpc.Client
+	orderService *ordergrpc.Client
+	tokenTTL     time.Duration
 }
 
-func NewUserController(authService *authgrpc.Client, tokenTTL time.Duration) *UserController {
-	return &UserController{authService: authService, tokenTTL: tokenTTL}
+func NewUserController(authService *authgrpc.Client, orderService *ordergrpc.Client, tokenTTL time.Duration) *UserController {
+	return &UserController{authService: authService, orderService: orderService, tokenTTL: tokenTTL}
 }
 
 func (c *UserController) Register(ctx *gin.Context) {
@@ -73,11 +75,13 @@ func (c *UserController) Login(ctx *gin.Context) {
 	}
 
 	isAdmin := false
+	userID := ""
 	if parsedToken, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{}); err == nil {
 		if claims, ok := parsedToken.Claims.(jwt.MapClaims); ok {
 			if claimValue, ok := claims["is_admin"].(bool); ok {
 				isAdmin = claimValue
 			}
+			userID, _ = claims["uid"].(string)
 		}
 	}
 
@@ -91,6 +95,13 @@ func (c *UserController) Login(ctx *gin.Context) {
 		os.Getenv("COOKIE_SECURE") == "true",
 		true,
 	)
+	// The cart filled before logging in moves into the user's cart. Login
+	// succeeds regardless; a cart that fails to merge stays in its cookie.
+	if cartID, err := ctx.Cookie(cartCookie); err == nil && cartID != "" && userID != "" {
+		if _, err := c.orderService.MergeCarts(ctx, cartID, userID); err == nil {
+			ctx.SetCookie(cartCookie, "", -1, "/", "", os.Getenv("COOKIE_SECURE") == "true", true)
+		}
+	}
 
 	ctx.JSON(http.StatusOK, gin.H{
 		"message":  "login success",
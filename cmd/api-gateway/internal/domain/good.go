@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"immxrtalbeast/order_microservices/internal/pkg/money"
+
 	"github.com/google/uuid"
 )
 
@@ -9,7 +11,7 @@ type Good struct {
 	Name            string    `gorm:"not null"`
 	ImageLink       string
 	Description     string
-	Price           int `gorm:"not null"`
+	Price           money.Money `gorm:"embedded;embeddedPrefix:price_"`
 	Volume          int
 	QuantityInStock int
 }
@@ -33,53 +33,88 @@ func AuthMiddleware(appSecret string) gin.HandlerFunc {
 			tokenString = cookie
 		}
 
-		claims := jwt.MapClaims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte(appSecret), nil
-		})
-
-		if err != nil {
-			if errors.Is(err, jwt.ErrTokenExpired) {
-				c.AbortWithStatusJSON(401, gin.H{"error": "Token expired"})
-				return
-			}
-			c.AbortWithStatusJSON(401, gin.H{"error": "Invalid token"})
+		if msg := authenticate(c, appSecret, tokenString); msg != "" {
+			c.AbortWithStatusJSON(401, gin.H{"error": msg})
 			return
 		}
 
-		if !token.Valid {
-			c.AbortWithStatusJSON(401, gin.H{"error": "Invalid token"})
+		c.Next()
+	}
+}
+
+// OptionalAuthMiddleware authenticates requests that carry a token like
+// AuthMiddleware and lets the others through anonymously, without userID.
+func OptionalAuthMiddleware(appSecret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if strings.TrimSpace(appSecret) == "" {
+			c.AbortWithStatusJSON(500, gin.H{"error": "auth is not configured"})
 			return
 		}
 
-		expiresAt, err := claims.GetExpirationTime()
-		if err != nil || expiresAt == nil {
-			c.AbortWithStatusJSON(401, gin.H{"error": "Invalid token claims"})
-			return
+		var tokenString string
+		if authHeader := c.GetHeader("Authorization"); authHeader != "" {
+			if !strings.HasPrefix(authHeader, "Bearer ") {
+				c.AbortWithStatusJSON(401, gin.H{"error": "Bearer token required"})
+				return
+			}
+			tokenString = strings.TrimPrefix(authHeader, "Bearer ")
+		} else if cookie, err := c.Cookie("jwt"); err == nil {
+			tokenString = cookie
 		}
-		if time.Now().After(expiresAt.Time) {
-			c.AbortWithStatusJSON(401, gin.H{"error": "Token expired"})
-			return
+
+		if tokenString != "" {
+			if msg := authenticate(c, appSecret, tokenString); msg != "" {
+				c.AbortWithStatusJSON(401, gin.H{"error": msg})
+				return
+			}
 		}
 
-		userID, ok := claims["uid"].(string)
-		if !ok || userID == "" {
-			c.AbortWithStatusJSON(401, gin.H{"error": "Invalid user ID in token"})
-			return
+		c.Next()
+	}
+}
+
+// authenticate validates tokenString and sets userID and isAdmin on c. It
+// returns the error to answer with when the token is not valid.
+func authenticate(c *gin.Context, appSecret, tokenString string) string {
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
+		return []byte(appSecret), nil
+	})
 
-		c.Set("userID", userID)
-		if isAdmin, ok := claims["is_admin"].(bool); ok {
-			c.Set("isAdmin", isAdmin)
-		} else {
-			c.Set("isAdmin", false)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return "Token expired"
 		}
+		return "Invalid token"
+	}
 
-		c.Next()
+	if !token.Valid {
+		return "Invalid token"
+	}
+
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil || expiresAt == nil {
+		return "Invalid token claims"
+	}
+	if time.Now().After(expiresAt.Time) {
+		return "Token expired"
+	}
+
+	userID, ok := claims["uid"].(string)
+	if !ok || userID == "" {
+		return "Invalid user ID in token"
+	}
+
+	c.Set("userID", userID)
+	if isAdmin, ok := claims["is_admin"].(bool); ok {
+		c.Set("isAdmin", isAdmin)
+	} else {
+		c.Set("isAdmin", false)
 	}
+	return ""
 }
 
 func AdminOnlyMiddleware() gin.HandlerFunc {
@@ -0,0 +1,10 @@
+package middleware
+
+import "strings"
+
+// AllowedOrigin reports whether browsers on origin may call the API. It backs
+// CORS and the WebSocket handshake, which CORS does not cover.
+func AllowedOrigin(origin string) bool {
+	return strings.HasPrefix(origin, "http://localhost:") ||
+		origin == "http://80.253.249.143"
+}
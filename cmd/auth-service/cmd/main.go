@@ -8,6 +8,8 @@ import (
 	"immxrtalbeast/order_microservices/auth-service/internal/tracing"
 	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/joho/godotenv"
 )
@@ -31,9 +33,13 @@ func main() {
 	}
 	application := app.New(log, cfg.GRPC.Port, dsn, cfg.TokenTTL, os.Getenv("APP_SECRET"))
 
-	application.GRPCServer.MustRun()
-	log.Info("db connected")
+	go application.GRPCServer.MustRun()
 
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	application.GRPCServer.Stop()
+	log.Info("application stopped")
 }
 
 const (
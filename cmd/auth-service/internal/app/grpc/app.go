@@ -70,3 +70,11 @@ func (a *App) Run() error {
 
 	return nil
 }
+
+// Stop stops accepting new requests and waits for running ones to finish.
+func (a *App) Stop() {
+	const op = "grpcapp.Stop"
+
+	a.log.With(slog.String("op", op)).Info("stopping grpc server", slog.Int("port", a.port))
+	a.gRPCServer.GracefulStop()
+}
@@ -2,28 +2,37 @@ package main
 
 import (
 	"context"
+	kafka "immxrtalbeast/order_microservices/internal/pkg/kafka"
+	"immxrtalbeast/order_microservices/internal/pkg/outbox"
 	"immxrtalbeast/order_microservices/inventory-service/grpcapp"
 	"immxrtalbeast/order_microservices/inventory-service/internal/client"
 	"immxrtalbeast/order_microservices/inventory-service/internal/config"
 	"immxrtalbeast/order_microservices/inventory-service/internal/domain"
+	"immxrtalbeast/order_microservices/inventory-service/internal/lib/logger/sl"
 	"immxrtalbeast/order_microservices/inventory-service/internal/lib/logger/slogpretty"
 	"immxrtalbeast/order_microservices/inventory-service/internal/service/good"
+	"immxrtalbeast/order_microservices/inventory-service/internal/service/modifier"
 	"immxrtalbeast/order_microservices/inventory-service/internal/storage/psql"
 	"immxrtalbeast/order_microservices/inventory-service/internal/tracing"
 	"log/slog"
 	"os"
-
-	kafka "github.com/ozzus/order_kafka"
+	"os/signal"
+	"syscall"
 
 	"github.com/joho/godotenv"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// serviceName stamps the outbox messages and inbox records of this service.
+const serviceName = "inventory-service"
+
 func main() {
 	cfg := config.MustLoad()
 	log := setupLogger(cfg.Env)
 	log.Info("starting application")
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 	if err := godotenv.Load(".env"); err != nil {
 		panic(err)
 	}
@@ -48,15 +57,35 @@ func main() {
 		panic("failed to connect database")
 	}
 	log.Info("db connected")
-	db.AutoMigrate(&domain.Good{})
+	db.AutoMigrate(&domain.Good{}, &domain.Reservation{}, &domain.Restock{}, &domain.ModifierGroup{}, &domain.Modifier{}, &domain.GoodModifierGroup{}, &domain.ModifierReservation{}, &outbox.Message{}, &domain.InboxMessage{})
+	if err := kafka.EnsureTopics(ctx, []string{os.Getenv("KAFKA_ADDRESS")},
+		kafka.TopicsWithDeadLetters(cfg.Kafka.Partitions, cfg.Kafka.ReplicationFactor, "saga-commands", "saga-replies")...,
+	); err != nil {
+		panic(err)
+	}
 	producer := kafka.NewProducer(
 		[]string{os.Getenv("KAFKA_ADDRESS")},
 		"saga-replies",
 	)
 	defer producer.Close()
+	deadLetters := kafka.NewDeadLetterQueue([]string{os.Getenv("KAFKA_ADDRESS")})
+	defer deadLetters.Close()
 
 	goodRepo := psql.NewGoodRepository(db)
-	goodInteractor := good.NewGoodInteractor(goodRepo, log, producer)
+	modifierRepo := psql.NewModifierRepository(db)
+	outboxRepo := outbox.NewRepository(db, serviceName)
+	inboxRepo := psql.NewInboxRepository(db, serviceName)
+	transactor := outbox.NewTransactor(db)
+	goodInteractor := good.NewGoodInteractor(goodRepo, outboxRepo, transactor, log, cfg.Reservation.HoldTTL)
+	go goodInteractor.RunExpiryJob(ctx, cfg.Reservation.SweepInterval, cfg.Reservation.SweepBatch)
+	modifierInteractor := modifier.NewModifierInteractor(modifierRepo, log)
+
+	relay := outbox.NewRelay(log, outboxRepo, map[string]*kafka.Producer{"saga-replies": producer}, cfg.Kafka.ContentType)
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		relay.Run(ctx, cfg.Outbox.PollInterval, cfg.Outbox.BatchSize)
+	}()
 
 	consumer := kafka.NewConsumer(
 		[]string{os.Getenv("KAFKA_ADDRESS")},
@@ -64,10 +93,26 @@ func main() {
 		"inventory-service-group",
 	)
 	defer consumer.Close()
-	go client.ProcessInventoryEvents(consumer, goodInteractor, log)
-	grpcApp := grpcapp.New(log, goodInteractor, cfg.GRPC.Port)
-	grpcApp.MustRun()
-
+	pool := kafka.NewPool(
+		consumer,
+		client.NewInventoryEventsHandler(goodInteractor, client.NewInbox(inboxRepo, transactor), log),
+		log,
+		cfg.Consumer.PoolConfig(deadLetters),
+	)
+	go pool.Run(ctx)
+	grpcApp := grpcapp.New(log, goodInteractor, modifierInteractor, cfg.GRPC.Port)
+	go grpcApp.MustRun()
+
+	<-ctx.Done()
+	log.Info("shutting down")
+	grpcApp.Stop()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Consumer.ShutdownTimeout)
+	defer cancel()
+	if err := pool.Shutdown(shutdownCtx); err != nil {
+		log.Error("in-flight messages were not drained", sl.Err(err))
+	}
+	<-relayDone
+	log.Info("application stopped")
 }
 
 const (
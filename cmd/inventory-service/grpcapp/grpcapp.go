@@ -21,7 +21,7 @@ type GrpcApp struct {
 	port       int // Порт, на котором будет работать grpc-сервер
 }
 
-func New(log *slog.Logger, inventoryInteractor domain.InventoryInteractor, port int) *GrpcApp {
+func New(log *slog.Logger, inventoryInteractor domain.InventoryInteractor, modifierInteractor domain.ModifierInteractor, port int) *GrpcApp {
 
 	recoveryOpts := []recovery.Option{
 		recovery.WithRecoveryHandler(func(p interface{}) (err error) {
@@ -37,7 +37,7 @@ func New(log *slog.Logger, inventoryInteractor domain.InventoryInteractor, port
 		),
 		grpc.StatsHandler(otelgrpc.NewServerHandler()))
 
-	inventorygrpc.Register(gRPCServer, inventoryInteractor)
+	inventorygrpc.Register(gRPCServer, inventoryInteractor, modifierInteractor)
 
 	return &GrpcApp{
 		log:        log,
@@ -67,3 +67,11 @@ func (a *GrpcApp) Run() error {
 
 	return nil
 }
+
+// Stop stops accepting new requests and waits for running ones to finish.
+func (a *GrpcApp) Stop() {
+	const op = "grpcapp.Stop"
+
+	a.log.With(slog.String("op", op)).Info("stopping grpc server", slog.Int("port", a.port))
+	a.gRPCServer.GracefulStop()
+}
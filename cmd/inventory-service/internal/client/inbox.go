@@ -0,0 +1,42 @@
+package client
+
+import (
+	"context"
+	"errors"
+	mykafka "immxrtalbeast/order_microservices/internal/pkg/kafka"
+	"immxrtalbeast/order_microservices/inventory-service/internal/domain"
+	"log/slog"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Inbox drops Kafka messages the service has already processed.
+type Inbox struct {
+	repo       domain.InboxRepository
+	transactor domain.Transactor
+}
+
+func NewInbox(repo domain.InboxRepository, transactor domain.Transactor) *Inbox {
+	return &Inbox{repo: repo, transactor: transactor}
+}
+
+// Handle runs handle in one transaction with recording msg as processed. A
+// message seen before is skipped; messages without an ID are always handled.
+func (i *Inbox) Handle(ctx context.Context, log *slog.Logger, msg kafka.Message, eventType string, handle func(ctx context.Context) error) error {
+	messageID := mykafka.Header(msg, mykafka.HeaderMessageID)
+	if messageID == "" {
+		log.Warn("message has no id, handling without deduplication", slog.String("event_type", eventType))
+		return handle(ctx)
+	}
+	err := i.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := i.repo.Record(ctx, messageID, eventType); err != nil {
+			return err
+		}
+		return handle(ctx)
+	})
+	if errors.Is(err, domain.ErrDuplicateMessage) {
+		log.Info("duplicate message skipped", slog.String("message_id", messageID), slog.String("event_type", eventType))
+		return nil
+	}
+	return err
+}
@@ -2,74 +2,68 @@ package client
 
 import (
 	"context"
-	"encoding/json"
-	"immxrtalbeast/order_microservices/inventory-service/internal/domain"
-	"immxrtalbeast/order_microservices/inventory-service/internal/lib/logger/sl"
+	"errors"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	mykafka "immxrtalbeast/order_microservices/internal/pkg/kafka"
 	"immxrtalbeast/order_microservices/inventory-service/internal/service/good"
 	"log/slog"
-	"time"
-
-	mykafka "github.com/ozzus/order_kafka"
 
 	"github.com/segmentio/kafka-go"
 	"go.opentelemetry.io/otel/propagation"
 )
 
-func ProcessInventoryEvents(consumer *mykafka.Consumer, goodInteractor *good.GoodInteractor, log *slog.Logger) {
-	log.Info("listening kafka")
+func NewInventoryEventsHandler(goodInteractor *good.GoodInteractor, inbox *Inbox, log *slog.Logger) mykafka.Handler {
 	propagator := propagation.TraceContext{}
-	for {
-		readCtx, readCancel := context.WithTimeout(context.Background(), 1*time.Second)
-
-		msg, err := consumer.ReadRawMessage(readCtx)
-		readCancel()
+	return func(ctx context.Context, msg kafka.Message) error {
+		log.Info("EventReceived")
+		env, err := events.Unmarshal(msg.Value, mykafka.Header(msg, mykafka.HeaderContentType), mykafka.Header(msg, mykafka.HeaderEventType))
 		if err != nil {
-			if err == context.DeadlineExceeded {
-				continue
-			}
-			time.Sleep(1 * time.Second)
-			continue
+			return mykafka.Permanent(err)
+		}
+		event, err := env.Decode()
+		if errors.Is(err, events.ErrUnknownType) {
+			return nil
 		}
-		log.Info("EventReceived")
-		eventType, err := getHeaderValue(msg.Headers, "Event-Type")
 		if err != nil {
-			log.Error("error while parsing eventType", sl.Err(err), "eventType is", eventType)
-			continue
+			return mykafka.Permanent(err)
 		}
-		baseCtx := context.Background()
+
 		carrier := propagation.MapCarrier{}
 		for _, header := range msg.Headers {
 			carrier[header.Key] = string(header.Value)
 		}
+		ctx = propagator.Extract(ctx, carrier)
+		ctx = events.WithCorrelationID(ctx, env.CorrelationID)
+		handle := func(fn func(ctx context.Context) error) error {
+			return inbox.Handle(ctx, log, msg, env.Type, fn)
+		}
+		switch e := event.(type) {
+		case events.ReserveInventory:
+			log.Info("products reserve command received", "event", e)
+			return handle(func(ctx context.Context) error {
+				return goodInteractor.ReserveProducts(ctx, e)
+			})
 
-		ctx := propagator.Extract(baseCtx, carrier)
-		processCtx, processCancel := context.WithTimeout(ctx, 30*time.Second)
-		switch eventType {
-		case "InventoryReserveItemsCommand":
-			var event domain.ReserveProductsEvent
-			if err := json.Unmarshal(msg.Value, &event); err != nil {
-				log.Error("failed to unmarshal event", "type", eventType, "error", err)
-				continue
-			}
-			log.Info("products reserve command received", "event", event)
-
-			go func() {
-				defer processCancel()
-				goodInteractor.ReserveProducts(processCtx, event)
-			}()
+		case events.CommitInventory:
+			log.Info("commit inventory command received", "command", e)
+			return handle(func(ctx context.Context) error {
+				return goodInteractor.CommitProducts(ctx, e)
+			})
 
-		default:
-			continue
-		}
+		case events.ReleaseInventory:
+			log.Info("release inventory command received", "command", e)
+			return handle(func(ctx context.Context) error {
+				return goodInteractor.ReleaseProducts(ctx, e)
+			})
 
-	}
-}
+		case events.RestockInventory:
+			log.Info("restock inventory command received", "command", e)
+			return handle(func(ctx context.Context) error {
+				return goodInteractor.RestockProducts(ctx, e)
+			})
 
-func getHeaderValue(headers []kafka.Header, key string) (string, error) {
-	for _, h := range headers {
-		if h.Key == key {
-			return string(h.Value), nil
+		default:
+			return nil
 		}
 	}
-	return "", nil
 }
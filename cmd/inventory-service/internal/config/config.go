@@ -2,6 +2,7 @@ package config
 
 import (
 	"flag"
+	kafka "immxrtalbeast/order_microservices/internal/pkg/kafka"
 	"os"
 	"time"
 
@@ -9,9 +10,21 @@ import (
 )
 
 type Config struct {
-	Env    string     `yaml:"env" env-default:"local"`
-	Jaeger Client     `yaml:"jaeger"`
-	GRPC   GRPCConfig `yaml:"grpc"`
+	Env         string            `yaml:"env" env-default:"local"`
+	Jaeger      Client            `yaml:"jaeger"`
+	GRPC        GRPCConfig        `yaml:"grpc"`
+	Reservation ReservationConfig `yaml:"reservation"`
+	Outbox      OutboxConfig      `yaml:"outbox"`
+	Consumer    ConsumerConfig    `yaml:"consumer"`
+	Kafka       KafkaConfig       `yaml:"kafka"`
+}
+
+type KafkaConfig struct {
+	Partitions        int `yaml:"partitions" env-default:"6"`
+	ReplicationFactor int `yaml:"replication_factor" env-default:"1"`
+	// ContentType is the encoding of published events, application/x-protobuf
+	// or application/json. Consumers accept both.
+	ContentType string `yaml:"content_type" env-default:"application/x-protobuf"`
 }
 type Client struct {
 	Address string `yaml:"address"`
@@ -21,6 +34,37 @@ type GRPCConfig struct {
 	Timeout time.Duration `yaml:"timeout"`
 }
 
+type ReservationConfig struct {
+	HoldTTL       time.Duration `yaml:"hold_ttl" env-default:"30m"`
+	SweepInterval time.Duration `yaml:"sweep_interval" env-default:"30s"`
+	SweepBatch    int           `yaml:"sweep_batch" env-default:"100"`
+}
+
+type OutboxConfig struct {
+	PollInterval time.Duration `yaml:"poll_interval" env-default:"500ms"`
+	BatchSize    int           `yaml:"batch_size" env-default:"100"`
+}
+
+type ConsumerConfig struct {
+	Workers         int                          `yaml:"workers" env-default:"8"`
+	QueueSize       int                          `yaml:"queue_size" env-default:"64"`
+	HandlerTimeout  time.Duration                `yaml:"handler_timeout" env-default:"30s"`
+	ShutdownTimeout time.Duration                `yaml:"shutdown_timeout" env-default:"20s"`
+	Retry           kafka.RetryPolicy            `yaml:"retry"`
+	EventRetry      map[string]kafka.RetryPolicy `yaml:"event_retry"`
+}
+
+func (c ConsumerConfig) PoolConfig(deadLetters *kafka.DeadLetterQueue) kafka.PoolConfig {
+	return kafka.PoolConfig{
+		Workers:        c.Workers,
+		QueueSize:      c.QueueSize,
+		HandlerTimeout: c.HandlerTimeout,
+		Retry:          c.Retry,
+		EventRetry:     c.EventRetry,
+		DeadLetters:    deadLetters,
+	}
+}
+
 func MustLoad() *Config {
 	configPath := fetchConfigPath()
 	if configPath == "" {
@@ -2,36 +2,132 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+var (
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrAlreadyReleased     = errors.New("reservation already released")
+	ErrAlreadyCommitted    = errors.New("reservation already committed")
+	ErrHoldExpired         = errors.New("reservation expired")
+	ErrAlreadyRestocked    = errors.New("refund already restocked")
+	ErrInsufficientStock   = errors.New("insufficient quantity")
+	ErrMixedCurrencies     = errors.New("order goods are priced in different currencies")
+	ErrGoodNotFound        = errors.New("good not found")
+)
+
+type ReservationState string
+
+const (
+	ReservationHeld      ReservationState = "HELD"
+	ReservationCommitted ReservationState = "COMMITTED"
+	ReservationReleased  ReservationState = "RELEASED"
+	// ReservationExpired is a hold that outlived its expiry. It no longer
+	// lowers the available quantity and can only be released.
+	ReservationExpired ReservationState = "EXPIRED"
+)
+
 type Good struct {
 	ID              uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
 	Name            string    `gorm:"not null"`
 	Category        string    `gorm:"not null"`
 	ImageLink       string
 	Description     string
-	Price           int `gorm:"not null"`
+	Price           money.Money `gorm:"embedded;embeddedPrefix:price_"`
 	Volume          int
 	QuantityInStock int
+	// Held is the quantity under open reservations; it is computed on read.
+	Held int `gorm:"->;-:migration"`
+}
+
+// Available is the stock that can still be reserved.
+func (g *Good) Available() int {
+	return g.QuantityInStock - g.Held
 }
 
 type OrderItem struct {
-	GoodID   uuid.UUID `json:"product_id"`
-	Quantity int       `json:"quantity"`
+	GoodID      uuid.UUID   `json:"product_id"`
+	Quantity    int         `json:"quantity"`
+	ModifierIDs []uuid.UUID `json:"modifier_ids,omitempty"`
+}
+
+// ReservedItem is a reserved order line together with the good's name, volume
+// and price and its modifiers at the time of the reservation.
+type ReservedItem struct {
+	GoodID    uuid.UUID
+	Name      string
+	Volume    int
+	Price     money.Money
+	Quantity  int
+	Modifiers []Modifier
 }
-type ReserveProductsEvent struct {
-	OrderID  uuid.UUID   `json:"order_id"`
-	SagaID   uuid.UUID   `json:"saga_id"`
-	Products []OrderItem `json:"products"`
+
+// UnitPrice is the price of one unit with its modifiers.
+func (i ReservedItem) UnitPrice() (money.Money, error) {
+	price := i.Price
+	for _, modifier := range i.Modifiers {
+		var err error
+		if price, err = price.Add(modifier.PriceDelta); err != nil {
+			return money.Money{}, err
+		}
+	}
+	return price, nil
+}
+
+func (i ReservedItem) LineTotal() (money.Money, error) {
+	price, err := i.UnitPrice()
+	return price.Mul(i.Quantity), err
+}
+
+// ReservedTotal sums the line totals of items, which must share a currency.
+func ReservedTotal(items []ReservedItem) (money.Money, error) {
+	lines := make([]money.Money, len(items))
+	for i, item := range items {
+		line, err := item.LineTotal()
+		if errors.Is(err, money.ErrCurrencyMismatch) {
+			return money.Money{}, ErrMixedCurrencies
+		}
+		lines[i] = line
+	}
+	total, err := money.Sum(lines...)
+	if errors.Is(err, money.ErrCurrencyMismatch) {
+		return money.Money{}, ErrMixedCurrencies
+	}
+	return total, err
+}
+
+// Reservation is a ledger entry for stock set aside for an order. A HELD
+// reservation lowers the available quantity until it is committed, which takes
+// the stock off hand, released by the saga or expired.
+type Reservation struct {
+	ID        uuid.UUID        `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	OrderID   uuid.UUID        `gorm:"type:uuid;not null;uniqueIndex:idx_reservations_order_good"`
+	SagaID    uuid.UUID        `gorm:"type:uuid;not null"`
+	GoodID    uuid.UUID        `gorm:"type:uuid;not null;uniqueIndex:idx_reservations_order_good"`
+	Quantity  int              `gorm:"not null"`
+	State     ReservationState `gorm:"type:varchar(20);not null;default:'HELD'"`
+	ExpiresAt time.Time        `gorm:"not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ExpiredHold is an order whose holds have expired, reported to its saga.
+type ExpiredHold struct {
+	OrderID uuid.UUID
+	SagaID  uuid.UUID
 }
 
-type ReserveProductsEventReply struct {
-	OrderID  uuid.UUID   `json:"order_id"`
-	SagaID   uuid.UUID   `json:"saga_id"`
-	Products []OrderItem `json:"products"`
-	TotalSum int         `json:"total_sum"`
+// Restock records that a refund saga put its items back in stock, so a
+// redelivered command does not add them twice.
+type Restock struct {
+	SagaID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	OrderID   uuid.UUID `gorm:"type:uuid;not null;index"`
+	CreatedAt time.Time
 }
 
 type GoodRepository interface {
@@ -39,13 +135,20 @@ type GoodRepository interface {
 	ListGoods(ctx context.Context) ([]*Good, error)
 	DeleteGood(ctx context.Context, goodID uuid.UUID) error
 	UpdateGood(ctx context.Context, good *Good) error
-	ReserveProducts(ctx context.Context, goods []OrderItem) (int, error)
+	ReserveProducts(ctx context.Context, orderID, sagaID uuid.UUID, goods []OrderItem, expiresAt time.Time) ([]ReservedItem, error)
+	CommitProducts(ctx context.Context, orderID uuid.UUID) ([]OrderItem, error)
+	ReleaseProducts(ctx context.Context, orderID uuid.UUID) ([]OrderItem, error)
+	ExpireHolds(ctx context.Context, now time.Time, limit int) ([]ExpiredHold, error)
+	RestockProducts(ctx context.Context, orderID, sagaID uuid.UUID, goods []OrderItem) ([]OrderItem, error)
 }
 
 type InventoryInteractor interface {
-	AddGood(ctx context.Context, name, category, description, imageLink string, price, volume, quantityInStock int) error
+	AddGood(ctx context.Context, name, category, description, imageLink string, price money.Money, volume, quantityInStock int) error
 	ListProducts(ctx context.Context) ([]*Good, error)
 	DeleteGood(ctx context.Context, goodID uuid.UUID) error
-	UpdateGood(ctx context.Context, goodID uuid.UUID, name, category, description, imageLink string, price, volume, quantityInStock int) error
-	ReserveProducts(ctx context.Context, event ReserveProductsEvent)
+	UpdateGood(ctx context.Context, goodID uuid.UUID, name, category, description, imageLink string, price money.Money, volume, quantityInStock int) error
+	ReserveProducts(ctx context.Context, command events.ReserveInventory) error
+	CommitProducts(ctx context.Context, command events.CommitInventory) error
+	ReleaseProducts(ctx context.Context, command events.ReleaseInventory) error
+	RestockProducts(ctx context.Context, command events.RestockInventory) error
 }
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrDuplicateMessage = errors.New("message already processed")
+)
+
+// InboxMessage records a consumed Kafka message so a redelivery is recognised.
+type InboxMessage struct {
+	Consumer    string `gorm:"primaryKey"`
+	MessageID   string `gorm:"primaryKey"`
+	EventType   string
+	ProcessedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (InboxMessage) TableName() string {
+	return "inbox"
+}
+
+type InboxRepository interface {
+	Record(ctx context.Context, messageID, eventType string) error
+}
@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrModifierGroupNotFound = errors.New("modifier group not found")
+	ErrInvalidModifierGroup  = errors.New("invalid modifier group")
+	ErrInvalidModifiers      = errors.New("invalid modifier selection")
+)
+
+type SelectionType string
+
+const (
+	SelectionSingle   SelectionType = "SINGLE"
+	SelectionMultiple SelectionType = "MULTIPLE"
+)
+
+// ModifierGroup is a set of modifiers offered for goods, such as the milk
+// types of a latte. A customer picks between MinSelected and MaxSelected of
+// them per order line. Groups are shared between goods, so a tracked modifier
+// draws on one stock whichever drink it is added to.
+type ModifierGroup struct {
+	ID            uuid.UUID     `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Name          string        `gorm:"not null"`
+	SelectionType SelectionType `gorm:"type:varchar(20);not null"`
+	MinSelected   int           `gorm:"not null;default:0"`
+	MaxSelected   int           `gorm:"not null"`
+	Modifiers     []Modifier    `gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Validate checks the selection limits and modifiers of the group.
+func (g *ModifierGroup) Validate() error {
+	switch {
+	case g.Name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidModifierGroup)
+	case g.SelectionType != SelectionSingle && g.SelectionType != SelectionMultiple:
+		return fmt.Errorf("%w: unknown selection type %q", ErrInvalidModifierGroup, g.SelectionType)
+	case g.MinSelected < 0 || g.MaxSelected < g.MinSelected:
+		return fmt.Errorf("%w: need 0 <= min_selected <= max_selected", ErrInvalidModifierGroup)
+	case g.SelectionType == SelectionSingle && g.MaxSelected != 1:
+		return fmt.Errorf("%w: single selection groups allow exactly one modifier", ErrInvalidModifierGroup)
+	case g.MaxSelected > len(g.Modifiers):
+		return fmt.Errorf("%w: max_selected exceeds the number of modifiers", ErrInvalidModifierGroup)
+	}
+	for _, modifier := range g.Modifiers {
+		switch {
+		case modifier.Name == "":
+			return fmt.Errorf("%w: modifier name is required", ErrInvalidModifierGroup)
+		case modifier.PriceDelta.Amount < 0:
+			return fmt.Errorf("%w: modifier price must not be negative", ErrInvalidModifierGroup)
+		case modifier.QuantityInStock < 0:
+			return fmt.Errorf("%w: modifier quantity must not be negative", ErrInvalidModifierGroup)
+		}
+	}
+	return nil
+}
+
+// Modifier is an option of a group priced on top of the good. Modifiers with
+// TrackStock are reserved and committed along with the goods they are added
+// to; the others are always available.
+type Modifier struct {
+	ID              uuid.UUID   `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	GroupID         uuid.UUID   `gorm:"type:uuid;not null;index"`
+	Name            string      `gorm:"not null"`
+	PriceDelta      money.Money `gorm:"embedded;embeddedPrefix:price_delta_"`
+	TrackStock      bool        `gorm:"not null;default:false"`
+	QuantityInStock int         `gorm:"not null;default:0"`
+	Position        int         `gorm:"not null;default:0"`
+	// Held is the quantity under open reservations; it is computed on read.
+	Held int `gorm:"->;-:migration"`
+}
+
+// Available is the stock that can still be reserved. It is only meaningful
+// for modifiers that track stock.
+func (m *Modifier) Available() int {
+	return m.QuantityInStock - m.Held
+}
+
+// GoodModifierGroup offers a modifier group for a good. Position orders the
+// groups of a good.
+type GoodModifierGroup struct {
+	GoodID   uuid.UUID `gorm:"type:uuid;primaryKey"`
+	GroupID  uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+	Position int       `gorm:"not null;default:0"`
+}
+
+// GoodModifiers is the modifier groups offered for a good.
+type GoodModifiers struct {
+	GoodID uuid.UUID
+	Groups []ModifierGroup
+}
+
+// SelectModifiers checks that ids is a valid choice from groups: each
+// modifier is offered, none is picked twice and every group gets between its
+// MinSelected and MaxSelected modifiers. It returns the chosen modifiers.
+func SelectModifiers(groups []ModifierGroup, ids []uuid.UUID) ([]Modifier, error) {
+	offered := make(map[uuid.UUID]Modifier)
+	for _, group := range groups {
+		for _, modifier := range group.Modifiers {
+			offered[modifier.ID] = modifier
+		}
+	}
+	chosen := make([]Modifier, 0, len(ids))
+	selected := make(map[uuid.UUID]int)
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		modifier, ok := offered[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: modifier %s is not offered", ErrInvalidModifiers, id)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("%w: modifier %s is chosen twice", ErrInvalidModifiers, id)
+		}
+		seen[id] = true
+		selected[modifier.GroupID]++
+		chosen = append(chosen, modifier)
+	}
+	for _, group := range groups {
+		if n := selected[group.ID]; n < group.MinSelected || n > group.MaxSelected {
+			return nil, fmt.Errorf("%w: %s takes %d to %d modifiers", ErrInvalidModifiers, group.Name, group.MinSelected, group.MaxSelected)
+		}
+	}
+	return chosen, nil
+}
+
+// ModifierReservation is the ledger entry of a tracked modifier reserved for
+// an order, kept like Reservation is for goods.
+type ModifierReservation struct {
+	ID         uuid.UUID        `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	OrderID    uuid.UUID        `gorm:"type:uuid;not null;uniqueIndex:idx_modifier_reservations_order_modifier"`
+	SagaID     uuid.UUID        `gorm:"type:uuid;not null"`
+	ModifierID uuid.UUID        `gorm:"type:uuid;not null;uniqueIndex:idx_modifier_reservations_order_modifier"`
+	Quantity   int              `gorm:"not null"`
+	State      ReservationState `gorm:"type:varchar(20);not null;default:'HELD'"`
+	ExpiresAt  time.Time        `gorm:"not null"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type ModifierRepository interface {
+	ListModifierGroups(ctx context.Context) ([]ModifierGroup, error)
+	SaveModifierGroup(ctx context.Context, group *ModifierGroup) error
+	SetGoodModifierGroups(ctx context.Context, goodID uuid.UUID, groupIDs []uuid.UUID) error
+	ListGoodModifiers(ctx context.Context, goodIDs []uuid.UUID) ([]GoodModifiers, error)
+}
+
+type ModifierInteractor interface {
+	ListModifierGroups(ctx context.Context) ([]ModifierGroup, error)
+	SaveModifierGroup(ctx context.Context, group ModifierGroup) (ModifierGroup, error)
+	SetGoodModifierGroups(ctx context.Context, goodID uuid.UUID, groupIDs []uuid.UUID) (GoodModifiers, error)
+	ListGoodModifiers(ctx context.Context, goodIDs []uuid.UUID) ([]GoodModifiers, error)
+}
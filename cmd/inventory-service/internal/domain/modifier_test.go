@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"errors"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestSelectModifiers(t *testing.T) {
+	milk := ModifierGroup{ID: uuid.New(), Name: "Milk", SelectionType: SelectionSingle, MinSelected: 1, MaxSelected: 1}
+	oat := Modifier{ID: uuid.New(), GroupID: milk.ID, Name: "Oat"}
+	soy := Modifier{ID: uuid.New(), GroupID: milk.ID, Name: "Soy"}
+	milk.Modifiers = []Modifier{oat, soy}
+
+	syrups := ModifierGroup{ID: uuid.New(), Name: "Syrups", SelectionType: SelectionMultiple, MinSelected: 0, MaxSelected: 2}
+	vanilla := Modifier{ID: uuid.New(), GroupID: syrups.ID, Name: "Vanilla"}
+	caramel := Modifier{ID: uuid.New(), GroupID: syrups.ID, Name: "Caramel"}
+	hazelnut := Modifier{ID: uuid.New(), GroupID: syrups.ID, Name: "Hazelnut"}
+	syrups.Modifiers = []Modifier{vanilla, caramel, hazelnut}
+
+	tests := []struct {
+		name    string
+		groups  []ModifierGroup
+		ids     []uuid.UUID
+		want    []uuid.UUID
+		wantErr error
+	}{
+		{name: "required group only", groups: []ModifierGroup{milk, syrups}, ids: []uuid.UUID{oat.ID}, want: []uuid.UUID{oat.ID}},
+		{
+			name:   "up to the maximum of an optional group",
+			groups: []ModifierGroup{milk, syrups},
+			ids:    []uuid.UUID{soy.ID, vanilla.ID, caramel.ID},
+			want:   []uuid.UUID{soy.ID, vanilla.ID, caramel.ID},
+		},
+		{name: "good without groups", ids: nil, want: []uuid.UUID{}},
+		{name: "below the minimum", groups: []ModifierGroup{milk, syrups}, ids: []uuid.UUID{vanilla.ID}, wantErr: ErrInvalidModifiers},
+		{name: "two of a single group", groups: []ModifierGroup{milk}, ids: []uuid.UUID{oat.ID, soy.ID}, wantErr: ErrInvalidModifiers},
+		{
+			name:    "above the maximum",
+			groups:  []ModifierGroup{milk, syrups},
+			ids:     []uuid.UUID{oat.ID, vanilla.ID, caramel.ID, hazelnut.ID},
+			wantErr: ErrInvalidModifiers,
+		},
+		{name: "the same modifier twice", groups: []ModifierGroup{syrups}, ids: []uuid.UUID{vanilla.ID, vanilla.ID}, wantErr: ErrInvalidModifiers},
+		// The syrups exist, but this good is not offered with them.
+		{name: "modifier of another good", groups: []ModifierGroup{milk}, ids: []uuid.UUID{oat.ID, vanilla.ID}, wantErr: ErrInvalidModifiers},
+		{name: "unknown modifier", groups: []ModifierGroup{syrups}, ids: []uuid.UUID{uuid.New()}, wantErr: ErrInvalidModifiers},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SelectModifiers(tt.groups, tt.ids)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("chose %d modifiers, want %d", len(got), len(tt.want))
+			}
+			for i, modifier := range got {
+				if modifier.ID != tt.want[i] {
+					t.Errorf("modifier %d = %s, want %s", i, modifier.ID, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestModifierGroupValidate(t *testing.T) {
+	modifiers := func(n int) []Modifier {
+		out := make([]Modifier, n)
+		for i := range out {
+			out[i] = Modifier{Name: "Syrup", PriceDelta: money.New(3000, "RUB")}
+		}
+		return out
+	}
+	tests := []struct {
+		name    string
+		group   ModifierGroup
+		wantErr error
+	}{
+		{name: "single", group: ModifierGroup{Name: "Milk", SelectionType: SelectionSingle, MaxSelected: 1, Modifiers: modifiers(2)}},
+		{name: "multiple", group: ModifierGroup{Name: "Syrups", SelectionType: SelectionMultiple, MinSelected: 1, MaxSelected: 3, Modifiers: modifiers(3)}},
+		{name: "no name", group: ModifierGroup{SelectionType: SelectionSingle, MaxSelected: 1, Modifiers: modifiers(1)}, wantErr: ErrInvalidModifierGroup},
+		{name: "unknown selection type", group: ModifierGroup{Name: "Milk", SelectionType: "ANY", MaxSelected: 1, Modifiers: modifiers(1)}, wantErr: ErrInvalidModifierGroup},
+		{name: "single with two", group: ModifierGroup{Name: "Milk", SelectionType: SelectionSingle, MaxSelected: 2, Modifiers: modifiers(2)}, wantErr: ErrInvalidModifierGroup},
+		{name: "minimum above maximum", group: ModifierGroup{Name: "Syrups", SelectionType: SelectionMultiple, MinSelected: 2, MaxSelected: 1, Modifiers: modifiers(2)}, wantErr: ErrInvalidModifierGroup},
+		{name: "maximum above modifiers", group: ModifierGroup{Name: "Syrups", SelectionType: SelectionMultiple, MaxSelected: 3, Modifiers: modifiers(2)}, wantErr: ErrInvalidModifierGroup},
+		{
+			name: "negative price",
+			group: ModifierGroup{Name: "Milk", SelectionType: SelectionSingle, MaxSelected: 1, Modifiers: []Modifier{
+				{Name: "Skimmed", PriceDelta: money.New(-1000, "RUB")},
+			}},
+			wantErr: ErrInvalidModifierGroup,
+		},
+		{
+			name: "negative stock",
+			group: ModifierGroup{Name: "Milk", SelectionType: SelectionSingle, MaxSelected: 1, Modifiers: []Modifier{
+				{Name: "Oat", TrackStock: true, QuantityInStock: -1},
+			}},
+			wantErr: ErrInvalidModifierGroup,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.group.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
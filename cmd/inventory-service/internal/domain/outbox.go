@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"context"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+)
+
+// OutboxRepository writes events to the outbox, published to Kafka by the
+// relay once the transaction in ctx commits.
+type OutboxRepository interface {
+	Enqueue(ctx context.Context, topic, aggregateID string, event events.Event) error
+}
+
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
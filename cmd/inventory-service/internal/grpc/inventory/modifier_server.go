@@ -0,0 +1,85 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"immxrtalbeast/order_microservices/internal/pkg/inventorypb"
+	"immxrtalbeast/order_microservices/inventory-service/internal/domain"
+	"immxrtalbeast/order_microservices/inventory-service/internal/lib"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type modifierServerAPI struct {
+	inventorypb.UnimplementedModifierServiceServer
+	modifierInteractor domain.ModifierInteractor
+}
+
+func (s *modifierServerAPI) ListModifierGroups(ctx context.Context, in *inventorypb.ListModifierGroupsRequest) (*inventorypb.ListModifierGroupsResponse, error) {
+	groups, err := s.modifierInteractor.ListModifierGroups(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to get modifier groups")
+	}
+	return &inventorypb.ListModifierGroupsResponse{Groups: lib.ConvertModifierGroupsToProto(groups)}, nil
+}
+
+func (s *modifierServerAPI) SaveModifierGroup(ctx context.Context, in *inventorypb.SaveModifierGroupRequest) (*inventorypb.ModifierGroup, error) {
+	if in.GetGroup() == nil {
+		return nil, status.Error(codes.InvalidArgument, "group is required")
+	}
+	group, err := lib.ConvertProtoToModifierGroup(in.GetGroup())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid modifier group ID format")
+	}
+
+	saved, err := s.modifierInteractor.SaveModifierGroup(ctx, group)
+	if err != nil {
+		return nil, modifierError(err, "failed to save modifier group")
+	}
+	return lib.ConvertModifierGroupsToProto([]domain.ModifierGroup{saved})[0], nil
+}
+
+func (s *modifierServerAPI) SetGoodModifierGroups(ctx context.Context, in *inventorypb.SetGoodModifierGroupsRequest) (*inventorypb.GoodModifiers, error) {
+	goodID, err := uuid.Parse(in.GetGoodId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid good ID format")
+	}
+	groupIDs, err := parseIDs(in.GetGroupIds())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid modifier group ID format")
+	}
+
+	good, err := s.modifierInteractor.SetGoodModifierGroups(ctx, goodID, groupIDs)
+	if err != nil {
+		return nil, modifierError(err, "failed to set modifier groups")
+	}
+	return lib.ConvertGoodModifiersToProto([]domain.GoodModifiers{good})[0], nil
+}
+
+func (s *modifierServerAPI) ListGoodModifiers(ctx context.Context, in *inventorypb.ListGoodModifiersRequest) (*inventorypb.ListGoodModifiersResponse, error) {
+	goodIDs, err := parseIDs(in.GetGoodIds())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid good ID format")
+	}
+
+	goods, err := s.modifierInteractor.ListGoodModifiers(ctx, goodIDs)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to get modifiers of goods")
+	}
+	return &inventorypb.ListGoodModifiersResponse{Goods: lib.ConvertGoodModifiersToProto(goods)}, nil
+}
+
+func modifierError(err error, msg string) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidModifierGroup):
+		return status.Error(codes.InvalidArgument, errors.Unwrap(err).Error())
+	case errors.Is(err, domain.ErrModifierGroupNotFound):
+		return status.Error(codes.NotFound, "modifier group not found")
+	case errors.Is(err, domain.ErrGoodNotFound):
+		return status.Error(codes.NotFound, "good not found")
+	default:
+		return status.Error(codes.Internal, msg)
+	}
+}
@@ -2,6 +2,8 @@ package grpc
 
 import (
 	"context"
+	"immxrtalbeast/order_microservices/internal/pkg/inventorypb"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
 	"immxrtalbeast/order_microservices/inventory-service/internal/domain"
 	"immxrtalbeast/order_microservices/inventory-service/internal/lib"
 
@@ -18,8 +20,10 @@ type serverAPI struct {
 	inventoryInteractor domain.InventoryInteractor
 }
 
-func Register(gRPCServer *grpc.Server, inventoryInteractor domain.InventoryInteractor) {
+func Register(gRPCServer *grpc.Server, inventoryInteractor domain.InventoryInteractor, modifierInteractor domain.ModifierInteractor) {
 	inventory.RegisterInventoryServer(gRPCServer, &serverAPI{inventoryInteractor: inventoryInteractor})
+	inventorypb.RegisterStockServiceServer(gRPCServer, &stockServerAPI{inventoryInteractor: inventoryInteractor})
+	inventorypb.RegisterModifierServiceServer(gRPCServer, &modifierServerAPI{modifierInteractor: modifierInteractor})
 }
 
 func (s *serverAPI) AddGood(ctx context.Context, in *inventory.AddGoodRequest) (*inventory.AddGoodResponse, error) {
@@ -36,7 +40,7 @@ func (s *serverAPI) AddGood(ctx context.Context, in *inventory.AddGoodRequest) (
 		return nil, status.Error(codes.InvalidArgument, "quantity should be equal/greater than 0")
 	}
 
-	if err := s.inventoryInteractor.AddGood(ctx, in.Name, in.Category, in.Description, in.ImageLink, int(in.Price), int(in.Volume), int(in.QuantityInStock)); err != nil {
+	if err := s.inventoryInteractor.AddGood(ctx, in.Name, in.Category, in.Description, in.ImageLink, money.FromFloat(in.Price, money.DefaultCurrency), int(in.Volume), int(in.QuantityInStock)); err != nil {
 		return nil, status.Error(codes.Internal, "failed to save good")
 	}
 	return &inventory.AddGoodResponse{Success: true}, nil
@@ -71,7 +75,7 @@ func (s *serverAPI) UpdateGood(ctx context.Context, in *inventory.UpdateGoodRequ
 	if in.QuantityInStock < 0 {
 		return nil, status.Error(codes.InvalidArgument, "quantity should be equal/greater than 0")
 	}
-	if err := s.inventoryInteractor.UpdateGood(ctx, good_id, in.Name, in.Category, in.Description, in.ImageLink, int(in.Price), int(in.Volume), int(in.QuantityInStock)); err != nil {
+	if err := s.inventoryInteractor.UpdateGood(ctx, good_id, in.Name, in.Category, in.Description, in.ImageLink, money.FromFloat(in.Price, money.DefaultCurrency), int(in.Volume), int(in.QuantityInStock)); err != nil {
 		return nil, status.Error(codes.InvalidArgument, "failed to update good")
 	}
 	return &inventory.UpdateGoodResponse{Success: true}, nil
@@ -0,0 +1,55 @@
+package grpc
+
+import (
+	"context"
+	"immxrtalbeast/order_microservices/internal/pkg/inventorypb"
+	"immxrtalbeast/order_microservices/inventory-service/internal/domain"
+	"immxrtalbeast/order_microservices/inventory-service/internal/lib"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type stockServerAPI struct {
+	inventorypb.UnimplementedStockServiceServer
+	inventoryInteractor domain.InventoryInteractor
+}
+
+func (s *stockServerAPI) ListStock(ctx context.Context, in *inventorypb.ListStockRequest) (*inventorypb.ListStockResponse, error) {
+	goodIDs, err := parseIDs(in.GetGoodIds())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid good ID format")
+	}
+
+	goods, err := s.inventoryInteractor.ListProducts(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to get stock")
+	}
+	if len(goodIDs) > 0 {
+		wanted := make(map[uuid.UUID]bool, len(goodIDs))
+		for _, id := range goodIDs {
+			wanted[id] = true
+		}
+		filtered := goods[:0]
+		for _, good := range goods {
+			if wanted[good.ID] {
+				filtered = append(filtered, good)
+			}
+		}
+		goods = filtered
+	}
+	return &inventorypb.ListStockResponse{Goods: lib.ConvertGoodsToStock(goods)}, nil
+}
+
+func parseIDs(in []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, len(in))
+	for i, s := range in {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
@@ -1,8 +1,13 @@
 package lib
 
 import (
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	"immxrtalbeast/order_microservices/internal/pkg/inventorypb"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
 	"immxrtalbeast/order_microservices/inventory-service/internal/domain"
+	"strings"
 
+	"github.com/google/uuid"
 	inventory "github.com/ozzus/order_protos/gen/go/inventory"
 )
 
@@ -15,7 +20,7 @@ func ConvertGoodToProduct(dbGoods []*domain.Good) []*inventory.Product {
 			Category:        g.Category,
 			ImageLink:       g.ImageLink,
 			Description:     g.Description,
-			Price:           float64(g.Price),
+			Price:           g.Price.Float(),
 			Volume:          int32(g.Volume),
 			QuantityInStock: int64(g.QuantityInStock),
 		}
@@ -23,3 +28,133 @@ func ConvertGoodToProduct(dbGoods []*domain.Good) []*inventory.Product {
 	}
 	return pbProducts
 }
+
+func ConvertGoodsToStock(goods []*domain.Good) []*inventorypb.GoodStock {
+	stock := make([]*inventorypb.GoodStock, len(goods))
+	for i, g := range goods {
+		stock[i] = &inventorypb.GoodStock{
+			GoodId:            g.ID.String(),
+			QuantityInStock:   int64(g.QuantityInStock),
+			QuantityHeld:      int64(g.Held),
+			QuantityAvailable: int64(g.Available()),
+		}
+	}
+	return stock
+}
+
+func ConvertEventItemsToItems(items []events.Item) []domain.OrderItem {
+	orderItems := make([]domain.OrderItem, len(items))
+	for i, item := range items {
+		orderItems[i] = domain.OrderItem{GoodID: item.ProductID, Quantity: item.Quantity}
+		for _, modifier := range item.Modifiers {
+			orderItems[i].ModifierIDs = append(orderItems[i].ModifierIDs, modifier.ModifierID)
+		}
+	}
+	return orderItems
+}
+
+func ConvertItemsToEventItems(items []domain.OrderItem) []events.Item {
+	eventItems := make([]events.Item, len(items))
+	for i, item := range items {
+		eventItems[i] = events.Item{ProductID: item.GoodID, Quantity: item.Quantity}
+	}
+	return eventItems
+}
+
+// ConvertReservedItemsToEventItems expects items that passed ReservedTotal, so
+// their prices share a currency.
+func ConvertReservedItemsToEventItems(items []domain.ReservedItem) []events.Item {
+	eventItems := make([]events.Item, len(items))
+	for i, item := range items {
+		price, _ := item.UnitPrice()
+		eventItems[i] = events.Item{
+			ProductID: item.GoodID,
+			Quantity:  item.Quantity,
+			Name:      item.Name,
+			Volume:    item.Volume,
+			Price:     price,
+		}
+		for _, modifier := range item.Modifiers {
+			eventItems[i].Modifiers = append(eventItems[i].Modifiers, events.ItemModifier{
+				ModifierID: modifier.ID,
+				Name:       modifier.Name,
+				PriceDelta: modifier.PriceDelta,
+			})
+		}
+	}
+	return eventItems
+}
+
+const selectionTypeProtoPrefix = "SELECTION_TYPE_"
+
+func ConvertModifierGroupsToProto(groups []domain.ModifierGroup) []*inventorypb.ModifierGroup {
+	pbGroups := make([]*inventorypb.ModifierGroup, len(groups))
+	for i, group := range groups {
+		pbGroups[i] = &inventorypb.ModifierGroup{
+			Id:            group.ID.String(),
+			Name:          group.Name,
+			SelectionType: inventorypb.SelectionType(inventorypb.SelectionType_value[selectionTypeProtoPrefix+string(group.SelectionType)]),
+			MinSelected:   int32(group.MinSelected),
+			MaxSelected:   int32(group.MaxSelected),
+			Modifiers:     make([]*inventorypb.Modifier, len(group.Modifiers)),
+		}
+		for j, modifier := range group.Modifiers {
+			pbGroups[i].Modifiers[j] = &inventorypb.Modifier{
+				Id:                modifier.ID.String(),
+				Name:              modifier.Name,
+				PriceDelta:        &inventorypb.Money{Amount: modifier.PriceDelta.Amount, Currency: modifier.PriceDelta.Currency},
+				TrackStock:        modifier.TrackStock,
+				QuantityInStock:   int64(modifier.QuantityInStock),
+				QuantityAvailable: int64(modifier.Available()),
+			}
+		}
+	}
+	return pbGroups
+}
+
+func ConvertGoodModifiersToProto(goods []domain.GoodModifiers) []*inventorypb.GoodModifiers {
+	pbGoods := make([]*inventorypb.GoodModifiers, len(goods))
+	for i, good := range goods {
+		pbGoods[i] = &inventorypb.GoodModifiers{
+			GoodId: good.GoodID.String(),
+			Groups: ConvertModifierGroupsToProto(good.Groups),
+		}
+	}
+	return pbGoods
+}
+
+// ConvertProtoToModifierGroup reads a group to save. Modifier positions follow
+// their order in the request.
+func ConvertProtoToModifierGroup(in *inventorypb.ModifierGroup) (domain.ModifierGroup, error) {
+	group := domain.ModifierGroup{
+		Name:          in.GetName(),
+		SelectionType: domain.SelectionType(strings.TrimPrefix(in.GetSelectionType().String(), selectionTypeProtoPrefix)),
+		MinSelected:   int(in.GetMinSelected()),
+		MaxSelected:   int(in.GetMaxSelected()),
+		Modifiers:     make([]domain.Modifier, len(in.GetModifiers())),
+	}
+	var err error
+	if in.GetId() != "" {
+		if group.ID, err = uuid.Parse(in.GetId()); err != nil {
+			return domain.ModifierGroup{}, err
+		}
+	}
+	for i, modifier := range in.GetModifiers() {
+		group.Modifiers[i] = domain.Modifier{
+			Name:            modifier.GetName(),
+			PriceDelta:      money.New(modifier.GetPriceDelta().GetAmount(), modifier.GetPriceDelta().GetCurrency()),
+			TrackStock:      modifier.GetTrackStock(),
+			QuantityInStock: int(modifier.GetQuantityInStock()),
+			Position:        i,
+		}
+		if group.Modifiers[i].PriceDelta.Currency == "" {
+			group.Modifiers[i].PriceDelta.Currency = money.DefaultCurrency
+		}
+		if modifier.GetId() != "" {
+			if group.Modifiers[i].ID, err = uuid.Parse(modifier.GetId()); err != nil {
+				return domain.ModifierGroup{}, err
+			}
+		}
+	}
+	return group, nil
+}
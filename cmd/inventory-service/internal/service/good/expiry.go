@@ -0,0 +1,63 @@
+package good
+
+import (
+	"context"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	"immxrtalbeast/order_microservices/inventory-service/internal/domain"
+	"immxrtalbeast/order_microservices/inventory-service/internal/lib/logger/sl"
+	"log/slog"
+	"time"
+)
+
+// RunExpiryJob periodically expires holds that outlived their expiry
+// until ctx is cancelled.
+func (gi *GoodInteractor) RunExpiryJob(ctx context.Context, interval time.Duration, batchSize int) {
+	gi.log.Info("reservation expiry job started", slog.Duration("interval", interval))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			gi.log.Info("reservation expiry job stopped")
+			return
+		case <-ticker.C:
+			gi.ExpireHolds(ctx, batchSize)
+		}
+	}
+}
+
+// ExpireHolds expires overdue holds and tells the saga of each order, so it
+// fails the order instead of committing stock that is no longer set aside.
+func (gi *GoodInteractor) ExpireHolds(ctx context.Context, batchSize int) {
+	const op = "service.good.expireHolds"
+	log := gi.log.With(
+		slog.String("op", op),
+	)
+	for {
+		var expired []domain.ExpiredHold
+		err := gi.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			expired, err = gi.goodRepo.ExpireHolds(ctx, time.Now(), batchSize)
+			if err != nil {
+				return err
+			}
+			for _, hold := range expired {
+				reply := events.InventoryHoldExpired{OrderID: hold.OrderID, SagaID: hold.SagaID}
+				if err := gi.enqueue(ctx, hold.OrderID, reply); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			log.Error("failed to expire holds", sl.Err(err))
+			return
+		}
+		if len(expired) > 0 {
+			log.Info("holds expired", slog.Int("orders", len(expired)))
+		}
+		if len(expired) < batchSize {
+			return
+		}
+	}
+}
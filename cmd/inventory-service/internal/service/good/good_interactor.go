@@ -2,29 +2,36 @@ package good
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
 	"immxrtalbeast/order_microservices/inventory-service/internal/domain"
+	"immxrtalbeast/order_microservices/inventory-service/internal/lib"
 	"immxrtalbeast/order_microservices/inventory-service/internal/lib/logger/sl"
 	"log/slog"
-
-	kafka "github.com/ozzus/order_kafka"
+	"time"
 
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 )
 
+const sagaRepliesTopic = "saga-replies"
+
 type GoodInteractor struct {
-	log      *slog.Logger
-	goodRepo domain.GoodRepository
-	producer *kafka.Producer
+	log        *slog.Logger
+	goodRepo   domain.GoodRepository
+	outboxRepo domain.OutboxRepository
+	transactor domain.Transactor
+	holdTTL    time.Duration
 }
 
-func NewGoodInteractor(goodRepo domain.GoodRepository, log *slog.Logger, producer *kafka.Producer) *GoodInteractor {
-	return &GoodInteractor{goodRepo: goodRepo, log: log, producer: producer}
+func NewGoodInteractor(goodRepo domain.GoodRepository, outboxRepo domain.OutboxRepository, transactor domain.Transactor, log *slog.Logger, holdTTL time.Duration) *GoodInteractor {
+	return &GoodInteractor{goodRepo: goodRepo, outboxRepo: outboxRepo, transactor: transactor, log: log, holdTTL: holdTTL}
 }
 
-func (gi *GoodInteractor) AddGood(ctx context.Context, name, category, description, imageLink string, price, volume, quantityInStock int) error {
+func (gi *GoodInteractor) AddGood(ctx context.Context, name, category, description, imageLink string, price money.Money, volume, quantityInStock int) error {
 	const op = "service.good.save"
 	log := gi.log.With(
 		slog.String("op", op),
@@ -101,7 +108,7 @@ func (gi *GoodInteractor) DeleteGood(ctx context.Context, goodID uuid.UUID) erro
 	return nil
 }
 
-func (gi *GoodInteractor) UpdateGood(ctx context.Context, goodID uuid.UUID, name, category, description, imageLink string, price, volume, quantityInStock int) error {
+func (gi *GoodInteractor) UpdateGood(ctx context.Context, goodID uuid.UUID, name, category, description, imageLink string, price money.Money, volume, quantityInStock int) error {
 	const op = "service.good.update"
 	log := gi.log.With(
 		slog.String("op", op),
@@ -110,7 +117,7 @@ func (gi *GoodInteractor) UpdateGood(ctx context.Context, goodID uuid.UUID, name
 		slog.String("category", category),
 		slog.String("description", description),
 		slog.String("imageLink", imageLink),
-		slog.Int("price", price),
+		slog.String("price", price.String()),
 		slog.Int("volume", volume),
 		slog.Int("quantity", quantityInStock),
 	)
@@ -140,41 +147,202 @@ func (gi *GoodInteractor) UpdateGood(ctx context.Context, goodID uuid.UUID, name
 	return nil
 }
 
-func (gi *GoodInteractor) ReserveProducts(ctx context.Context, event domain.ReserveProductsEvent) {
-	const op = "service.good.update"
+func (gi *GoodInteractor) ReserveProducts(ctx context.Context, command events.ReserveInventory) error {
+	const op = "service.good.reserve"
 	log := gi.log.With(
 		slog.String("op", op),
-		slog.String("order_id", event.OrderID.String()),
-		slog.String("saga_id", event.SagaID.String()),
-		slog.Any("products", event.Products),
+		slog.String("order_id", command.OrderID.String()),
+		slog.String("saga_id", command.SagaID.String()),
+		slog.Any("products", command.Items),
 	)
 	log.Info("reserving goods")
 	tracer := otel.Tracer("inventory-service")
 	ctx, span := tracer.Start(ctx, "InvetoryService.ReserveProducts")
 	span.SetAttributes(
-		attribute.String("saga.id", event.SagaID.String()),
+		attribute.String("saga.id", command.SagaID.String()),
 	)
 	defer span.End()
-	order_sum, err := gi.goodRepo.ReserveProducts(ctx, event.Products)
+	err := gi.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		reserved, err := gi.goodRepo.ReserveProducts(ctx, command.OrderID, command.SagaID, lib.ConvertEventItemsToItems(command.Items), time.Now().Add(gi.holdTTL))
+		if err != nil {
+			span.RecordError(err)
+			log.Error("failed to reserve products", sl.Err(err))
+			failed := events.InventoryReserveFailed{
+				OrderID: command.OrderID,
+				SagaID:  command.SagaID,
+				Items:   command.Items,
+				Reason:  err.Error(),
+			}
+			return gi.enqueue(ctx, command.OrderID, failed)
+		}
+		total, err := domain.ReservedTotal(reserved)
+		if err != nil {
+			return err
+		}
+		reply := events.InventoryReserved{
+			OrderID: command.OrderID,
+			SagaID:  command.SagaID,
+			Items:   lib.ConvertReservedItemsToEventItems(reserved),
+			Total:   total,
+		}
+		log.Info("goods reserved")
+		return gi.enqueue(ctx, command.OrderID, reply)
+	})
 	if err != nil {
 		span.RecordError(err)
-		log.Error("failed to reserve products", sl.Err(err))
-		if err := gi.producer.PublishEventWithEventType(ctx, "InventoryReservedEventFailed", event, "InventoryReservedEventFailed"); err != nil {
+		log.Error("failed to enqueue reply", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (gi *GoodInteractor) CommitProducts(ctx context.Context, command events.CommitInventory) error {
+	const op = "service.good.commit"
+	log := gi.log.With(
+		slog.String("op", op),
+		slog.String("order_id", command.OrderID.String()),
+		slog.String("saga_id", command.SagaID.String()),
+	)
+	log.Info("committing goods")
+	tracer := otel.Tracer("inventory-service")
+	ctx, span := tracer.Start(ctx, "InvetoryService.CommitProducts")
+	span.SetAttributes(
+		attribute.String("saga.id", command.SagaID.String()),
+		attribute.String("order.id", command.OrderID.String()),
+	)
+	defer span.End()
+	err := gi.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		committed, err := gi.goodRepo.CommitProducts(ctx, command.OrderID)
+		switch {
+		case errors.Is(err, domain.ErrAlreadyCommitted):
+			log.Warn("reservation already committed")
+		case errors.Is(err, domain.ErrHoldExpired):
+			log.Warn("reservation expired before commit")
+			expired := events.InventoryHoldExpired{
+				OrderID: command.OrderID,
+				SagaID:  command.SagaID,
+			}
+			return gi.enqueue(ctx, command.OrderID, expired)
+		case err != nil:
 			span.RecordError(err)
-			log.Error("Failed to publish event", sl.Err(err))
+			log.Error("failed to commit products", sl.Err(err))
+			failed := events.InventoryCommitFailed{
+				OrderID: command.OrderID,
+				SagaID:  command.SagaID,
+				Reason:  err.Error(),
+			}
+			return gi.enqueue(ctx, command.OrderID, failed)
+		}
+		reply := events.InventoryCommitted{
+			OrderID: command.OrderID,
+			SagaID:  command.SagaID,
+			Items:   lib.ConvertItemsToEventItems(committed),
 		}
-		return
+		log.Info("goods committed", slog.Any("products", committed))
+		return gi.enqueue(ctx, command.OrderID, reply)
+	})
+	if err != nil {
+		span.RecordError(err)
+		log.Error("failed to enqueue reply", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
 	}
-	reply := domain.ReserveProductsEventReply{
-		OrderID:  event.OrderID,
-		SagaID:   event.SagaID,
-		Products: event.Products,
-		TotalSum: order_sum,
+	return nil
+}
+
+func (gi *GoodInteractor) ReleaseProducts(ctx context.Context, command events.ReleaseInventory) error {
+	const op = "service.good.release"
+	log := gi.log.With(
+		slog.String("op", op),
+		slog.String("order_id", command.OrderID.String()),
+		slog.String("saga_id", command.SagaID.String()),
+	)
+	log.Info("releasing goods")
+	tracer := otel.Tracer("inventory-service")
+	ctx, span := tracer.Start(ctx, "InvetoryService.ReleaseProducts")
+	span.SetAttributes(
+		attribute.String("saga.id", command.SagaID.String()),
+		attribute.String("order.id", command.OrderID.String()),
+	)
+	defer span.End()
+	err := gi.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		released, err := gi.goodRepo.ReleaseProducts(ctx, command.OrderID)
+		switch {
+		case errors.Is(err, domain.ErrAlreadyReleased), errors.Is(err, domain.ErrReservationNotFound):
+			log.Warn("nothing to release", sl.Err(err))
+		case err != nil:
+			span.RecordError(err)
+			log.Error("failed to release products", sl.Err(err))
+			failed := events.InventoryReleaseFailed{
+				OrderID: command.OrderID,
+				SagaID:  command.SagaID,
+				Reason:  err.Error(),
+			}
+			return gi.enqueue(ctx, command.OrderID, failed)
+		}
+		reply := events.InventoryReleased{
+			OrderID: command.OrderID,
+			SagaID:  command.SagaID,
+			Items:   lib.ConvertItemsToEventItems(released),
+		}
+		log.Info("goods released", slog.Any("products", released))
+		return gi.enqueue(ctx, command.OrderID, reply)
+	})
+	if err != nil {
+		span.RecordError(err)
+		log.Error("failed to enqueue reply", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
 	}
-	log.Info("goods reserved")
-	if err := gi.producer.PublishEventWithEventType(ctx, "InventoryReservedEvent", reply, "InventoryReservedEvent"); err != nil {
+	return nil
+}
+
+func (gi *GoodInteractor) RestockProducts(ctx context.Context, command events.RestockInventory) error {
+	const op = "service.good.restock"
+	log := gi.log.With(
+		slog.String("op", op),
+		slog.String("order_id", command.OrderID.String()),
+		slog.String("saga_id", command.SagaID.String()),
+	)
+	log.Info("restocking goods")
+	tracer := otel.Tracer("inventory-service")
+	ctx, span := tracer.Start(ctx, "InvetoryService.RestockProducts")
+	span.SetAttributes(
+		attribute.String("saga.id", command.SagaID.String()),
+		attribute.String("order.id", command.OrderID.String()),
+	)
+	defer span.End()
+	err := gi.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		restocked, err := gi.goodRepo.RestockProducts(ctx, command.OrderID, command.SagaID, lib.ConvertEventItemsToItems(command.Items))
+		switch {
+		case errors.Is(err, domain.ErrAlreadyRestocked):
+			log.Warn("goods already restocked")
+			restocked = lib.ConvertEventItemsToItems(command.Items)
+		case err != nil:
+			span.RecordError(err)
+			log.Error("failed to restock products", sl.Err(err))
+			failed := events.InventoryRestockFailed{
+				OrderID: command.OrderID,
+				SagaID:  command.SagaID,
+				Reason:  err.Error(),
+			}
+			return gi.enqueue(ctx, command.OrderID, failed)
+		}
+		reply := events.InventoryRestocked{
+			OrderID: command.OrderID,
+			SagaID:  command.SagaID,
+			Items:   lib.ConvertItemsToEventItems(restocked),
+		}
+		log.Info("goods restocked", slog.Any("products", restocked))
+		return gi.enqueue(ctx, command.OrderID, reply)
+	})
+	if err != nil {
 		span.RecordError(err)
-		log.Error("Failed to publish event", sl.Err(err))
+		log.Error("failed to enqueue reply", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
 	}
+	return nil
+}
 
+// enqueue writes a reply to the outbox within the caller's transaction.
+func (gi *GoodInteractor) enqueue(ctx context.Context, orderID uuid.UUID, event events.Event) error {
+	return gi.outboxRepo.Enqueue(ctx, sagaRepliesTopic, orderID.String(), event)
 }
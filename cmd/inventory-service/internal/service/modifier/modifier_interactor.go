@@ -0,0 +1,115 @@
+package modifier
+
+import (
+	"context"
+	"fmt"
+	"immxrtalbeast/order_microservices/inventory-service/internal/domain"
+	"immxrtalbeast/order_microservices/inventory-service/internal/lib/logger/sl"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type ModifierInteractor struct {
+	log          *slog.Logger
+	modifierRepo domain.ModifierRepository
+}
+
+func NewModifierInteractor(modifierRepo domain.ModifierRepository, log *slog.Logger) *ModifierInteractor {
+	return &ModifierInteractor{modifierRepo: modifierRepo, log: log}
+}
+
+func (mi *ModifierInteractor) ListModifierGroups(ctx context.Context) ([]domain.ModifierGroup, error) {
+	const op = "service.modifier.listGroups"
+	log := mi.log.With(
+		slog.String("op", op),
+	)
+	tracer := otel.Tracer("inventory-service")
+	ctx, span := tracer.Start(ctx, "ModifierService.ListModifierGroups")
+	defer span.End()
+	groups, err := mi.modifierRepo.ListModifierGroups(ctx)
+	if err != nil {
+		log.Error("failed to list modifier groups", sl.Err(err))
+		span.RecordError(err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return groups, nil
+}
+
+// SaveModifierGroup creates the group when it has no ID and replaces the
+// stored one otherwise.
+func (mi *ModifierInteractor) SaveModifierGroup(ctx context.Context, group domain.ModifierGroup) (domain.ModifierGroup, error) {
+	const op = "service.modifier.saveGroup"
+	log := mi.log.With(
+		slog.String("op", op),
+		slog.String("group_id", group.ID.String()),
+		slog.String("name", group.Name),
+	)
+	tracer := otel.Tracer("inventory-service")
+	ctx, span := tracer.Start(ctx, "ModifierService.SaveModifierGroup")
+	span.SetAttributes(
+		attribute.String("modifier_group.id", group.ID.String()),
+	)
+	defer span.End()
+	if err := group.Validate(); err != nil {
+		log.Warn("invalid modifier group", sl.Err(err))
+		return domain.ModifierGroup{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := mi.modifierRepo.SaveModifierGroup(ctx, &group); err != nil {
+		log.Error("failed to save modifier group", sl.Err(err))
+		span.RecordError(err)
+		return domain.ModifierGroup{}, fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("modifier group saved", slog.String("group_id", group.ID.String()))
+	return group, nil
+}
+
+func (mi *ModifierInteractor) SetGoodModifierGroups(ctx context.Context, goodID uuid.UUID, groupIDs []uuid.UUID) (domain.GoodModifiers, error) {
+	const op = "service.modifier.setGoodGroups"
+	log := mi.log.With(
+		slog.String("op", op),
+		slog.String("good_id", goodID.String()),
+		slog.Int("groups", len(groupIDs)),
+	)
+	tracer := otel.Tracer("inventory-service")
+	ctx, span := tracer.Start(ctx, "ModifierService.SetGoodModifierGroups")
+	span.SetAttributes(
+		attribute.String("good.id", goodID.String()),
+	)
+	defer span.End()
+	if err := mi.modifierRepo.SetGoodModifierGroups(ctx, goodID, groupIDs); err != nil {
+		log.Error("failed to set modifier groups of good", sl.Err(err))
+		span.RecordError(err)
+		return domain.GoodModifiers{}, fmt.Errorf("%s: %w", op, err)
+	}
+	goods, err := mi.modifierRepo.ListGoodModifiers(ctx, []uuid.UUID{goodID})
+	if err != nil {
+		log.Error("failed to read modifier groups of good", sl.Err(err))
+		span.RecordError(err)
+		return domain.GoodModifiers{}, fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("modifier groups of good set")
+	if len(goods) == 0 {
+		return domain.GoodModifiers{GoodID: goodID}, nil
+	}
+	return goods[0], nil
+}
+
+func (mi *ModifierInteractor) ListGoodModifiers(ctx context.Context, goodIDs []uuid.UUID) ([]domain.GoodModifiers, error) {
+	const op = "service.modifier.listGoodModifiers"
+	log := mi.log.With(
+		slog.String("op", op),
+	)
+	tracer := otel.Tracer("inventory-service")
+	ctx, span := tracer.Start(ctx, "ModifierService.ListGoodModifiers")
+	defer span.End()
+	goods, err := mi.modifierRepo.ListGoodModifiers(ctx, goodIDs)
+	if err != nil {
+		log.Error("failed to list modifiers of goods", sl.Err(err))
+		span.RecordError(err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return goods, nil
+}
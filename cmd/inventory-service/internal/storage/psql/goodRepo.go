@@ -3,6 +3,7 @@ package psql
 import (
 	"context"
 	"errors"
+	"time"
 
 	"immxrtalbeast/order_microservices/inventory-service/internal/domain"
 
@@ -20,25 +21,32 @@ func NewGoodRepository(db *gorm.DB) *GoodRepository {
 }
 
 func (r *GoodRepository) SaveGood(ctx context.Context, good *domain.Good) error {
-	err := r.db.WithContext(ctx).Create(&good).Error
+	err := conn(ctx, r.db).Create(&good).Error
 	return err
 }
 
 func (r *GoodRepository) DeleteGood(ctx context.Context, goodID uuid.UUID) error {
-	return r.db.WithContext(ctx).Where("id = ?", goodID).Delete(&domain.Good{}).Error
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("good_id = ?", goodID).Delete(&domain.GoodModifierGroup{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", goodID).Delete(&domain.Good{}).Error
+	})
 }
 
 func (r *GoodRepository) ListGoods(ctx context.Context) ([]*domain.Good, error) {
 	var goods []*domain.Good
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).
 		Model(&domain.Good{}).
+		Select("goods.*, COALESCE(h.held, 0) AS held").
+		Joins("LEFT JOIN (?) AS h ON h.good_id = goods.id", heldQuery(r.db)).
 		Scan(&goods).
 		Error
 	return goods, err
 }
 
 func (r *GoodRepository) UpdateGood(ctx context.Context, good *domain.Good) error {
-	result := r.db.WithContext(ctx).Model(&domain.Good{}).
+	result := conn(ctx, r.db).Model(&domain.Good{}).
 		Where("id = ?", good.ID).
 		Omit("id").
 		Updates(&good)
@@ -46,10 +54,15 @@ func (r *GoodRepository) UpdateGood(ctx context.Context, good *domain.Good) erro
 	return result.Error
 }
 
-func (r *GoodRepository) ReserveProducts(ctx context.Context, orderItems []domain.OrderItem) (int, error) {
-	var total int
+// ReserveProducts places HELD reservations for the order, for its goods and the
+// modifiers among its chosen ones that track stock. Goods and modifiers are
+// locked while checking availability so concurrent holds cannot oversell. If
+// the order is already reserved nothing changes and its lines are returned
+// again.
+func (r *GoodRepository) ReserveProducts(ctx context.Context, orderID, sagaID uuid.UUID, orderItems []domain.OrderItem, expiresAt time.Time) ([]domain.ReservedItem, error) {
+	var reserved []domain.ReservedItem
 
-	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
 		quantityByGoodID := make(map[uuid.UUID]int, len(orderItems))
 		for _, item := range orderItems {
 			if item.Quantity <= 0 {
@@ -70,43 +83,412 @@ func (r *GoodRepository) ReserveProducts(ctx context.Context, orderItems []domai
 			return err
 		}
 
+		var held []struct {
+			GoodID uuid.UUID
+			Held   int
+		}
+		if err := heldQuery(tx).Where("good_id IN ?", goodIDs).Scan(&held).Error; err != nil {
+			return err
+		}
+		heldByGoodID := make(map[uuid.UUID]int, len(held))
+		for _, h := range held {
+			heldByGoodID[h.GoodID] = h.Held
+		}
+
 		goodMap := make(map[uuid.UUID]domain.Good)
 		for _, good := range goods {
+			good.Held = heldByGoodID[good.ID]
 			goodMap[good.ID] = good
 		}
 
-		total = 0
-		updates := make(map[uuid.UUID]int)
+		modifiers, err := goodModifiers(tx, goodIDs)
+		if err != nil {
+			return err
+		}
+		groupsByGoodID := make(map[uuid.UUID][]domain.ModifierGroup, len(modifiers))
+		for _, good := range modifiers {
+			groupsByGoodID[good.GoodID] = good.Groups
+		}
+
+		reserved = make([]domain.ReservedItem, 0, len(orderItems))
+		quantityByModifierID := make(map[uuid.UUID]int)
+		for _, item := range orderItems {
+			chosen, err := domain.SelectModifiers(groupsByGoodID[item.GoodID], item.ModifierIDs)
+			if err != nil {
+				return err
+			}
+			for _, modifier := range chosen {
+				if modifier.TrackStock {
+					quantityByModifierID[modifier.ID] += item.Quantity
+				}
+			}
+			reserved = append(reserved, reservedItem(goodMap[item.GoodID], item.GoodID, chosen, item.Quantity))
+		}
+
+		var existing []domain.Reservation
+		if err := tx.Where("order_id = ?", orderID).Find(&existing).Error; err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			return nil
+		}
+
+		reservations := make([]domain.Reservation, 0, len(quantityByGoodID))
 
 		for goodID, requestedQuantity := range quantityByGoodID {
 			good, exists := goodMap[goodID]
 			if !exists {
-				return errors.New("good not found")
+				return domain.ErrGoodNotFound
 			}
 
-			total += good.Price * requestedQuantity
-
-			newQuantity := good.QuantityInStock - requestedQuantity
-			if newQuantity < 0 {
-				return errors.New("insufficient quantity")
+			if good.Available() < requestedQuantity {
+				return domain.ErrInsufficientStock
 			}
-			updates[goodID] = newQuantity
+			reservations = append(reservations, domain.Reservation{
+				OrderID:   orderID,
+				SagaID:    sagaID,
+				GoodID:    goodID,
+				Quantity:  requestedQuantity,
+				State:     domain.ReservationHeld,
+				ExpiresAt: expiresAt,
+			})
+		}
+		if _, err := domain.ReservedTotal(reserved); err != nil {
+			return err
+		}
+		if err := tx.Create(&reservations).Error; err != nil {
+			return err
 		}
 
-		for goodID, quantity := range updates {
+		return reserveModifiers(tx, orderID, sagaID, quantityByModifierID, expiresAt)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return reserved, nil
+}
+
+// reserveModifiers holds the tracked modifiers of an order, failing with
+// ErrInsufficientStock when one of them is short.
+func reserveModifiers(tx *gorm.DB, orderID, sagaID uuid.UUID, quantityByModifierID map[uuid.UUID]int, expiresAt time.Time) error {
+	if len(quantityByModifierID) == 0 {
+		return nil
+	}
+	modifierIDs := make([]uuid.UUID, 0, len(quantityByModifierID))
+	for modifierID := range quantityByModifierID {
+		modifierIDs = append(modifierIDs, modifierID)
+	}
+
+	var modifiers []domain.Modifier
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", modifierIDs).
+		Find(&modifiers).Error; err != nil {
+		return err
+	}
+	var held []struct {
+		ModifierID uuid.UUID
+		Held       int
+	}
+	if err := modifierHeldQuery(tx).Where("modifier_id IN ?", modifierIDs).Scan(&held).Error; err != nil {
+		return err
+	}
+	heldByModifierID := make(map[uuid.UUID]int, len(held))
+	for _, h := range held {
+		heldByModifierID[h.ModifierID] = h.Held
+	}
+
+	reservations := make([]domain.ModifierReservation, 0, len(modifiers))
+	for _, modifier := range modifiers {
+		modifier.Held = heldByModifierID[modifier.ID]
+		if modifier.Available() < quantityByModifierID[modifier.ID] {
+			return domain.ErrInsufficientStock
+		}
+		reservations = append(reservations, domain.ModifierReservation{
+			OrderID:    orderID,
+			SagaID:     sagaID,
+			ModifierID: modifier.ID,
+			Quantity:   quantityByModifierID[modifier.ID],
+			State:      domain.ReservationHeld,
+			ExpiresAt:  expiresAt,
+		})
+	}
+	if len(reservations) == 0 {
+		return nil
+	}
+	return tx.Create(&reservations).Error
+}
+
+func reservedItem(good domain.Good, goodID uuid.UUID, modifiers []domain.Modifier, quantity int) domain.ReservedItem {
+	return domain.ReservedItem{
+		GoodID:    goodID,
+		Name:      good.Name,
+		Volume:    good.Volume,
+		Price:     good.Price,
+		Quantity:  quantity,
+		Modifiers: modifiers,
+	}
+}
+
+// CommitProducts turns the order's holds into sales, taking the stock of its
+// goods and tracked modifiers off hand. An expired hold no longer keeps its
+// stock from other orders, so it fails with ErrHoldExpired and nothing is
+// committed.
+func (r *GoodRepository) CommitProducts(ctx context.Context, orderID uuid.UUID) ([]domain.OrderItem, error) {
+	var committed []domain.OrderItem
+
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		reservations, err := lockReservations(tx, orderID)
+		if err != nil {
+			return err
+		}
+
+		states := make(map[domain.ReservationState]int)
+		for _, reservation := range reservations {
+			states[reservation.State]++
+		}
+		if states[domain.ReservationReleased] > 0 {
+			return domain.ErrAlreadyReleased
+		}
+		if states[domain.ReservationExpired] > 0 {
+			return domain.ErrHoldExpired
+		}
+		if states[domain.ReservationHeld] == 0 {
+			return domain.ErrAlreadyCommitted
+		}
+
+		now := time.Now()
+		for _, reservation := range reservations {
+			if reservation.State != domain.ReservationHeld {
+				continue
+			}
 			if err := tx.Model(&domain.Good{}).
-				Where("id = ?", goodID).
-				Update("quantity_in_stock", quantity).Error; err != nil {
+				Where("id = ?", reservation.GoodID).
+				Update("quantity_in_stock", gorm.Expr("quantity_in_stock - ?", reservation.Quantity)).Error; err != nil {
+				return err
+			}
+			if err := setReservationState(tx, &domain.Reservation{}, reservation.ID, domain.ReservationCommitted, now); err != nil {
+				return err
+			}
+			committed = append(committed, domain.OrderItem{GoodID: reservation.GoodID, Quantity: reservation.Quantity})
+		}
+
+		modifierReservations, err := lockModifierReservations(tx, orderID)
+		if err != nil {
+			return err
+		}
+		for _, reservation := range modifierReservations {
+			if reservation.State == domain.ReservationExpired {
+				return domain.ErrHoldExpired
+			}
+			if reservation.State != domain.ReservationHeld {
+				continue
+			}
+			if err := tx.Model(&domain.Modifier{}).
+				Where("id = ?", reservation.ModifierID).
+				Update("quantity_in_stock", gorm.Expr("quantity_in_stock - ?", reservation.Quantity)).Error; err != nil {
+				return err
+			}
+			if err := setReservationState(tx, &domain.ModifierReservation{}, reservation.ID, domain.ReservationCommitted, now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return committed, nil
+}
+
+// ReleaseProducts releases the order's reservations. A hold only stops counting
+// against available stock, an expired one already has, and a committed
+// reservation puts its stock back on hand.
+// Reservations are locked while doing so, so a redelivered command sees them
+// already released and never returns stock twice.
+func (r *GoodRepository) ReleaseProducts(ctx context.Context, orderID uuid.UUID) ([]domain.OrderItem, error) {
+	var released []domain.OrderItem
+
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		reservations, err := lockReservations(tx, orderID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, reservation := range reservations {
+			if reservation.State == domain.ReservationReleased {
+				continue
+			}
+			if reservation.State == domain.ReservationCommitted {
+				if err := tx.Model(&domain.Good{}).
+					Where("id = ?", reservation.GoodID).
+					Update("quantity_in_stock", gorm.Expr("quantity_in_stock + ?", reservation.Quantity)).Error; err != nil {
+					return err
+				}
+			}
+			if err := setReservationState(tx, &domain.Reservation{}, reservation.ID, domain.ReservationReleased, now); err != nil {
 				return err
 			}
+			released = append(released, domain.OrderItem{GoodID: reservation.GoodID, Quantity: reservation.Quantity})
+		}
+		if len(released) == 0 {
+			return domain.ErrAlreadyReleased
 		}
 
+		modifierReservations, err := lockModifierReservations(tx, orderID)
+		if err != nil {
+			return err
+		}
+		for _, reservation := range modifierReservations {
+			if reservation.State == domain.ReservationReleased {
+				continue
+			}
+			if reservation.State == domain.ReservationCommitted {
+				if err := tx.Model(&domain.Modifier{}).
+					Where("id = ?", reservation.ModifierID).
+					Update("quantity_in_stock", gorm.Expr("quantity_in_stock + ?", reservation.Quantity)).Error; err != nil {
+					return err
+				}
+			}
+			if err := setReservationState(tx, &domain.ModifierReservation{}, reservation.ID, domain.ReservationReleased, now); err != nil {
+				return err
+			}
+		}
 		return nil
 	})
 
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	return total, nil
+	return released, nil
+}
+
+// RestockProducts puts refunded goods back on hand once per saga. Goods deleted
+// since the order are skipped. Modifiers are not restocked: milk or syrup
+// that went into a drink is used up.
+func (r *GoodRepository) RestockProducts(ctx context.Context, orderID, sagaID uuid.UUID, goods []domain.OrderItem) ([]domain.OrderItem, error) {
+	var restocked []domain.OrderItem
+
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&domain.Restock{SagaID: sagaID, OrderID: orderID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrAlreadyRestocked
+		}
+
+		for _, good := range goods {
+			result := tx.Model(&domain.Good{}).
+				Where("id = ?", good.GoodID).
+				Update("quantity_in_stock", gorm.Expr("quantity_in_stock + ?", good.Quantity))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected > 0 {
+				restocked = append(restocked, good)
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return restocked, nil
+}
+
+// ExpireHolds expires the holds of goods and modifiers of the orders with one
+// of the up to limit oldest holds past their expiry, and returns these orders.
+func (r *GoodRepository) ExpireHolds(ctx context.Context, now time.Time, limit int) ([]domain.ExpiredHold, error) {
+	var expired []domain.ExpiredHold
+
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		seen := make(map[uuid.UUID]bool)
+		var holds []domain.ExpiredHold
+		for _, model := range []interface{}{&domain.Reservation{}, &domain.ModifierReservation{}} {
+			var due []domain.ExpiredHold
+			if err := tx.Model(model).
+				Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+				Select("order_id, saga_id").
+				Where("state = ? AND expires_at <= ?", domain.ReservationHeld, now).
+				Order("expires_at").
+				Limit(limit).
+				Scan(&due).Error; err != nil {
+				return err
+			}
+			for _, hold := range due {
+				if !seen[hold.OrderID] {
+					seen[hold.OrderID] = true
+					holds = append(holds, hold)
+				}
+			}
+		}
+
+		for _, hold := range holds {
+			var affected int64
+			for _, model := range []interface{}{&domain.Reservation{}, &domain.ModifierReservation{}} {
+				result := tx.Model(model).
+					Where("order_id = ? AND state = ?", hold.OrderID, domain.ReservationHeld).
+					Updates(map[string]interface{}{"state": domain.ReservationExpired, "updated_at": now})
+				if result.Error != nil {
+					return result.Error
+				}
+				affected += result.RowsAffected
+			}
+			if affected > 0 {
+				expired = append(expired, hold)
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return expired, nil
+}
+
+func heldQuery(db *gorm.DB) *gorm.DB {
+	return db.Model(&domain.Reservation{}).
+		Select("good_id, SUM(quantity) AS held").
+		Where("state = ?", domain.ReservationHeld).
+		Group("good_id")
+}
+
+func lockReservations(tx *gorm.DB, orderID uuid.UUID) ([]domain.Reservation, error) {
+	var reservations []domain.Reservation
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("order_id = ?", orderID).
+		Find(&reservations).Error; err != nil {
+		return nil, err
+	}
+	if len(reservations) == 0 {
+		return nil, domain.ErrReservationNotFound
+	}
+	return reservations, nil
+}
+
+func lockModifierReservations(tx *gorm.DB, orderID uuid.UUID) ([]domain.ModifierReservation, error) {
+	var reservations []domain.ModifierReservation
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("order_id = ?", orderID).
+		Find(&reservations).Error
+	return reservations, err
+}
+
+// setReservationState sets the state of a reservation of goods or modifiers,
+// picked by model.
+func setReservationState(tx *gorm.DB, model interface{}, id uuid.UUID, state domain.ReservationState, now time.Time) error {
+	return tx.Model(model).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"state": state, "updated_at": now}).Error
 }
@@ -0,0 +1,213 @@
+package psql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"immxrtalbeast/order_microservices/inventory-service/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ModifierRepository struct {
+	db *gorm.DB
+}
+
+func NewModifierRepository(db *gorm.DB) *ModifierRepository {
+	return &ModifierRepository{db: db}
+}
+
+func (r *ModifierRepository) ListModifierGroups(ctx context.Context) ([]domain.ModifierGroup, error) {
+	var groups []domain.ModifierGroup
+	err := withModifiers(conn(ctx, r.db)).
+		Order("name").
+		Find(&groups).Error
+	return groups, err
+}
+
+// SaveModifierGroup creates group when it has no ID and otherwise replaces the
+// stored group with it: modifiers with an ID are updated, ones without are
+// added and the ones left out are removed.
+func (r *ModifierRepository) SaveModifierGroup(ctx context.Context, group *domain.ModifierGroup) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if group.ID == uuid.Nil {
+			return tx.Create(group).Error
+		}
+
+		var stored domain.ModifierGroup
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("Modifiers").
+			Where("id = ?", group.ID).
+			First(&stored).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrModifierGroupNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := tx.Model(&domain.ModifierGroup{}).
+			Where("id = ?", group.ID).
+			Updates(map[string]interface{}{
+				"name":           group.Name,
+				"selection_type": group.SelectionType,
+				"min_selected":   group.MinSelected,
+				"max_selected":   group.MaxSelected,
+				"updated_at":     now,
+			}).Error; err != nil {
+			return err
+		}
+
+		kept := make(map[uuid.UUID]bool, len(group.Modifiers))
+		for i := range group.Modifiers {
+			modifier := &group.Modifiers[i]
+			modifier.GroupID = group.ID
+			if modifier.ID == uuid.Nil {
+				if err := tx.Create(modifier).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			result := tx.Model(&domain.Modifier{}).
+				Where("id = ? AND group_id = ?", modifier.ID, group.ID).
+				Updates(map[string]interface{}{
+					"name":                 modifier.Name,
+					"price_delta_amount":   modifier.PriceDelta.Amount,
+					"price_delta_currency": modifier.PriceDelta.Currency,
+					"track_stock":          modifier.TrackStock,
+					"quantity_in_stock":    modifier.QuantityInStock,
+					"position":             modifier.Position,
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return fmt.Errorf("%w: modifier %s is not in the group", domain.ErrInvalidModifierGroup, modifier.ID)
+			}
+			kept[modifier.ID] = true
+		}
+		for _, modifier := range stored.Modifiers {
+			if kept[modifier.ID] {
+				continue
+			}
+			if err := tx.Delete(&domain.Modifier{}, "id = ?", modifier.ID).Error; err != nil {
+				return err
+			}
+		}
+		group.CreatedAt, group.UpdatedAt = stored.CreatedAt, now
+		return nil
+	})
+}
+
+// SetGoodModifierGroups replaces the groups offered for a good, keeping the
+// order of groupIDs.
+func (r *ModifierRepository) SetGoodModifierGroups(ctx context.Context, goodID uuid.UUID, groupIDs []uuid.UUID) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var goods int64
+		if err := tx.Model(&domain.Good{}).Where("id = ?", goodID).Count(&goods).Error; err != nil {
+			return err
+		}
+		if goods == 0 {
+			return domain.ErrGoodNotFound
+		}
+
+		links := make([]domain.GoodModifierGroup, 0, len(groupIDs))
+		seen := make(map[uuid.UUID]bool, len(groupIDs))
+		for i, groupID := range groupIDs {
+			if seen[groupID] {
+				continue
+			}
+			seen[groupID] = true
+			links = append(links, domain.GoodModifierGroup{GoodID: goodID, GroupID: groupID, Position: i})
+		}
+		if len(links) > 0 {
+			var groups int64
+			if err := tx.Model(&domain.ModifierGroup{}).Where("id IN ?", groupIDs).Count(&groups).Error; err != nil {
+				return err
+			}
+			if int(groups) != len(links) {
+				return domain.ErrModifierGroupNotFound
+			}
+		}
+
+		if err := tx.Where("good_id = ?", goodID).Delete(&domain.GoodModifierGroup{}).Error; err != nil {
+			return err
+		}
+		if len(links) == 0 {
+			return nil
+		}
+		return tx.Create(&links).Error
+	})
+}
+
+// ListGoodModifiers returns the modifier groups of goodIDs, or of all goods
+// when goodIDs is empty. Goods without groups are left out.
+func (r *ModifierRepository) ListGoodModifiers(ctx context.Context, goodIDs []uuid.UUID) ([]domain.GoodModifiers, error) {
+	return goodModifiers(conn(ctx, r.db), goodIDs)
+}
+
+func goodModifiers(tx *gorm.DB, goodIDs []uuid.UUID) ([]domain.GoodModifiers, error) {
+	query := tx.Model(&domain.GoodModifierGroup{}).Order("good_id, position")
+	if len(goodIDs) > 0 {
+		query = query.Where("good_id IN ?", goodIDs)
+	}
+	var links []domain.GoodModifierGroup
+	if err := query.Find(&links).Error; err != nil {
+		return nil, err
+	}
+	if len(links) == 0 {
+		return nil, nil
+	}
+
+	groupIDs := make([]uuid.UUID, 0, len(links))
+	for _, link := range links {
+		groupIDs = append(groupIDs, link.GroupID)
+	}
+	var groups []domain.ModifierGroup
+	if err := withModifiers(tx.Session(&gorm.Session{NewDB: true})).
+		Where("id IN ?", groupIDs).
+		Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	groupByID := make(map[uuid.UUID]domain.ModifierGroup, len(groups))
+	for _, group := range groups {
+		groupByID[group.ID] = group
+	}
+
+	var result []domain.GoodModifiers
+	for _, link := range links {
+		group, ok := groupByID[link.GroupID]
+		if !ok {
+			continue
+		}
+		if len(result) == 0 || result[len(result)-1].GoodID != link.GoodID {
+			result = append(result, domain.GoodModifiers{GoodID: link.GoodID})
+		}
+		last := &result[len(result)-1]
+		last.Groups = append(last.Groups, group)
+	}
+	return result, nil
+}
+
+// withModifiers preloads the modifiers of groups in order, with the quantity
+// held of each.
+func withModifiers(db *gorm.DB) *gorm.DB {
+	return db.Preload("Modifiers", func(db *gorm.DB) *gorm.DB {
+		return db.
+			Select("modifiers.*, COALESCE(h.held, 0) AS held").
+			Joins("LEFT JOIN (?) AS h ON h.modifier_id = modifiers.id", modifierHeldQuery(db.Session(&gorm.Session{NewDB: true}))).
+			Order("modifiers.position, modifiers.name")
+	})
+}
+
+func modifierHeldQuery(db *gorm.DB) *gorm.DB {
+	return db.Model(&domain.ModifierReservation{}).
+		Select("modifier_id, SUM(quantity) AS held").
+		Where("state = ?", domain.ReservationHeld).
+		Group("modifier_id")
+}
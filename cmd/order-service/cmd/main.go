@@ -8,22 +8,33 @@ import (
 	"immxrtalbeast/order_microservices/cmd/order-service/internal/domain"
 	"immxrtalbeast/order_microservices/cmd/order-service/internal/lib/logger/sl"
 	"immxrtalbeast/order_microservices/cmd/order-service/internal/lib/logger/slogpretty"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/service/cart"
 	"immxrtalbeast/order_microservices/cmd/order-service/internal/service/order"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/service/watch"
 	"immxrtalbeast/order_microservices/cmd/order-service/internal/storage/psql"
 	"immxrtalbeast/order_microservices/cmd/order-service/internal/tracing"
+	kafka "immxrtalbeast/order_microservices/internal/pkg/kafka"
+	"immxrtalbeast/order_microservices/internal/pkg/outbox"
 	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
 
-	kafka "github.com/ozzus/order_kafka"
+	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// serviceName stamps the outbox messages and inbox records of this service.
+const serviceName = "order-service"
+
 func main() {
 	cfg := config.MustLoad()
 	log := setupLogger(cfg.Env)
 	log.Info("starting application")
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 	if err := godotenv.Load(".env"); err != nil {
 		log.Error("failed to load .env file", sl.Err(err))
 		os.Exit(1)
@@ -50,15 +61,57 @@ func main() {
 	}
 	log.Info("db connected")
 
-	db.AutoMigrate(&domain.Order{}, &domain.OrderItem{})
+	db.AutoMigrate(&domain.Order{}, &domain.OrderItem{}, &domain.OrderItemModifier{}, &outbox.Message{}, &domain.InboxMessage{}, &domain.OrderStatusHistory{}, &domain.IdempotencyKey{}, &domain.ArchivedOrder{}, &domain.Refund{}, &domain.RefundItem{}, &domain.Cart{}, &domain.CartItem{})
+	if err := kafka.EnsureTopics(ctx, []string{os.Getenv("KAFKA_ADDRESS")},
+		kafka.TopicsWithDeadLetters(cfg.Kafka.Partitions, cfg.Kafka.ReplicationFactor, "saga-commands", "saga-replies", "order-events")...,
+	); err != nil {
+		log.Error("failed to provision kafka topics", sl.Err(err))
+		os.Exit(1)
+	}
 	producer := kafka.NewProducer(
 		[]string{os.Getenv("KAFKA_ADDRESS")},
 		"saga-replies",
 	)
 	defer producer.Close()
+	orderEventsProducer := kafka.NewProducer(
+		[]string{os.Getenv("KAFKA_ADDRESS")},
+		"order-events",
+	)
+	defer orderEventsProducer.Close()
+	deadLetters := kafka.NewDeadLetterQueue([]string{os.Getenv("KAFKA_ADDRESS")})
+	defer deadLetters.Close()
 
 	orderRepo := psql.NewOrderRepository(db)
-	orderInteractor := order.NewOrderInteractor(orderRepo, log, producer)
+	outboxRepo := outbox.NewRepository(db, serviceName)
+	inboxRepo := psql.NewInboxRepository(db, serviceName)
+	historyRepo := psql.NewStatusHistoryRepository(db)
+	idempotencyRepo := psql.NewIdempotencyRepository(db)
+	archiveRepo := psql.NewArchiveRepository(db)
+	refundRepo := psql.NewRefundRepository(db)
+	cartRepo := psql.NewCartRepository(db)
+	transactor := outbox.NewTransactor(db)
+	hub := watch.NewHub()
+	orderInteractor := order.NewOrderInteractor(orderRepo, outboxRepo, historyRepo, idempotencyRepo, archiveRepo, refundRepo, hub, transactor, log, cfg.Idempotency.TTL)
+	go orderInteractor.RunIdempotencyJob(ctx, cfg.Idempotency.SweepInterval, cfg.Idempotency.SweepBatch)
+	go orderInteractor.RunArchiveJob(ctx, cfg.Archive.Interval, cfg.Archive.After, cfg.Archive.Batch)
+	catalog, err := client.NewCatalog(cfg.Clients.Inventory.Address, cfg.Clients.Inventory.Timeout, cfg.Clients.Inventory.RetriesCount)
+	if err != nil {
+		log.Error("failed to connect inventory service", sl.Err(err))
+		panic(err)
+	}
+	cartInteractor := cart.NewCartInteractor(cartRepo, catalog, orderInteractor, transactor, log, cfg.Cart.TTL)
+	go cartInteractor.RunCartJob(ctx, cfg.Cart.SweepInterval, cfg.Cart.SweepBatch)
+	inbox := client.NewInbox(inboxRepo, transactor)
+
+	relay := outbox.NewRelay(log, outboxRepo, map[string]*kafka.Producer{
+		"saga-replies": producer,
+		"order-events": orderEventsProducer,
+	}, cfg.Kafka.ContentType)
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		relay.Run(ctx, cfg.Outbox.PollInterval, cfg.Outbox.BatchSize)
+	}()
 
 	consumer := kafka.NewConsumer(
 		[]string{os.Getenv("KAFKA_ADDRESS")},
@@ -67,9 +120,49 @@ func main() {
 	)
 	defer consumer.Close()
 
-	go client.ProcessOrderEvents(consumer, orderInteractor, log)
-	grpcApp := grpcapp.New(log, orderInteractor, cfg.GRPC.Port)
-	grpcApp.MustRun()
+	commandsConsumer := kafka.NewConsumer(
+		[]string{os.Getenv("KAFKA_ADDRESS")},
+		"saga-commands",
+		"order-service-commands-group",
+	)
+	defer commandsConsumer.Close()
+
+	// Every instance reads all status changes to serve the watchers connected
+	// to it, hence a consumer group of its own.
+	statusConsumer := kafka.NewBroadcastConsumer(
+		[]string{os.Getenv("KAFKA_ADDRESS")},
+		"order-events",
+		"order-service-watch-"+uuid.NewString(),
+	)
+	defer statusConsumer.Close()
+	go client.RunStatusFeed(ctx, statusConsumer, hub, log)
+
+	handler := client.NewOrderEventsHandler(orderInteractor, inbox, log)
+	pools := []*kafka.Pool{
+		kafka.NewPool(consumer, handler, log, cfg.Consumer.PoolConfig(deadLetters)),
+		kafka.NewPool(commandsConsumer, handler, log, cfg.Consumer.PoolConfig(deadLetters)),
+	}
+	for _, pool := range pools {
+		go pool.Run(ctx)
+	}
+	grpcApp := grpcapp.New(log, orderInteractor, cartInteractor, cfg.GRPC.Port)
+	go grpcApp.MustRun()
+
+	<-ctx.Done()
+	log.Info("shutting down")
+	// Open watch streams end when the hub closes, so they do not hold up the
+	// graceful stop.
+	hub.Close()
+	grpcApp.Stop()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Consumer.ShutdownTimeout)
+	defer cancel()
+	for _, pool := range pools {
+		if err := pool.Shutdown(shutdownCtx); err != nil {
+			log.Error("in-flight messages were not drained", sl.Err(err))
+		}
+	}
+	<-relayDone
+	log.Info("application stopped")
 }
 
 const (
@@ -8,7 +8,6 @@ require (
 	github.com/grpc-ecosystem/go-grpc-middleware/v2 v2.3.2
 	github.com/ilyakaznacheev/cleanenv v1.5.0
 	github.com/joho/godotenv v1.5.1
-	github.com/ozzus/order_kafka v0.0.0-20260621120956-f08d9605a6c7
 	github.com/ozzus/order_protos v0.0.0-20260621120947-f44d30eaffd6
 	github.com/segmentio/kafka-go v0.4.51
 	go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc v0.63.0
@@ -19,6 +18,12 @@ require (
 	google.golang.org/protobuf v1.36.9
 	gorm.io/driver/postgres v1.6.0
 	gorm.io/gorm v1.31.0
+	immxrtalbeast/order_microservices/internal/pkg/events v0.0.0-00010101000000-000000000000
+	immxrtalbeast/order_microservices/internal/pkg/inventorypb v0.0.0-00010101000000-000000000000
+	immxrtalbeast/order_microservices/internal/pkg/kafka v0.0.0-00010101000000-000000000000
+	immxrtalbeast/order_microservices/internal/pkg/money v0.0.0-00010101000000-000000000000
+	immxrtalbeast/order_microservices/internal/pkg/orderpb v0.0.0-00010101000000-000000000000
+	immxrtalbeast/order_microservices/internal/pkg/outbox v0.0.0-00010101000000-000000000000
 )
 
 require (
@@ -48,3 +53,15 @@ require (
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 	olympos.io/encoding/edn v0.0.0-20201019073823-d3554ca0b0a3 // indirect
 )
+
+replace immxrtalbeast/order_microservices/internal/pkg/outbox => ../../internal/pkg/outbox
+
+replace immxrtalbeast/order_microservices/internal/pkg/kafka => ../../internal/pkg/kafka
+
+replace immxrtalbeast/order_microservices/internal/pkg/events => ../../internal/pkg/events
+
+replace immxrtalbeast/order_microservices/internal/pkg/orderpb => ../../internal/pkg/orderpb
+
+replace immxrtalbeast/order_microservices/internal/pkg/money => ../../internal/pkg/money
+
+replace immxrtalbeast/order_microservices/internal/pkg/inventorypb => ../../internal/pkg/inventorypb
@@ -20,7 +20,7 @@ type GrpcApp struct {
 	port       int
 }
 
-func New(log *slog.Logger, orderInteractor domain.OrderInteractor, port int) *GrpcApp {
+func New(log *slog.Logger, orderInteractor domain.OrderInteractor, cartInteractor domain.CartInteractor, port int) *GrpcApp {
 
 	recoveryOpts := []recovery.Option{
 		recovery.WithRecoveryHandler(func(p interface{}) (err error) {
@@ -34,9 +34,12 @@ func New(log *slog.Logger, orderInteractor domain.OrderInteractor, port int) *Gr
 		grpc.ChainUnaryInterceptor(
 			recovery.UnaryServerInterceptor(recoveryOpts...),
 		),
+		grpc.ChainStreamInterceptor(
+			recovery.StreamServerInterceptor(recoveryOpts...),
+		),
 		grpc.StatsHandler(otelgrpc.NewServerHandler()))
 
-	ordergrpc.Register(gRPCServer, orderInteractor)
+	ordergrpc.Register(gRPCServer, orderInteractor, cartInteractor)
 
 	return &GrpcApp{
 		log:        log,
@@ -70,3 +73,11 @@ func (a *GrpcApp) Run() error {
 
 	return nil
 }
+
+// Stop stops accepting new requests and waits for running ones to finish.
+func (a *GrpcApp) Stop() {
+	const op = "grpcapp.Stop"
+
+	a.log.With(slog.String("op", op)).Info("stopping grpc server", slog.Int("port", a.port))
+	a.gRPCServer.GracefulStop()
+}
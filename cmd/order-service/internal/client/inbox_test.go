@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/domain"
+	mykafka "immxrtalbeast/order_microservices/internal/pkg/kafka"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// fakeInboxRepo remembers recorded message IDs the way the unique key on the
+// inbox table does.
+type fakeInboxRepo struct {
+	seen map[string]bool
+}
+
+func (r *fakeInboxRepo) Record(ctx context.Context, messageID, eventType string) error {
+	if r.seen[messageID] {
+		return domain.ErrDuplicateMessage
+	}
+	r.seen[messageID] = true
+	return nil
+}
+
+// fakeTransactor forgets the inbox records written by a failed handler, as a
+// rollback would.
+type fakeTransactor struct {
+	repo *fakeInboxRepo
+}
+
+func (t fakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	before := make(map[string]bool, len(t.repo.seen))
+	for id := range t.repo.seen {
+		before[id] = true
+	}
+	if err := fn(ctx); err != nil {
+		t.repo.seen = before
+		return err
+	}
+	return nil
+}
+
+func messageWithID(id string) kafka.Message {
+	if id == "" {
+		return kafka.Message{}
+	}
+	return kafka.Message{Headers: []kafka.Header{{Key: mykafka.HeaderMessageID, Value: []byte(id)}}}
+}
+
+func TestInboxHandle(t *testing.T) {
+	errHandler := errors.New("handler failed")
+	tests := []struct {
+		name      string
+		seen      []string
+		messageID string
+		handleErr error
+		wantCalls int
+		wantErr   error
+		wantSeen  bool
+	}{
+		{name: "first delivery is handled and recorded", messageID: "m-1", wantCalls: 1, wantSeen: true},
+		{name: "redelivery is skipped", seen: []string{"m-1"}, messageID: "m-1", wantSeen: true},
+		{name: "other message is handled", seen: []string{"m-1"}, messageID: "m-2", wantCalls: 1, wantSeen: true},
+		{name: "message without id is always handled", wantCalls: 1},
+		{name: "failed handler leaves the message unrecorded", messageID: "m-1", handleErr: errHandler, wantCalls: 1, wantErr: errHandler},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeInboxRepo{seen: map[string]bool{}}
+			for _, id := range tt.seen {
+				repo.seen[id] = true
+			}
+			inbox := NewInbox(repo, fakeTransactor{repo: repo})
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			calls := 0
+			err := inbox.Handle(context.Background(), log, messageWithID(tt.messageID), "OrderStatusUpdateCommand", func(ctx context.Context) error {
+				calls++
+				return tt.handleErr
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("handler ran %d times, want %d", calls, tt.wantCalls)
+			}
+			if tt.messageID != "" && repo.seen[tt.messageID] != tt.wantSeen {
+				t.Errorf("recorded = %t, want %t", repo.seen[tt.messageID], tt.wantSeen)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/domain"
+	"immxrtalbeast/order_microservices/internal/pkg/inventorypb"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
+	"time"
+
+	"github.com/google/uuid"
+	grpcretry "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/retry"
+	inventory "github.com/ozzus/order_protos/gen/go/inventory"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Catalog reads live product prices and stock and the modifiers of products
+// from inventory-service.
+type Catalog struct {
+	api       inventory.InventoryClient
+	modifiers inventorypb.ModifierServiceClient
+	stock     inventorypb.StockServiceClient
+}
+
+func NewCatalog(addr string, timeout time.Duration, retriesCount int) (*Catalog, error) {
+	const op = "client.NewCatalog"
+
+	retryOpts := []grpcretry.CallOption{
+		grpcretry.WithCodes(codes.Unavailable, codes.Aborted, codes.DeadlineExceeded),
+		grpcretry.WithMax(uint(retriesCount)),
+		grpcretry.WithPerRetryTimeout(timeout),
+	}
+	conn, err := grpc.NewClient(
+		addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithChainUnaryInterceptor(
+			grpcretry.UnaryClientInterceptor(retryOpts...),
+		))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &Catalog{
+		api:       inventory.NewInventoryClient(conn),
+		modifiers: inventorypb.NewModifierServiceClient(conn),
+		stock:     inventorypb.NewStockServiceClient(conn),
+	}, nil
+}
+
+func (c *Catalog) Products(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]domain.Product, error) {
+	const op = "client.Catalog.Products"
+
+	resp, err := c.api.ListProducts(ctx, &inventory.ListProductsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	wanted := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	products := make(map[uuid.UUID]domain.Product, len(ids))
+	for _, product := range resp.Products {
+		id, err := uuid.Parse(product.Id)
+		if err != nil || !wanted[id] {
+			continue
+		}
+		products[id] = domain.Product{
+			ID:     id,
+			Name:   product.Name,
+			Volume: int(product.Volume),
+			Price:  money.FromFloat(product.Price, money.DefaultCurrency),
+		}
+	}
+	if len(products) == 0 {
+		return products, nil
+	}
+
+	goodIDs := make([]string, 0, len(products))
+	for id := range products {
+		goodIDs = append(goodIDs, id.String())
+	}
+	stock, err := c.stock.ListStock(ctx, &inventorypb.ListStockRequest{GoodIds: goodIDs})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for _, good := range stock.GetGoods() {
+		id, err := uuid.Parse(good.GetGoodId())
+		if err != nil {
+			continue
+		}
+		if product, ok := products[id]; ok {
+			product.Available = int(good.GetQuantityAvailable())
+			products[id] = product
+		}
+	}
+	modifiers, err := c.modifiers.ListGoodModifiers(ctx, &inventorypb.ListGoodModifiersRequest{GoodIds: goodIDs})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for _, good := range modifiers.GetGoods() {
+		id, err := uuid.Parse(good.GetGoodId())
+		if err != nil {
+			continue
+		}
+		product, ok := products[id]
+		if !ok {
+			continue
+		}
+		product.ModifierGroups = modifierGroups(good.GetGroups())
+		products[id] = product
+	}
+	return products, nil
+}
+
+func modifierGroups(in []*inventorypb.ModifierGroup) []domain.ModifierGroup {
+	groups := make([]domain.ModifierGroup, 0, len(in))
+	for _, g := range in {
+		groupID, err := uuid.Parse(g.GetId())
+		if err != nil {
+			continue
+		}
+		group := domain.ModifierGroup{
+			ID:          groupID,
+			Name:        g.GetName(),
+			MinSelected: int(g.GetMinSelected()),
+			MaxSelected: int(g.GetMaxSelected()),
+		}
+		for _, m := range g.GetModifiers() {
+			modifierID, err := uuid.Parse(m.GetId())
+			if err != nil {
+				continue
+			}
+			group.Modifiers = append(group.Modifiers, domain.Modifier{
+				ID:         modifierID,
+				GroupID:    groupID,
+				Name:       m.GetName(),
+				PriceDelta: money.New(m.GetPriceDelta().GetAmount(), m.GetPriceDelta().GetCurrency()),
+				TrackStock: m.GetTrackStock(),
+				Available:  int(m.GetQuantityAvailable()),
+			})
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
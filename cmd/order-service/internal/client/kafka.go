@@ -2,87 +2,91 @@ package client
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"immxrtalbeast/order_microservices/cmd/order-service/internal/domain"
 	"immxrtalbeast/order_microservices/cmd/order-service/internal/lib/logger/sl"
 	"immxrtalbeast/order_microservices/cmd/order-service/internal/service/order"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	mykafka "immxrtalbeast/order_microservices/internal/pkg/kafka"
 	"log/slog"
-	"time"
 
-	mykafka "github.com/ozzus/order_kafka"
 	"github.com/segmentio/kafka-go"
 	"go.opentelemetry.io/otel/propagation"
 )
 
-func ProcessOrderEvents(consumer *mykafka.Consumer, orderInteractor *order.OrderInteractor, log *slog.Logger) {
-	log.Info("listening kafka")
+func NewOrderEventsHandler(orderInteractor *order.OrderInteractor, inbox *Inbox, log *slog.Logger) mykafka.Handler {
 	propagator := propagation.TraceContext{}
-	for {
-		readCtx, readCancel := context.WithTimeout(context.Background(), 1*time.Second)
-
-		msg, err := consumer.ReadRawMessage(readCtx)
-		readCancel()
+	return func(ctx context.Context, msg kafka.Message) error {
+		log.Info("EventReceived")
+		env, err := events.Unmarshal(msg.Value, mykafka.Header(msg, mykafka.HeaderContentType), mykafka.Header(msg, mykafka.HeaderEventType))
 		if err != nil {
-			if err == context.DeadlineExceeded {
-				continue
-			}
-			time.Sleep(1 * time.Second)
-			continue
+			return mykafka.Permanent(err)
+		}
+		event, err := env.Decode()
+		if errors.Is(err, events.ErrUnknownType) {
+			return nil
 		}
-		log.Info("EventReceived")
-		eventType, err := getHeaderValue(msg.Headers, "Event-Type")
 		if err != nil {
-			log.Error("error while parsing eventType", sl.Err(err), "eventType is", eventType)
-			continue
+			return mykafka.Permanent(err)
 		}
-		baseCtx := context.Background()
+
 		carrier := propagation.MapCarrier{}
 		for _, header := range msg.Headers {
 			carrier[header.Key] = string(header.Value)
 		}
+		ctx = propagator.Extract(ctx, carrier)
+		ctx = events.WithCorrelationID(ctx, env.CorrelationID)
+		handle := func(fn func(ctx context.Context) error) error {
+			return inbox.Handle(ctx, log, msg, env.Type, fn)
+		}
+		switch e := event.(type) {
+		case events.InventoryReserved:
+			log.Info("products reserve command received", "event", e)
+			return handle(func(ctx context.Context) error {
+				return skipRejected(log, orderInteractor.HandleInventoryReserved(ctx, e))
+			})
 
-		ctx := propagator.Extract(baseCtx, carrier)
-		processCtx, processCancel := context.WithTimeout(ctx, 30*time.Second)
-		switch eventType {
-		case "InventoryReservedEvent":
-			var event domain.ReserveProductsEventReply
-			if err := json.Unmarshal(msg.Value, &event); err != nil {
-				log.Error("failed to unmarshal event", "type", eventType, "error", err)
-				continue
-			}
-			log.Info("products reserve command received", "event", event)
+		case events.OrderStatusUpdate:
+			return handle(func(ctx context.Context) error {
+				status, err := domain.ParseOrderStatus(e.Status)
+				if err != nil {
+					return skipRejected(log, err)
+				}
+				_, err = orderInteractor.UpdateOrderStatus(ctx, e.OrderID, domain.StatusChange{
+					Status: status,
+					Actor:  domain.ActorSaga,
+					Reason: e.Reason,
+				})
+				return skipRejected(log, err)
+			})
 
-			go func() {
-				defer processCancel()
-				orderInteractor.SetTotalSum(processCtx, event)
-			}()
+		case events.PaymentCaptured:
+			return handle(func(ctx context.Context) error {
+				return orderInteractor.HandlePaymentCaptured(ctx, e)
+			})
 
-		case "OrderStatusUpdateCommand":
-			var command domain.OrderStatusUpdateCommand
-			if err := json.Unmarshal(msg.Value, &command); err != nil {
-				log.Error("failed to unmarshal command", "type", eventType, "error", err)
-				processCancel()
-				continue
-			}
-			go func() {
-				defer processCancel()
-				if err := orderInteractor.UpdateOrderStatus(processCtx, command.OrderID, command.Status); err != nil {
-					log.Error("failed to update order status", sl.Err(err), "order_id", command.OrderID, "status", command.Status)
-				}
-			}()
+		case events.OrderRefundCompleted:
+			return handle(func(ctx context.Context) error {
+				return skipRejected(log, orderInteractor.HandleRefundCompleted(ctx, e))
+			})
+
+		case events.OrderRefundFailed:
+			return handle(func(ctx context.Context) error {
+				return skipRejected(log, orderInteractor.HandleRefundFailed(ctx, e))
+			})
 
 		default:
-			processCancel()
-			continue
+			return nil
 		}
 	}
 }
 
-func getHeaderValue(headers []kafka.Header, key string) (string, error) {
-	for _, h := range headers {
-		if h.Key == key {
-			return string(h.Value), nil
-		}
+// skipRejected acknowledges messages the order status machine refuses, since
+// redelivering them cannot make the transition legal.
+func skipRejected(log *slog.Logger, err error) error {
+	if errors.Is(err, domain.ErrIllegalTransition) || errors.Is(err, domain.ErrUnknownStatus) || errors.Is(err, domain.ErrOrderNotFound) || errors.Is(err, domain.ErrRefundNotFound) {
+		log.Warn("status change rejected", sl.Err(err))
+		return nil
 	}
-	return "", nil
+	return err
 }
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/lib/logger/sl"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/service/watch"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	mykafka "immxrtalbeast/order_microservices/internal/pkg/kafka"
+	"log/slog"
+	"time"
+)
+
+// RunStatusFeed passes the OrderStatusChanged events read by consumer to hub
+// until ctx is cancelled. Offsets are not committed: a restarted instance has
+// no watchers to catch up.
+func RunStatusFeed(ctx context.Context, consumer *mykafka.Consumer, hub *watch.Hub, log *slog.Logger) {
+	const op = "client.statusFeed"
+	log = log.With(
+		slog.String("op", op),
+	)
+	log.Info("order status feed started")
+	for {
+		msg, err := consumer.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				log.Info("order status feed stopped")
+				return
+			}
+			log.Error("failed to fetch order status event", sl.Err(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		env, err := events.Unmarshal(msg.Value, mykafka.Header(msg, mykafka.HeaderContentType), mykafka.Header(msg, mykafka.HeaderEventType))
+		if err != nil {
+			log.Warn("failed to read order status event", sl.Err(err))
+			continue
+		}
+		event, err := env.Decode()
+		if err != nil {
+			continue
+		}
+		if change, ok := event.(events.OrderStatusChanged); ok {
+			hub.Publish(change)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"flag"
+	kafka "immxrtalbeast/order_microservices/internal/pkg/kafka"
 	"os"
 	"time"
 
@@ -9,8 +10,33 @@ import (
 )
 
 type Config struct {
-	Env  string     `yaml:"env" env-default:"local"`
-	GRPC GRPCConfig `yaml:"grpc"`
+	Env         string            `yaml:"env" env-default:"local"`
+	GRPC        GRPCConfig        `yaml:"grpc"`
+	Outbox      OutboxConfig      `yaml:"outbox"`
+	Consumer    ConsumerConfig    `yaml:"consumer"`
+	Kafka       KafkaConfig       `yaml:"kafka"`
+	Idempotency IdempotencyConfig `yaml:"idempotency"`
+	Archive     ArchiveConfig     `yaml:"archive"`
+	Cart        CartConfig        `yaml:"cart"`
+	Clients     ClientsConfig     `yaml:"clients"`
+}
+
+type Client struct {
+	Address      string        `yaml:"address"`
+	Timeout      time.Duration `yaml:"timeout"`
+	RetriesCount int           `yaml:"retriesCount"`
+}
+
+type ClientsConfig struct {
+	Inventory Client `yaml:"inventory"`
+}
+
+type KafkaConfig struct {
+	Partitions        int `yaml:"partitions" env-default:"6"`
+	ReplicationFactor int `yaml:"replication_factor" env-default:"1"`
+	// ContentType is the encoding of published events, application/x-protobuf
+	// or application/json. Consumers accept both.
+	ContentType string `yaml:"content_type" env-default:"application/x-protobuf"`
 }
 
 type GRPCConfig struct {
@@ -18,6 +44,54 @@ type GRPCConfig struct {
 	Timeout time.Duration `yaml:"timeout"`
 }
 
+type OutboxConfig struct {
+	PollInterval time.Duration `yaml:"poll_interval" env-default:"500ms"`
+	BatchSize    int           `yaml:"batch_size" env-default:"100"`
+}
+
+// IdempotencyConfig controls how long CreateOrder idempotency keys are kept.
+type IdempotencyConfig struct {
+	TTL           time.Duration `yaml:"ttl" env-default:"24h"`
+	SweepInterval time.Duration `yaml:"sweep_interval" env-default:"5m"`
+	SweepBatch    int           `yaml:"sweep_batch" env-default:"500"`
+}
+
+// ArchiveConfig controls the job moving completed orders into the archive
+// once they have not changed for After.
+type ArchiveConfig struct {
+	After    time.Duration `yaml:"after" env-default:"2160h"`
+	Interval time.Duration `yaml:"interval" env-default:"1h"`
+	Batch    int           `yaml:"batch" env-default:"100"`
+}
+
+// CartConfig controls how long an untouched cart is kept and the job
+// deleting expired ones.
+type CartConfig struct {
+	TTL           time.Duration `yaml:"ttl" env-default:"720h"`
+	SweepInterval time.Duration `yaml:"sweep_interval" env-default:"1h"`
+	SweepBatch    int           `yaml:"sweep_batch" env-default:"500"`
+}
+
+type ConsumerConfig struct {
+	Workers         int                          `yaml:"workers" env-default:"8"`
+	QueueSize       int                          `yaml:"queue_size" env-default:"64"`
+	HandlerTimeout  time.Duration                `yaml:"handler_timeout" env-default:"30s"`
+	ShutdownTimeout time.Duration                `yaml:"shutdown_timeout" env-default:"20s"`
+	Retry           kafka.RetryPolicy            `yaml:"retry"`
+	EventRetry      map[string]kafka.RetryPolicy `yaml:"event_retry"`
+}
+
+func (c ConsumerConfig) PoolConfig(deadLetters *kafka.DeadLetterQueue) kafka.PoolConfig {
+	return kafka.PoolConfig{
+		Workers:        c.Workers,
+		QueueSize:      c.QueueSize,
+		HandlerTimeout: c.HandlerTimeout,
+		Retry:          c.Retry,
+		EventRetry:     c.EventRetry,
+		DeadLetters:    deadLetters,
+	}
+}
+
 func MustLoad() *Config {
 	configPath := fetchConfigPath()
 	if configPath == "" {
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"context"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ArchivedOrder is an old completed order moved out of the orders table.
+// Payload holds the order with its items and status history as JSON so it
+// can be restored as it was.
+type ArchivedOrder struct {
+	OrderID    uuid.UUID   `gorm:"type:uuid;primaryKey"`
+	UserID     uuid.UUID   `gorm:"type:uuid;not null;index"`
+	Status     OrderStatus `gorm:"type:varchar(20);not null"`
+	Total      money.Money `gorm:"embedded;embeddedPrefix:total_"`
+	CreatedAt  time.Time   `gorm:"not null"`
+	ArchivedAt time.Time   `gorm:"autoCreateTime"`
+	Payload    string      `gorm:"type:jsonb;not null"`
+}
+
+func (ArchivedOrder) TableName() string {
+	return "order_archive"
+}
+
+type ArchiveRepository interface {
+	// Archive moves up to limit completed orders last updated before cutoff
+	// into the archive and returns how many were moved.
+	Archive(ctx context.Context, cutoff time.Time, limit int) (int64, error)
+	// Restore moves an archived order back, returning ErrOrderNotFound if it
+	// is not archived.
+	Restore(ctx context.Context, orderID uuid.UUID) error
+}
@@ -0,0 +1,274 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCartNotFound     = errors.New("cart not found")
+	ErrCartEmpty        = errors.New("cart is empty")
+	ErrCartNotInStock   = errors.New("cart has items that are not in stock")
+	ErrProductNotFound  = errors.New("product not found")
+	ErrInvalidModifiers = errors.New("invalid modifier selection")
+)
+
+// Cart is kept server side for a user, or for an anonymous visitor by its ID
+// alone. It expires once untouched until ExpiresAt.
+type Cart struct {
+	ID     uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID *uuid.UUID `gorm:"type:uuid;uniqueIndex"`
+	Items  []CartItem `gorm:"foreignKey:CartID;constraint:OnDelete:CASCADE"`
+	// CheckoutKey and CheckoutOrderID remember the last checkout, so a retry
+	// with the same idempotency key finds its order after the cart was emptied.
+	CheckoutKey     string
+	CheckoutOrderID *uuid.UUID `gorm:"type:uuid"`
+	ExpiresAt       time.Time  `gorm:"not null;index"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (c *Cart) Expired(now time.Time) bool {
+	return !c.ExpiresAt.After(now)
+}
+
+// CartItem is a cart line: a product with a choice of modifiers, stored as
+// their ModifierKey. The same product with other modifiers is another line.
+type CartItem struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	CartID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_cart_items_cart_line"`
+	ProductID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_cart_items_cart_line"`
+	ModifierKey string    `gorm:"not null;default:'';uniqueIndex:idx_cart_items_cart_line"`
+	Quantity    int       `gorm:"not null"`
+	CreatedAt   time.Time
+}
+
+// ModifierIDs returns the modifiers chosen for the line.
+func (i CartItem) ModifierIDs() []uuid.UUID {
+	if i.ModifierKey == "" {
+		return nil
+	}
+	parts := strings.Split(i.ModifierKey, ",")
+	ids := make([]uuid.UUID, 0, len(parts))
+	for _, part := range parts {
+		if id, err := uuid.Parse(part); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// CartOwner picks a cart: the user's when UserID is set, otherwise the
+// anonymous cart CartID.
+type CartOwner struct {
+	UserID uuid.UUID
+	CartID uuid.UUID
+}
+
+// Product is what the catalog currently sells a product at, with the
+// modifier groups it is offered with.
+type Product struct {
+	ID             uuid.UUID
+	Name           string
+	Volume         int
+	Price          money.Money
+	Available      int
+	ModifierGroups []ModifierGroup
+}
+
+// ModifierGroup is a set of modifiers of a product, of which a line picks
+// between MinSelected and MaxSelected.
+type ModifierGroup struct {
+	ID          uuid.UUID
+	Name        string
+	MinSelected int
+	MaxSelected int
+	Modifiers   []Modifier
+}
+
+// Modifier is what the catalog currently sells a modifier at. Available only
+// limits modifiers that track stock.
+type Modifier struct {
+	ID         uuid.UUID
+	GroupID    uuid.UUID
+	Name       string
+	PriceDelta money.Money
+	TrackStock bool
+	Available  int
+}
+
+// SelectModifiers checks that ids is a valid choice from the modifier groups
+// of the product and returns the chosen modifiers in the order of ids.
+func (p Product) SelectModifiers(ids []uuid.UUID) ([]Modifier, error) {
+	offered := make(map[uuid.UUID]Modifier)
+	for _, group := range p.ModifierGroups {
+		for _, modifier := range group.Modifiers {
+			offered[modifier.ID] = modifier
+		}
+	}
+	chosen := make([]Modifier, 0, len(ids))
+	selected := make(map[uuid.UUID]int)
+	for _, id := range ids {
+		modifier, ok := offered[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: modifier %s is not offered for %s", ErrInvalidModifiers, id, p.Name)
+		}
+		delete(offered, id)
+		selected[modifier.GroupID]++
+		chosen = append(chosen, modifier)
+	}
+	for _, group := range p.ModifierGroups {
+		if n := selected[group.ID]; n < group.MinSelected || n > group.MaxSelected {
+			return nil, fmt.Errorf("%w: %s takes %d to %d modifiers", ErrInvalidModifiers, group.Name, group.MinSelected, group.MaxSelected)
+		}
+	}
+	return chosen, nil
+}
+
+type Availability string
+
+const (
+	AvailabilityInStock           Availability = "IN_STOCK"
+	AvailabilityInsufficientStock Availability = "INSUFFICIENT_STOCK"
+	// AvailabilityUnavailable lines are for products no longer sold or with
+	// modifiers no longer offered.
+	AvailabilityUnavailable Availability = "UNAVAILABLE"
+)
+
+// PricedCartItem is a cart line priced with the live product and modifiers.
+// UnitPrice includes the modifiers and Available is the least of what is
+// left of the product and its tracked modifiers.
+type PricedCartItem struct {
+	ProductID    uuid.UUID
+	Quantity     int
+	Name         string
+	Volume       int
+	UnitPrice    money.Money
+	LineTotal    money.Money
+	Available    int
+	Availability Availability
+	Modifiers    []Modifier
+}
+
+// PricedCart is a cart as the catalog sells it right now. Subtotal sums the
+// lines that are still sold.
+type PricedCart struct {
+	Cart     Cart
+	Items    []PricedCartItem
+	Subtotal money.Money
+}
+
+// CheckoutReady reports whether the cart has items and all of them are in
+// stock.
+func (c PricedCart) CheckoutReady() bool {
+	if len(c.Items) == 0 {
+		return false
+	}
+	for _, item := range c.Items {
+		if item.Availability != AvailabilityInStock {
+			return false
+		}
+	}
+	return true
+}
+
+// PriceCart prices the items of cart with products, the catalog keyed by
+// product ID. Lines whose modifiers are no longer a valid choice are
+// unavailable like lines of products no longer sold.
+func PriceCart(cart Cart, products map[uuid.UUID]Product) (PricedCart, error) {
+	priced := PricedCart{
+		Cart:     cart,
+		Items:    make([]PricedCartItem, 0, len(cart.Items)),
+		Subtotal: money.New(0, money.DefaultCurrency),
+	}
+	for _, item := range cart.Items {
+		line := PricedCartItem{
+			ProductID:    item.ProductID,
+			Quantity:     item.Quantity,
+			Availability: AvailabilityUnavailable,
+		}
+		product, ok := products[item.ProductID]
+		line.Name, line.Volume = product.Name, product.Volume
+		modifiers, err := product.SelectModifiers(item.ModifierIDs())
+		if !ok || err != nil {
+			for _, id := range item.ModifierIDs() {
+				line.Modifiers = append(line.Modifiers, Modifier{ID: id})
+			}
+			priced.Items = append(priced.Items, line)
+			continue
+		}
+
+		line.UnitPrice = product.Price
+		line.Available = product.Available
+		line.Modifiers = modifiers
+		for _, modifier := range modifiers {
+			if line.UnitPrice, err = line.UnitPrice.Add(modifier.PriceDelta); err != nil {
+				return PricedCart{}, err
+			}
+			if modifier.TrackStock {
+				line.Available = min(line.Available, modifier.Available)
+			}
+		}
+		line.LineTotal = line.UnitPrice.Mul(item.Quantity)
+		line.Availability = AvailabilityInStock
+		if item.Quantity > line.Available {
+			line.Availability = AvailabilityInsufficientStock
+		}
+		if priced.Subtotal, err = priced.Subtotal.Add(line.LineTotal); err != nil {
+			return PricedCart{}, err
+		}
+		priced.Items = append(priced.Items, line)
+	}
+	return priced, nil
+}
+
+// Catalog reads live products from inventory-service.
+type Catalog interface {
+	// Products returns the products among ids that are still sold, with
+	// their modifier groups.
+	Products(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]Product, error)
+}
+
+type CartRepository interface {
+	CreateCart(ctx context.Context, cart *Cart) error
+	// Cart returns the cart with its items, locked until the end of the
+	// transaction in ctx.
+	Cart(ctx context.Context, cartID uuid.UUID) (Cart, error)
+	// CartByUser is Cart for the cart of a user.
+	CartByUser(ctx context.Context, userID uuid.UUID) (Cart, error)
+	// AddItem adds quantity to the cart's line of the product and
+	// modifierKey, creating it if needed.
+	AddItem(ctx context.Context, cartID, productID uuid.UUID, modifierKey string, quantity int) error
+	// SetItem sets the quantity of the cart's line of the product and
+	// modifierKey.
+	SetItem(ctx context.Context, cartID, productID uuid.UUID, modifierKey string, quantity int) error
+	RemoveItem(ctx context.Context, cartID, productID uuid.UUID, modifierKey string) error
+	// ClearItems empties the cart.
+	ClearItems(ctx context.Context, cartID uuid.UUID) error
+	// UpdateCart stores the expiry and checkout of the cart.
+	UpdateCart(ctx context.Context, cart *Cart) error
+	DeleteCart(ctx context.Context, cartID uuid.UUID) error
+	// DeleteExpired removes up to limit carts that expired before now.
+	DeleteExpired(ctx context.Context, now time.Time, limit int) (int64, error)
+}
+
+// OrderCreator places orders; checkout goes through it like any other order.
+type OrderCreator interface {
+	CreateOrder(ctx context.Context, userID uuid.UUID, items []OrderItem, idempotencyKey string) (uuid.UUID, OrderStatus, error)
+	Order(ctx context.Context, orderID uuid.UUID) (Order, error)
+}
+
+type CartInteractor interface {
+	Cart(ctx context.Context, owner CartOwner) (PricedCart, error)
+	AddItem(ctx context.Context, owner CartOwner, productID uuid.UUID, modifierIDs []uuid.UUID, quantity int) (PricedCart, error)
+	UpdateItem(ctx context.Context, owner CartOwner, productID uuid.UUID, modifierIDs []uuid.UUID, quantity int) (PricedCart, error)
+	RemoveItem(ctx context.Context, owner CartOwner, productID uuid.UUID, modifierIDs []uuid.UUID) (PricedCart, error)
+	MergeCarts(ctx context.Context, cartID, userID uuid.UUID) (PricedCart, error)
+	Checkout(ctx context.Context, userID uuid.UUID, idempotencyKey string) (uuid.UUID, OrderStatus, error)
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StatusActor is who moved an order to a new status.
+type StatusActor string
+
+const (
+	ActorUser  StatusActor = "user"
+	ActorAdmin StatusActor = "admin"
+	ActorSaga  StatusActor = "saga"
+)
+
+// StatusChange is a requested move to Status together with who asked for it
+// and why. ActorID is the user or admin ID and empty for the saga.
+type StatusChange struct {
+	Status  OrderStatus
+	Actor   StatusActor
+	ActorID string
+	Reason  string
+}
+
+// OrderStatusHistory records one status change of an order. FromStatus is
+// empty for the entry written when the order is created. Completed refunds
+// add a line with RefundID and RefundAmount set that keeps the status.
+type OrderStatusHistory struct {
+	ID         int64       `gorm:"primaryKey;autoIncrement"`
+	OrderID    uuid.UUID   `gorm:"type:uuid;not null;index"`
+	FromStatus OrderStatus `gorm:"type:varchar(20);not null;default:''"`
+	ToStatus   OrderStatus `gorm:"type:varchar(20);not null"`
+	Actor      StatusActor `gorm:"type:varchar(20);not null"`
+	ActorID    string
+	Reason     string
+	ChangedAt  time.Time `gorm:"not null"`
+	// RefundID and RefundAmount are set on refund lines.
+	RefundID     *uuid.UUID  `gorm:"type:uuid"`
+	RefundAmount money.Money `gorm:"embedded;embeddedPrefix:refund_amount_"`
+}
+
+func (OrderStatusHistory) TableName() string {
+	return "order_status_history"
+}
+
+type StatusHistoryRepository interface {
+	Append(ctx context.Context, entry *OrderStatusHistory) error
+	// History returns the status changes of an order, oldest first.
+	History(ctx context.Context, orderID uuid.UUID) ([]OrderStatusHistory, error)
+}
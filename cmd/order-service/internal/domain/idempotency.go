@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrIdempotencyKeyReused = errors.New("idempotency key was used for a different request")
+)
+
+// IdempotencyKey remembers the order created for a client-supplied key, so a
+// retried CreateOrder returns that order instead of creating another one. Keys
+// are scoped to the user and RequestHash identifies the request body.
+type IdempotencyKey struct {
+	UserID      uuid.UUID   `gorm:"type:uuid;primaryKey"`
+	Key         string      `gorm:"primaryKey"`
+	RequestHash string      `gorm:"not null"`
+	OrderID     uuid.UUID   `gorm:"type:uuid;not null"`
+	Status      OrderStatus `gorm:"type:varchar(20);not null"`
+	CreatedAt   time.Time   `gorm:"autoCreateTime"`
+	ExpiresAt   time.Time   `gorm:"not null;index"`
+}
+
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+type IdempotencyRepository interface {
+	// Claim stores key unless an unexpired key with the same user and value
+	// exists, in which case that one is returned and claimed is false.
+	Claim(ctx context.Context, key *IdempotencyKey) (stored IdempotencyKey, claimed bool, err error)
+	// DeleteExpired removes up to limit keys that expired before now.
+	DeleteExpired(ctx context.Context, now time.Time, limit int) (int64, error)
+}
@@ -2,61 +2,175 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
-type OrderCreatedEvent struct {
-	OrderID  uuid.UUID        `json:"order_id"`
-	Products []OrderItemEvent `json:"products"`
-	UserID   uuid.UUID        `json:"user_id"`
-}
-
-type OrderStatusUpdateCommand struct {
-	OrderID uuid.UUID `json:"order_id"`
-	Status  string    `json:"status"`
-}
+var (
+	ErrOrderNotFound     = errors.New("order not found")
+	ErrIllegalTransition = errors.New("illegal order status transition")
+	ErrUnknownStatus     = errors.New("unknown order status")
+	ErrOrderNotDeletable = errors.New("only completed, cancelled or failed orders can be deleted")
+	ErrOrderNotPaid      = errors.New("order payment is not captured yet")
+)
 
 type Order struct {
-	ID        uuid.UUID   `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
-	UserID    uuid.UUID   `gorm:"type:uuid;not null;index"` // Связь с пользователем
-	Items     []OrderItem `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
-	Total     float64     `gorm:"type:decimal(10,2);not null"`
-	Status    string      `gorm:"type:varchar(20);not null;default:'CREATED'"`
+	ID     uuid.UUID   `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID uuid.UUID   `gorm:"type:uuid;not null;index"` // Связь с пользователем
+	Items  []OrderItem `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+	Total  money.Money `gorm:"embedded;embeddedPrefix:total_"`
+	// Refunded is the sum of completed refunds, already taken off Total.
+	Refunded  money.Money `gorm:"embedded;embeddedPrefix:refunded_"`
+	Status    OrderStatus `gorm:"type:varchar(20);not null;default:'CREATED'"`
 	CreatedAt time.Time   `gorm:"autoCreateTime"`
 	UpdatedAt time.Time   `gorm:"autoUpdateTime"`
+	// PaidAt is when the saga captured the payment; a READY order is only
+	// completed once it is set.
+	PaidAt *time.Time
+	// DeletedAt is set when the order is soft deleted; such orders are left
+	// out of queries unless asked for.
+	DeletedAt gorm.DeletedAt `gorm:"index"`
 }
 
+// OrderItem is a product line of an order. Name, Volume, UnitPrice and
+// LineTotal snapshot the product when inventory reserves it, so later price
+// changes do not alter past orders. UnitPrice includes the price of the
+// line's modifiers.
 type OrderItem struct {
 	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
 	OrderID   uuid.UUID `gorm:"type:uuid;not null;index"` // Внешний ключ
 	ProductID uuid.UUID `gorm:"type:uuid;not null"`
 	Quantity  int       `gorm:"not null"`
+	Name      string
+	Volume    int
+	UnitPrice money.Money         `gorm:"embedded;embeddedPrefix:unit_price_"`
+	LineTotal money.Money         `gorm:"embedded;embeddedPrefix:line_total_"`
+	Modifiers []OrderItemModifier `gorm:"foreignKey:OrderItemID;constraint:OnDelete:CASCADE"`
+}
+
+// LineKey identifies the product and modifiers of a line, whatever order the
+// modifiers were chosen in.
+func (i OrderItem) LineKey() string {
+	ids := make([]uuid.UUID, len(i.Modifiers))
+	for j, modifier := range i.Modifiers {
+		ids[j] = modifier.ModifierID
+	}
+	return i.ProductID.String() + "/" + ModifierKey(ids)
+}
+
+// ModifierKey joins the sorted modifier IDs, so the same choice gives the
+// same key in any order. No modifiers give an empty key.
+func ModifierKey(modifierIDs []uuid.UUID) string {
+	key := make([]string, len(modifierIDs))
+	for i, id := range modifierIDs {
+		key[i] = id.String()
+	}
+	slices.Sort(key)
+	return strings.Join(key, ",")
+}
+
+// OrderItemModifier is a modifier chosen for an order line. Name and
+// PriceDelta snapshot it when inventory reserves the order.
+type OrderItemModifier struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	OrderItemID uuid.UUID `gorm:"type:uuid;not null;index"`
+	ModifierID  uuid.UUID `gorm:"type:uuid;not null"`
+	Name        string
+	PriceDelta  money.Money `gorm:"embedded;embeddedPrefix:price_delta_"`
+}
+
+type OrderStatus string
+
+const (
+	StatusCreated   OrderStatus = "CREATED"
+	StatusReserved  OrderStatus = "RESERVED"
+	StatusPreparing OrderStatus = "PREPARING"
+	StatusReady     OrderStatus = "READY"
+	StatusCompleted OrderStatus = "COMPLETED"
+	StatusCancelled OrderStatus = "CANCELLED"
+	StatusFailed    OrderStatus = "FAILED"
+	// StatusCancelling is a cancelled order whose stock the saga is still
+	// releasing.
+	StatusCancelling OrderStatus = "CANCELLING"
+)
+
+// orderTransitions lists every status an order may move to from a given
+// status. Statuses without an entry are terminal.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	StatusCreated:    {StatusReserved, StatusCancelling, StatusFailed},
+	StatusReserved:   {StatusPreparing, StatusCancelling, StatusFailed},
+	StatusPreparing:  {StatusReady, StatusFailed},
+	StatusReady:      {StatusCompleted, StatusFailed},
+	StatusCancelling: {StatusCancelled, StatusFailed},
+}
+
+func ParseOrderStatus(status string) (OrderStatus, error) {
+	switch s := OrderStatus(status); s {
+	case StatusCreated, StatusReserved, StatusPreparing, StatusReady, StatusCompleted, StatusCancelled, StatusFailed, StatusCancelling:
+		return s, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownStatus, status)
+}
+
+// IsTerminal reports whether an order in status s can no longer change.
+func (s OrderStatus) IsTerminal() bool {
+	return len(orderTransitions[s]) == 0
 }
 
-type OrderItemEvent struct {
-	GoodID   uuid.UUID `json:"product_id"`
-	Quantity int       `json:"quantity"`
-	Price    float64   `json:"price"`
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
 }
 
 type OrderRepository interface {
 	SaveOrder(ctx context.Context, order *Order) (uuid.UUID, error)
 	GetOrder(ctx context.Context, orderID uuid.UUID) (Order, error)
+	// LockOrder is GetOrder that keeps the order locked until the end of the
+	// transaction in ctx. Soft deleted orders are returned too, so refunds
+	// started before the deletion can still finish.
+	LockOrder(ctx context.Context, orderID uuid.UUID) (Order, error)
+	// DeleteOrder soft deletes an order.
 	DeleteOrder(ctx context.Context, orderID uuid.UUID) error
-	UpdateOrderStatus(ctx context.Context, orderID uuid.UUID, status string) error
-	ListOrdersByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]Order, error)
-	ListOrders(ctx context.Context, limit, offset int) ([]Order, error)
-	SetTotalSum(ctx context.Context, orderID uuid.UUID, sum int) error
+	// RestoreOrder clears the soft delete of an order.
+	RestoreOrder(ctx context.Context, orderID uuid.UUID) error
+	// UpdateOrderStatus sets the status to to if it is still from, returning
+	// ErrIllegalTransition if the order was moved concurrently.
+	UpdateOrderStatus(ctx context.Context, orderID uuid.UUID, from, to OrderStatus) error
+	ListOrders(ctx context.Context, query OrderQuery) (OrderPage, error)
+	SetTotal(ctx context.Context, orderID uuid.UUID, total money.Money) error
+	// MarkPaid sets PaidAt of an order that does not have it yet.
+	MarkPaid(ctx context.Context, orderID uuid.UUID, paidAt time.Time) error
+	// SetRefunded stores the total and refunded sum of an order after a refund.
+	SetRefunded(ctx context.Context, orderID uuid.UUID, total, refunded money.Money) error
+	// SnapshotItems stores the name, volume and prices of items and their
+	// modifiers on the order's lines with the same product and modifiers.
+	SnapshotItems(ctx context.Context, orderID uuid.UUID, items []OrderItem) error
 }
 
 type OrderInteractor interface {
-	CreateOrder(ctx context.Context, userID uuid.UUID, orderItem []OrderItem) (uuid.UUID, string, error)
+	CreateOrder(ctx context.Context, userID uuid.UUID, orderItem []OrderItem, idempotencyKey string) (uuid.UUID, OrderStatus, error)
 	Order(ctx context.Context, orderID uuid.UUID) (Order, error)
-	ListOrdersByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]Order, error)
-	ListOrders(ctx context.Context, limit, offset int) ([]Order, error)
+	ListOrders(ctx context.Context, query OrderQuery) (OrderPage, error)
 	DeleteOrder(ctx context.Context, orderID uuid.UUID) error
-	UpdateOrderStatus(ctx context.Context, orderID uuid.UUID, status string) error
-	SetTotalSum(ctx context.Context, event ReserveProductsEventReply) error
+	RestoreOrder(ctx context.Context, orderID uuid.UUID) (Order, error)
+	UpdateOrderStatus(ctx context.Context, orderID uuid.UUID, change StatusChange) (Order, error)
+	StatusHistory(ctx context.Context, orderID uuid.UUID) ([]OrderStatusHistory, error)
+	WatchOrder(ctx context.Context, orderID uuid.UUID) (Order, <-chan events.OrderStatusChanged, func(), error)
+	HandleInventoryReserved(ctx context.Context, event events.InventoryReserved) error
+	RefundOrder(ctx context.Context, orderID uuid.UUID, request RefundRequest) (Refund, error)
+	Refunds(ctx context.Context, orderID uuid.UUID) ([]Refund, error)
+	HandleRefundCompleted(ctx context.Context, event events.OrderRefundCompleted) error
+	HandleRefundFailed(ctx context.Context, event events.OrderRefundFailed) error
 }
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOrderStatusCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to OrderStatus
+		want     bool
+	}{
+		{StatusCreated, StatusReserved, true},
+		{StatusCreated, StatusCancelling, true},
+		{StatusCreated, StatusCancelled, false},
+		{StatusCreated, StatusFailed, true},
+		{StatusCreated, StatusPreparing, false},
+		{StatusCreated, StatusCompleted, false},
+		{StatusReserved, StatusPreparing, true},
+		{StatusReserved, StatusCancelling, true},
+		{StatusReserved, StatusReady, false},
+		{StatusPreparing, StatusReady, true},
+		{StatusPreparing, StatusReserved, false},
+		{StatusReady, StatusCompleted, true},
+		// Cancelling waits for the saga to release the stock.
+		{StatusCancelling, StatusCancelled, true},
+		{StatusCancelling, StatusReserved, false},
+		// Stock of an order being made is already used up.
+		{StatusPreparing, StatusCancelling, false},
+		{StatusReady, StatusCancelling, false},
+		// Statuses only move forward, and not onto themselves.
+		{StatusReady, StatusPreparing, false},
+		{StatusReserved, StatusReserved, false},
+		// Completed, cancelled and failed orders are terminal.
+		{StatusCompleted, StatusCancelled, false},
+		{StatusCancelled, StatusReserved, false},
+		{StatusFailed, StatusCreated, false},
+		{OrderStatus("SHIPPED"), StatusCompleted, false},
+	}
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("%s -> %s allowed = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestParseOrderStatus(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    OrderStatus
+		wantErr error
+	}{
+		{in: "CREATED", want: StatusCreated},
+		{in: "READY", want: StatusReady},
+		{in: "FAILED", want: StatusFailed},
+		{in: "CANCELLING", want: StatusCancelling},
+		{in: "ready", wantErr: ErrUnknownStatus},
+		{in: "", wantErr: ErrUnknownStatus},
+		{in: "SHIPPED", wantErr: ErrUnknownStatus},
+	}
+	for _, tt := range tests {
+		got, err := ParseOrderStatus(tt.in)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("ParseOrderStatus(%q) err = %v, want %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseOrderStatus(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"errors"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidPageToken = errors.New("invalid page token")
+)
+
+type OrderSortField string
+
+const (
+	SortByCreatedAt OrderSortField = "created_at"
+	SortByTotal     OrderSortField = "total"
+)
+
+// OrderFilter narrows an order listing. Zero fields do not filter.
+// CreatedFrom is inclusive and CreatedTo exclusive; MinTotal only matches
+// orders in its currency. Soft deleted orders are only listed with
+// IncludeDeleted.
+type OrderFilter struct {
+	UserID         uuid.UUID
+	Statuses       []OrderStatus
+	CreatedFrom    time.Time
+	CreatedTo      time.Time
+	ProductID      uuid.UUID
+	MinTotal       money.Money
+	IncludeDeleted bool
+}
+
+// OrderSort orders a listing by Field, descending unless Ascending, with
+// ties broken by order ID.
+type OrderSort struct {
+	Field     OrderSortField
+	Ascending bool
+}
+
+// OrderQuery is a page of an order listing. PageToken continues after the
+// page that returned it and must be used with the same Filter and Sort.
+// Offset serves the legacy limit/offset API and is ignored with a PageToken.
+type OrderQuery struct {
+	Filter         OrderFilter
+	Sort           OrderSort
+	Limit          int
+	PageToken      string
+	Offset         int
+	WithTotalCount bool
+}
+
+// OrderPage holds one page of orders. NextPageToken is empty on the last
+// page and TotalCount is nil unless the query asked for it.
+type OrderPage struct {
+	Orders        []Order
+	NextPageToken string
+	TotalCount    *int64
+}
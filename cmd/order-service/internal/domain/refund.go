@@ -0,0 +1,216 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrRefundNotFound     = errors.New("refund not found")
+	ErrOrderNotRefundable = errors.New("only completed orders can be refunded")
+	ErrRefundExceedsPaid  = errors.New("refund exceeds what was paid and not refunded yet")
+	ErrRefundExceedsItems = errors.New("refund exceeds the ordered quantity not refunded yet")
+	ErrNothingToRefund    = errors.New("nothing left to refund")
+)
+
+type RefundStatus string
+
+const (
+	// RefundPending is a refund the refund saga is still returning.
+	RefundPending   RefundStatus = "PENDING"
+	RefundCompleted RefundStatus = "COMPLETED"
+	RefundFailed    RefundStatus = "FAILED"
+)
+
+// Refund returns Amount of a completed order to its user. Pending and
+// completed refunds count towards the limits; failed ones do not.
+type Refund struct {
+	ID            uuid.UUID    `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	OrderID       uuid.UUID    `gorm:"type:uuid;not null;index"`
+	Items         []RefundItem `gorm:"foreignKey:RefundID;constraint:OnDelete:CASCADE"`
+	Amount        money.Money  `gorm:"embedded;embeddedPrefix:amount_"`
+	Restock       bool         `gorm:"not null;default:false"`
+	Reason        string
+	Status        RefundStatus `gorm:"type:varchar(20);not null"`
+	FailureReason string
+	RequestedBy   string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (Refund) TableName() string {
+	return "order_refunds"
+}
+
+// RefundItem is a refunded quantity of an order line, Amount being its unit
+// price times the quantity.
+type RefundItem struct {
+	ID        uuid.UUID   `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	RefundID  uuid.UUID   `gorm:"type:uuid;not null;index"`
+	ProductID uuid.UUID   `gorm:"type:uuid;not null"`
+	Quantity  int         `gorm:"not null"`
+	Amount    money.Money `gorm:"embedded;embeddedPrefix:amount_"`
+}
+
+func (RefundItem) TableName() string {
+	return "order_refund_items"
+}
+
+// RefundRequest is what an admin asks to refund. Without Items everything not
+// refunded yet is.
+type RefundRequest struct {
+	Items   []RefundItem
+	Restock bool
+	Reason  string
+	AdminID string
+}
+
+// NewRefund prices request against order and the refunds it already has,
+// failing when it goes beyond what was ordered or paid.
+func NewRefund(order Order, refunds []Refund, request RefundRequest) (*Refund, error) {
+	if order.Status != StatusCompleted {
+		return nil, ErrOrderNotRefundable
+	}
+	refundedQty := make(map[uuid.UUID]int)
+	refunded := money.New(0, order.Total.Currency)
+	for _, r := range refunds {
+		if r.Status == RefundFailed {
+			continue
+		}
+		for _, item := range r.Items {
+			refundedQty[item.ProductID] += item.Quantity
+		}
+		var err error
+		if refunded, err = refunded.Add(r.Amount); err != nil {
+			return nil, err
+		}
+	}
+	// Completed refunds are already taken off the total.
+	paid, err := order.Total.Add(order.Refunded)
+	if err != nil {
+		return nil, err
+	}
+	left, err := paid.Sub(refunded)
+	if err != nil {
+		return nil, err
+	}
+
+	refund := &Refund{
+		ID:          uuid.New(),
+		OrderID:     order.ID,
+		Restock:     request.Restock,
+		Reason:      request.Reason,
+		Status:      RefundPending,
+		RequestedBy: request.AdminID,
+	}
+	// A product may be on several lines with different modifiers.
+	lines := make(map[uuid.UUID][]OrderItem, len(order.Items))
+	orderedQty := make(map[uuid.UUID]int, len(order.Items))
+	var products []uuid.UUID
+	for _, item := range order.Items {
+		if _, ok := lines[item.ProductID]; !ok {
+			products = append(products, item.ProductID)
+		}
+		lines[item.ProductID] = append(lines[item.ProductID], item)
+		orderedQty[item.ProductID] += item.Quantity
+	}
+	for _, productLines := range lines {
+		slices.SortFunc(productLines, func(a, b OrderItem) int {
+			return strings.Compare(a.ID.String(), b.ID.String())
+		})
+	}
+
+	if len(request.Items) == 0 {
+		if left.Amount <= 0 {
+			return nil, ErrNothingToRefund
+		}
+		for _, productID := range products {
+			if qty := orderedQty[productID] - refundedQty[productID]; qty > 0 {
+				lineAmount, err := refundAmount(lines[productID], refundedQty[productID], qty)
+				if err != nil {
+					return nil, err
+				}
+				refund.Items = append(refund.Items, RefundItem{
+					ID:        uuid.New(),
+					RefundID:  refund.ID,
+					ProductID: productID,
+					Quantity:  qty,
+					Amount:    lineAmount,
+				})
+			}
+		}
+		// The rest of the payment, whatever rounding the lines had.
+		refund.Amount = left
+		return refund, nil
+	}
+
+	amount := money.New(0, order.Total.Currency)
+	for _, requested := range request.Items {
+		if requested.Quantity <= 0 || refundedQty[requested.ProductID]+requested.Quantity > orderedQty[requested.ProductID] {
+			return nil, ErrRefundExceedsItems
+		}
+		lineAmount, err := refundAmount(lines[requested.ProductID], refundedQty[requested.ProductID], requested.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		refundedQty[requested.ProductID] += requested.Quantity
+		if amount, err = amount.Add(lineAmount); err != nil {
+			return nil, err
+		}
+		refund.Items = append(refund.Items, RefundItem{
+			ID:        uuid.New(),
+			RefundID:  refund.ID,
+			ProductID: requested.ProductID,
+			Quantity:  requested.Quantity,
+			Amount:    lineAmount,
+		})
+	}
+	if amount.Amount <= 0 {
+		return nil, ErrNothingToRefund
+	}
+	if amount.Amount > left.Amount {
+		return nil, ErrRefundExceedsPaid
+	}
+	refund.Amount = amount
+	return refund, nil
+}
+
+// refundAmount prices qty units of a product refunded after the first
+// refunded units, taking the units from the product's lines in turn.
+func refundAmount(lines []OrderItem, refunded, qty int) (money.Money, error) {
+	amount := money.New(0, lines[0].UnitPrice.Currency)
+	for _, line := range lines {
+		if refunded >= line.Quantity {
+			refunded -= line.Quantity
+			continue
+		}
+		n := min(line.Quantity-refunded, qty)
+		refunded = 0
+		var err error
+		if amount, err = amount.Add(line.UnitPrice.Mul(n)); err != nil {
+			return money.Money{}, err
+		}
+		if qty -= n; qty == 0 {
+			break
+		}
+	}
+	return amount, nil
+}
+
+type RefundRepository interface {
+	SaveRefund(ctx context.Context, refund *Refund) error
+	// Refund returns the refund with its items, locked until the end of the
+	// transaction in ctx.
+	Refund(ctx context.Context, refundID uuid.UUID) (Refund, error)
+	// Refunds returns the refunds of an order, oldest first.
+	Refunds(ctx context.Context, orderID uuid.UUID) ([]Refund, error)
+	// FinishRefund moves a pending refund to status, returning
+	// ErrRefundNotFound if it is not pending.
+	FinishRefund(ctx context.Context, refundID uuid.UUID, status RefundStatus, failureReason string) error
+}
@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"errors"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewRefund(t *testing.T) {
+	latte, croissant := uuid.New(), uuid.New()
+	rub := func(amount int64) money.Money { return money.New(amount, "RUB") }
+	// order is two lattes at 300 and a croissant at 400, paid total and
+	// refunded so far as given.
+	order := func(status OrderStatus, total, refunded int64) Order {
+		return Order{
+			ID:       uuid.New(),
+			Status:   status,
+			Total:    rub(total),
+			Refunded: rub(refunded),
+			Items: []OrderItem{
+				{ProductID: latte, Quantity: 2, UnitPrice: rub(300)},
+				{ProductID: croissant, Quantity: 1, UnitPrice: rub(400)},
+			},
+		}
+	}
+	refund := func(status RefundStatus, amount int64, items ...RefundItem) Refund {
+		return Refund{ID: uuid.New(), Status: status, Amount: rub(amount), Items: items}
+	}
+	lines := func(items ...RefundItem) RefundRequest { return RefundRequest{Items: items} }
+
+	tests := []struct {
+		name       string
+		order      Order
+		refunds    []Refund
+		request    RefundRequest
+		wantErr    error
+		wantAmount int64
+		wantItems  map[uuid.UUID]int
+	}{
+		{
+			name:       "everything",
+			order:      order(StatusCompleted, 1000, 0),
+			wantAmount: 1000,
+			wantItems:  map[uuid.UUID]int{latte: 2, croissant: 1},
+		},
+		{
+			name:       "one line",
+			order:      order(StatusCompleted, 1000, 0),
+			request:    lines(RefundItem{ProductID: latte, Quantity: 1}),
+			wantAmount: 300,
+			wantItems:  map[uuid.UUID]int{latte: 1},
+		},
+		{
+			name:       "the rest after a completed refund",
+			order:      order(StatusCompleted, 700, 300),
+			refunds:    []Refund{refund(RefundCompleted, 300, RefundItem{ProductID: latte, Quantity: 1})},
+			wantAmount: 700,
+			wantItems:  map[uuid.UUID]int{latte: 1, croissant: 1},
+		},
+		{
+			// Paid 999 after rounding, so a full refund returns 999 and not
+			// the 1000 the lines add up to.
+			name:       "everything returns what was paid",
+			order:      order(StatusCompleted, 999, 0),
+			wantAmount: 999,
+			wantItems:  map[uuid.UUID]int{latte: 2, croissant: 1},
+		},
+		{
+			name:       "a failed refund does not count",
+			order:      order(StatusCompleted, 1000, 0),
+			refunds:    []Refund{refund(RefundFailed, 600, RefundItem{ProductID: latte, Quantity: 2})},
+			request:    lines(RefundItem{ProductID: latte, Quantity: 2}),
+			wantAmount: 600,
+			wantItems:  map[uuid.UUID]int{latte: 2},
+		},
+		{
+			name:    "a pending refund holds its lines",
+			order:   order(StatusCompleted, 1000, 0),
+			refunds: []Refund{refund(RefundPending, 600, RefundItem{ProductID: latte, Quantity: 2})},
+			request: lines(RefundItem{ProductID: latte, Quantity: 1}),
+			wantErr: ErrRefundExceedsItems,
+		},
+		{
+			name:    "more than ordered",
+			order:   order(StatusCompleted, 1000, 0),
+			request: lines(RefundItem{ProductID: croissant, Quantity: 2}),
+			wantErr: ErrRefundExceedsItems,
+		},
+		{
+			name:    "the same line twice",
+			order:   order(StatusCompleted, 1000, 0),
+			request: lines(RefundItem{ProductID: latte, Quantity: 2}, RefundItem{ProductID: latte, Quantity: 1}),
+			wantErr: ErrRefundExceedsItems,
+		},
+		{
+			name:    "a product not in the order",
+			order:   order(StatusCompleted, 1000, 0),
+			request: lines(RefundItem{ProductID: uuid.New(), Quantity: 1}),
+			wantErr: ErrRefundExceedsItems,
+		},
+		{
+			name:    "zero quantity",
+			order:   order(StatusCompleted, 1000, 0),
+			request: lines(RefundItem{ProductID: latte, Quantity: 0}),
+			wantErr: ErrRefundExceedsItems,
+		},
+		{
+			// A discount left 800 paid; 600 of it is already being returned.
+			name:    "more than is left of the payment",
+			order:   order(StatusCompleted, 800, 0),
+			refunds: []Refund{refund(RefundPending, 600, RefundItem{ProductID: latte, Quantity: 2})},
+			request: lines(RefundItem{ProductID: croissant, Quantity: 1}),
+			wantErr: ErrRefundExceedsPaid,
+		},
+		{
+			name:    "nothing left",
+			order:   order(StatusCompleted, 0, 1000),
+			refunds: []Refund{refund(RefundCompleted, 1000, RefundItem{ProductID: latte, Quantity: 2}, RefundItem{ProductID: croissant, Quantity: 1})},
+			wantErr: ErrNothingToRefund,
+		},
+		{
+			name:    "order not completed",
+			order:   order(StatusReady, 1000, 0),
+			wantErr: ErrOrderNotRefundable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewRefund(tt.order, tt.refunds, tt.request)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Amount != rub(tt.wantAmount) || got.Status != RefundPending || got.OrderID != tt.order.ID {
+				t.Errorf("got %v %s for order %s, want %d RUB PENDING for %s", got.Amount, got.Status, got.OrderID, tt.wantAmount, tt.order.ID)
+			}
+			items := make(map[uuid.UUID]int)
+			for _, item := range got.Items {
+				if item.RefundID != got.ID {
+					t.Errorf("item of refund %s, want %s", item.RefundID, got.ID)
+				}
+				items[item.ProductID] += item.Quantity
+			}
+			if len(items) != len(tt.wantItems) {
+				t.Fatalf("items = %v, want %v", items, tt.wantItems)
+			}
+			for product, qty := range tt.wantItems {
+				if items[product] != qty {
+					t.Errorf("items = %v, want %v", items, tt.wantItems)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+
+	"github.com/google/uuid"
+)
+
+// StatusWatcher delivers the status changes of orders as they are published.
+type StatusWatcher interface {
+	// Watch subscribes to the changes of one order. The channel is closed if
+	// the subscriber falls behind or the watcher shuts down; stop ends the
+	// subscription.
+	Watch(orderID uuid.UUID) (changes <-chan events.OrderStatusChanged, stop func())
+}
@@ -0,0 +1,90 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/domain"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/lib"
+	"immxrtalbeast/order_microservices/internal/pkg/orderpb"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type adminServerAPI struct {
+	orderpb.UnimplementedOrderAdminServiceServer
+	orderInteractor domain.OrderInteractor
+}
+
+func (s *adminServerAPI) RestoreOrder(ctx context.Context, in *orderpb.RestoreOrderRequest) (*orderpb.Order, error) {
+	orderID, err := uuid.Parse(in.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order ID format")
+	}
+
+	restored, err := s.orderInteractor.RestoreOrder(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			return nil, status.Error(codes.NotFound, "no archived or deleted order with this ID")
+		}
+		return nil, status.Error(codes.Internal, "failed to restore order")
+	}
+
+	return lib.ConvertOrderToOrderpb(restored), nil
+}
+
+func (s *adminServerAPI) RefundOrder(ctx context.Context, in *orderpb.RefundOrderRequest) (*orderpb.Refund, error) {
+	orderID, err := uuid.Parse(in.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order ID format")
+	}
+	request := domain.RefundRequest{
+		Restock: in.GetRestock(),
+		Reason:  in.GetReason(),
+		AdminID: in.GetAdminId(),
+	}
+	for _, item := range in.GetItems() {
+		productID, err := uuid.Parse(item.GetProductId())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid product ID format")
+		}
+		if item.GetQuantity() <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "refund quantity must be positive")
+		}
+		request.Items = append(request.Items, domain.RefundItem{ProductID: productID, Quantity: int(item.GetQuantity())})
+	}
+
+	refund, err := s.orderInteractor.RefundOrder(ctx, orderID, request)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrOrderNotFound):
+			return nil, status.Error(codes.NotFound, "order not found")
+		case errors.Is(err, domain.ErrOrderNotRefundable),
+			errors.Is(err, domain.ErrRefundExceedsPaid),
+			errors.Is(err, domain.ErrRefundExceedsItems),
+			errors.Is(err, domain.ErrNothingToRefund):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Error(codes.Internal, "failed to refund order")
+	}
+
+	return lib.ConvertRefundToProto(refund), nil
+}
+
+func (s *adminServerAPI) ListRefunds(ctx context.Context, in *orderpb.ListRefundsRequest) (*orderpb.ListRefundsResponse, error) {
+	orderID, err := uuid.Parse(in.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order ID format")
+	}
+
+	refunds, err := s.orderInteractor.Refunds(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			return nil, status.Error(codes.NotFound, "order not found")
+		}
+		return nil, status.Error(codes.Internal, "failed to list refunds")
+	}
+
+	return &orderpb.ListRefundsResponse{Refunds: lib.ConvertRefundsToProto(refunds)}, nil
+}
@@ -0,0 +1,184 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/domain"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/lib"
+	"immxrtalbeast/order_microservices/internal/pkg/orderpb"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type cartServerAPI struct {
+	orderpb.UnimplementedCartServiceServer
+	cartInteractor domain.CartInteractor
+}
+
+func (s *cartServerAPI) GetCart(ctx context.Context, in *orderpb.GetCartRequest) (*orderpb.Cart, error) {
+	owner, err := cartOwner(in.GetOwner())
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := s.cartInteractor.Cart(ctx, owner)
+	if err != nil {
+		return nil, cartError(err, "failed to get cart")
+	}
+	return lib.ConvertCartToProto(cart), nil
+}
+
+func (s *cartServerAPI) AddCartItem(ctx context.Context, in *orderpb.AddCartItemRequest) (*orderpb.Cart, error) {
+	owner, err := cartOwner(in.GetOwner())
+	if err != nil {
+		return nil, err
+	}
+	productID, err := uuid.Parse(in.GetProductId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid product ID format")
+	}
+	if in.GetQuantity() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "quantity must be positive")
+	}
+	modifierIDs, err := parseModifierIDs(in.GetModifierIds())
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := s.cartInteractor.AddItem(ctx, owner, productID, modifierIDs, int(in.GetQuantity()))
+	if err != nil {
+		return nil, cartError(err, "failed to add cart item")
+	}
+	return lib.ConvertCartToProto(cart), nil
+}
+
+func (s *cartServerAPI) UpdateCartItem(ctx context.Context, in *orderpb.UpdateCartItemRequest) (*orderpb.Cart, error) {
+	owner, err := cartOwner(in.GetOwner())
+	if err != nil {
+		return nil, err
+	}
+	productID, err := uuid.Parse(in.GetProductId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid product ID format")
+	}
+	if in.GetQuantity() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "quantity must not be negative")
+	}
+	modifierIDs, err := parseModifierIDs(in.GetModifierIds())
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := s.cartInteractor.UpdateItem(ctx, owner, productID, modifierIDs, int(in.GetQuantity()))
+	if err != nil {
+		return nil, cartError(err, "failed to update cart item")
+	}
+	return lib.ConvertCartToProto(cart), nil
+}
+
+func (s *cartServerAPI) RemoveCartItem(ctx context.Context, in *orderpb.RemoveCartItemRequest) (*orderpb.Cart, error) {
+	owner, err := cartOwner(in.GetOwner())
+	if err != nil {
+		return nil, err
+	}
+	productID, err := uuid.Parse(in.GetProductId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid product ID format")
+	}
+	modifierIDs, err := parseModifierIDs(in.GetModifierIds())
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := s.cartInteractor.RemoveItem(ctx, owner, productID, modifierIDs)
+	if err != nil {
+		return nil, cartError(err, "failed to remove cart item")
+	}
+	return lib.ConvertCartToProto(cart), nil
+}
+
+func (s *cartServerAPI) MergeCarts(ctx context.Context, in *orderpb.MergeCartsRequest) (*orderpb.Cart, error) {
+	cartID, err := uuid.Parse(in.GetCartId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid cart ID format")
+	}
+	userID, err := uuid.Parse(in.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user ID format")
+	}
+
+	cart, err := s.cartInteractor.MergeCarts(ctx, cartID, userID)
+	if err != nil {
+		return nil, cartError(err, "failed to merge carts")
+	}
+	return lib.ConvertCartToProto(cart), nil
+}
+
+func (s *cartServerAPI) CheckoutCart(ctx context.Context, in *orderpb.CheckoutCartRequest) (*orderpb.CheckoutCartResponse, error) {
+	userID, err := uuid.Parse(in.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user ID format")
+	}
+	if len(in.GetIdempotencyKey()) > maxIdempotencyKeyLen {
+		return nil, status.Error(codes.InvalidArgument, "idempotency key is too long")
+	}
+
+	orderID, orderStatus, err := s.cartInteractor.Checkout(ctx, userID, in.GetIdempotencyKey())
+	if err != nil {
+		if errors.Is(err, domain.ErrIdempotencyKeyReused) {
+			return nil, status.Error(codes.AlreadyExists, "idempotency key was used for a different request")
+		}
+		return nil, cartError(err, "failed to check out cart")
+	}
+	return &orderpb.CheckoutCartResponse{
+		OrderId: orderID.String(),
+		Status:  lib.ConvertStatusToStatusProto(orderStatus),
+	}, nil
+}
+
+func cartOwner(in *orderpb.CartOwner) (domain.CartOwner, error) {
+	var owner domain.CartOwner
+	var err error
+	if in.GetUserId() != "" {
+		if owner.UserID, err = uuid.Parse(in.GetUserId()); err != nil {
+			return domain.CartOwner{}, status.Error(codes.InvalidArgument, "invalid user ID format")
+		}
+	}
+	if in.GetCartId() != "" {
+		if owner.CartID, err = uuid.Parse(in.GetCartId()); err != nil {
+			return domain.CartOwner{}, status.Error(codes.InvalidArgument, "invalid cart ID format")
+		}
+	}
+	return owner, nil
+}
+
+func parseModifierIDs(in []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, len(in))
+	for i, s := range in {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid modifier ID format")
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+func cartError(err error, msg string) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidModifiers):
+		return status.Error(codes.InvalidArgument, errors.Unwrap(err).Error())
+	case errors.Is(err, domain.ErrCartNotFound):
+		return status.Error(codes.NotFound, "cart not found")
+	case errors.Is(err, domain.ErrProductNotFound):
+		return status.Error(codes.NotFound, "product not found")
+	case errors.Is(err, domain.ErrCartEmpty):
+		return status.Error(codes.FailedPrecondition, domain.ErrCartEmpty.Error())
+	case errors.Is(err, domain.ErrCartNotInStock):
+		return status.Error(codes.FailedPrecondition, domain.ErrCartNotInStock.Error())
+	default:
+		return status.Error(codes.Internal, msg)
+	}
+}
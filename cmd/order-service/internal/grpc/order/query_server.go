@@ -0,0 +1,160 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/domain"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/lib"
+	"immxrtalbeast/order_microservices/internal/pkg/orderpb"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type queryServerAPI struct {
+	orderpb.UnimplementedOrderQueryServiceServer
+	orderInteractor domain.OrderInteractor
+}
+
+func (s *queryServerAPI) GetOrder(ctx context.Context, in *orderpb.GetOrderRequest) (*orderpb.Order, error) {
+	orderID, err := uuid.Parse(in.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order ID format")
+	}
+
+	domainOrder, err := s.orderInteractor.Order(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			return nil, status.Error(codes.NotFound, "order not found")
+		}
+		return nil, status.Error(codes.Internal, "failed to get order")
+	}
+
+	return lib.ConvertOrderToOrderpb(domainOrder), nil
+}
+
+func (s *queryServerAPI) ListOrders(ctx context.Context, in *orderpb.ListOrdersRequest) (*orderpb.ListOrdersResponse, error) {
+	if in.GetLimit() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit must be non-negative")
+	}
+	query, err := orderQuery(in)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	page, err := s.orderInteractor.ListOrders(ctx, query)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidPageToken) {
+			return nil, status.Error(codes.InvalidArgument, "invalid page token")
+		}
+		return nil, status.Error(codes.Internal, "failed to list orders")
+	}
+
+	return &orderpb.ListOrdersResponse{
+		Orders:        lib.ConvertOrdersToOrderpb(page.Orders),
+		NextPageToken: page.NextPageToken,
+		TotalCount:    page.TotalCount,
+	}, nil
+}
+
+func (s *queryServerAPI) WatchOrder(in *orderpb.WatchOrderRequest, stream grpc.ServerStreamingServer[orderpb.OrderStatusEvent]) error {
+	orderID, err := uuid.Parse(in.GetOrderId())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "invalid order ID format")
+	}
+
+	current, changes, stop, err := s.orderInteractor.WatchOrder(stream.Context(), orderID)
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			return status.Error(codes.NotFound, "order not found")
+		}
+		return status.Error(codes.Internal, "failed to watch order")
+	}
+	defer stop()
+
+	last := current.Status
+	err = stream.Send(&orderpb.OrderStatusEvent{
+		OrderId:   current.ID.String(),
+		To:        lib.ConvertStatusToStatusProto(current.Status),
+		ChangedAt: timestamppb.New(current.UpdatedAt),
+	})
+	if err != nil || last.IsTerminal() {
+		return err
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case change, ok := <-changes:
+			if !ok {
+				return status.Error(codes.Unavailable, "order watch interrupted, reopen it")
+			}
+			// The current status may already include changes still in flight.
+			next := domain.OrderStatus(change.To)
+			if next == last {
+				continue
+			}
+			last = next
+			err := stream.Send(&orderpb.OrderStatusEvent{
+				OrderId:   change.OrderID.String(),
+				From:      lib.ConvertStatusToStatusProto(domain.OrderStatus(change.From)),
+				To:        lib.ConvertStatusToStatusProto(next),
+				ChangedAt: timestamppb.New(change.ChangedAt),
+			})
+			if err != nil || next.IsTerminal() {
+				return err
+			}
+		}
+	}
+}
+
+func orderQuery(in *orderpb.ListOrdersRequest) (domain.OrderQuery, error) {
+	query := domain.OrderQuery{
+		Limit:          int(in.GetLimit()),
+		PageToken:      in.GetPageToken(),
+		WithTotalCount: in.GetIncludeTotalCount(),
+	}
+	if in.GetUserId() != "" {
+		userID, err := uuid.Parse(in.GetUserId())
+		if err != nil {
+			return domain.OrderQuery{}, errors.New("invalid user ID format")
+		}
+		query.Filter.UserID = userID
+	}
+
+	filter := in.GetFilter()
+	for _, s := range filter.GetStatuses() {
+		orderStatus, err := lib.ConvertStatusFromStatusProto(s)
+		if err != nil {
+			return domain.OrderQuery{}, errors.New("unknown order status")
+		}
+		query.Filter.Statuses = append(query.Filter.Statuses, orderStatus)
+	}
+	if filter.GetCreatedFrom() != nil {
+		query.Filter.CreatedFrom = filter.GetCreatedFrom().AsTime()
+	}
+	if filter.GetCreatedTo() != nil {
+		query.Filter.CreatedTo = filter.GetCreatedTo().AsTime()
+	}
+	if filter.GetProductId() != "" {
+		productID, err := uuid.Parse(filter.GetProductId())
+		if err != nil {
+			return domain.OrderQuery{}, errors.New("invalid product ID format")
+		}
+		query.Filter.ProductID = productID
+	}
+	if filter.GetMinTotal() != nil {
+		query.Filter.MinTotal = lib.ConvertMoneyFromProto(filter.GetMinTotal())
+	}
+	query.Filter.IncludeDeleted = filter.GetIncludeDeleted()
+
+	sortField, ok := lib.ConvertSortFieldFromProto(in.GetSort().GetField())
+	if !ok {
+		return domain.OrderQuery{}, errors.New("unknown sort field")
+	}
+	query.Sort = domain.OrderSort{Field: sortField, Ascending: in.GetSort().GetAscending()}
+	return query, nil
+}
@@ -2,23 +2,36 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"immxrtalbeast/order_microservices/cmd/order-service/internal/domain"
 	"immxrtalbeast/order_microservices/cmd/order-service/internal/lib"
+	"immxrtalbeast/order_microservices/internal/pkg/orderpb"
 
 	"github.com/google/uuid"
 	order "github.com/ozzus/order_protos/gen/go/order"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
+// idempotencyKeyMetadata carries the client's Idempotency-Key on CreateOrder.
+const (
+	idempotencyKeyMetadata = "idempotency-key"
+	maxIdempotencyKeyLen   = 255
+)
+
 type serverAPI struct {
 	order.UnimplementedOrderServiceServer
 	orderInteractor domain.OrderInteractor
 }
 
-func Register(gRPCServer *grpc.Server, orderInteractor domain.OrderInteractor) {
+func Register(gRPCServer *grpc.Server, orderInteractor domain.OrderInteractor, cartInteractor domain.CartInteractor) {
 	order.RegisterOrderServiceServer(gRPCServer, &serverAPI{orderInteractor: orderInteractor})
+	orderpb.RegisterOrderStatusServiceServer(gRPCServer, &statusServerAPI{orderInteractor: orderInteractor})
+	orderpb.RegisterOrderQueryServiceServer(gRPCServer, &queryServerAPI{orderInteractor: orderInteractor})
+	orderpb.RegisterOrderAdminServiceServer(gRPCServer, &adminServerAPI{orderInteractor: orderInteractor})
+	orderpb.RegisterCartServiceServer(gRPCServer, &cartServerAPI{cartInteractor: cartInteractor})
 }
 
 func (s *serverAPI) CreateOrder(ctx context.Context, in *order.CreateOrderRequest) (*order.CreateOrderResponse, error) {
@@ -50,14 +63,27 @@ func (s *serverAPI) CreateOrder(ctx context.Context, in *order.CreateOrderReques
 		}
 	}
 
-	orderID, statusStr, err := s.orderInteractor.CreateOrder(ctx, userID, domainItems)
+	var idempotencyKey string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if keys := md.Get(idempotencyKeyMetadata); len(keys) > 0 {
+			idempotencyKey = keys[0]
+		}
+	}
+	if len(idempotencyKey) > maxIdempotencyKeyLen {
+		return nil, status.Error(codes.InvalidArgument, "idempotency key is too long")
+	}
+
+	orderID, orderStatus, err := s.orderInteractor.CreateOrder(ctx, userID, domainItems, idempotencyKey)
 	if err != nil {
+		if errors.Is(err, domain.ErrIdempotencyKeyReused) {
+			return nil, status.Error(codes.AlreadyExists, "idempotency key was used for a different request")
+		}
 		return nil, status.Error(codes.Internal, "failed to create order")
 	}
 
 	return &order.CreateOrderResponse{
 		OrderId: orderID.String(),
-		Status:  lib.ConvertStatusToProto(statusStr),
+		Status:  lib.ConvertStatusToProto(orderStatus),
 	}, nil
 }
 
@@ -72,7 +98,14 @@ func (s *serverAPI) DeleteOrder(ctx context.Context, in *order.DeleteOrderReques
 	}
 
 	if err := s.orderInteractor.DeleteOrder(ctx, orderID); err != nil {
-		return nil, status.Error(codes.Internal, "failed to delete order")
+		switch {
+		case errors.Is(err, domain.ErrOrderNotFound):
+			return nil, status.Error(codes.NotFound, "order not found")
+		case errors.Is(err, domain.ErrOrderNotDeletable):
+			return nil, status.Error(codes.FailedPrecondition, domain.ErrOrderNotDeletable.Error())
+		default:
+			return nil, status.Error(codes.Internal, "failed to delete order")
+		}
 	}
 
 	return &order.DeleteOrderResponse{Success: true}, nil
@@ -112,13 +145,17 @@ func (s *serverAPI) ListOrders(ctx context.Context, in *order.ListOrdersRequest)
 		return nil, status.Error(codes.InvalidArgument, "limit and offset must be non-negative")
 	}
 
-	orders, err := s.orderInteractor.ListOrdersByUser(ctx, userID, int(in.Limit), int(in.Offset))
+	page, err := s.orderInteractor.ListOrders(ctx, domain.OrderQuery{
+		Filter: domain.OrderFilter{UserID: userID},
+		Limit:  int(in.Limit),
+		Offset: int(in.Offset),
+	})
 	if err != nil {
 		return nil, status.Error(codes.Internal, "failed to list orders")
 	}
 
 	return &order.ListOrdersResponse{
-		Orders: lib.ConvertOrdersToProto(orders),
+		Orders: lib.ConvertOrdersToProto(page.Orders),
 	}, nil
 }
 
@@ -127,12 +164,15 @@ func (s *serverAPI) ListAllOrders(ctx context.Context, in *order.ListAllOrdersRe
 		return nil, status.Error(codes.InvalidArgument, "limit and offset must be non-negative")
 	}
 
-	orders, err := s.orderInteractor.ListOrders(ctx, int(in.Limit), int(in.Offset))
+	page, err := s.orderInteractor.ListOrders(ctx, domain.OrderQuery{
+		Limit:  int(in.Limit),
+		Offset: int(in.Offset),
+	})
 	if err != nil {
 		return nil, status.Error(codes.Internal, "failed to list orders")
 	}
 
 	return &order.ListOrdersResponse{
-		Orders: lib.ConvertOrdersToProto(orders),
+		Orders: lib.ConvertOrdersToProto(page.Orders),
 	}, nil
 }
@@ -0,0 +1,76 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/domain"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/lib"
+	"immxrtalbeast/order_microservices/internal/pkg/orderpb"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type statusServerAPI struct {
+	orderpb.UnimplementedOrderStatusServiceServer
+	orderInteractor domain.OrderInteractor
+}
+
+func (s *statusServerAPI) UpdateOrderStatus(ctx context.Context, in *orderpb.UpdateOrderStatusRequest) (*orderpb.UpdateOrderStatusResponse, error) {
+	orderID, err := uuid.Parse(in.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order ID format")
+	}
+	orderStatus, err := lib.ConvertStatusFromStatusProto(in.GetStatus())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "unknown order status")
+	}
+	actor, ok := lib.ConvertActorFromProto(in.GetActor())
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "actor is required")
+	}
+
+	updated, err := s.orderInteractor.UpdateOrderStatus(ctx, orderID, domain.StatusChange{
+		Status:  orderStatus,
+		Actor:   actor,
+		ActorID: in.GetActorId(),
+		Reason:  in.GetReason(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrOrderNotFound):
+			return nil, status.Error(codes.NotFound, "order not found")
+		case errors.Is(err, domain.ErrOrderNotPaid):
+			return nil, status.Error(codes.FailedPrecondition, domain.ErrOrderNotPaid.Error())
+		case errors.Is(err, domain.ErrIllegalTransition):
+			return nil, status.Errorf(codes.FailedPrecondition, "order cannot move to %s from its current status", orderStatus)
+		default:
+			return nil, status.Error(codes.Internal, "failed to update order status")
+		}
+	}
+
+	return &orderpb.UpdateOrderStatusResponse{
+		OrderId: updated.ID.String(),
+		Status:  lib.ConvertStatusToStatusProto(updated.Status),
+	}, nil
+}
+
+func (s *statusServerAPI) OrderStatusHistory(ctx context.Context, in *orderpb.OrderStatusHistoryRequest) (*orderpb.OrderStatusHistoryResponse, error) {
+	orderID, err := uuid.Parse(in.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order ID format")
+	}
+
+	history, err := s.orderInteractor.StatusHistory(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			return nil, status.Error(codes.NotFound, "order not found")
+		}
+		return nil, status.Error(codes.Internal, "failed to get order status history")
+	}
+
+	return &orderpb.OrderStatusHistoryResponse{
+		Entries: lib.ConvertHistoryToProto(history),
+	}, nil
+}
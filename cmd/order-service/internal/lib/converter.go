@@ -2,7 +2,12 @@ package lib
 
 import (
 	"immxrtalbeast/order_microservices/cmd/order-service/internal/domain"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
+	"immxrtalbeast/order_microservices/internal/pkg/orderpb"
+	"strings"
 
+	"github.com/google/uuid"
 	order "github.com/ozzus/order_protos/gen/go/order"
 
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -21,7 +26,7 @@ func ConvertOrderToProto(o domain.Order) *order.Order {
 		Id:        o.ID.String(),
 		UserId:    o.UserID.String(),
 		Items:     items,
-		Total:     float32(o.Total),
+		Total:     float32(o.Total.Float()),
 		Status:    ConvertStatusToProto(o.Status),
 		CreatedAt: timestamppb.New(o.CreatedAt),
 		UpdatedAt: timestamppb.New(o.UpdatedAt),
@@ -36,29 +41,249 @@ func ConvertOrdersToProto(orders []domain.Order) []*order.Order {
 	return pbOrders
 }
 
-func ConvertStatusToProto(status string) order.OrderStatus {
+// ConvertOrderToOrderpb converts an order with its full status and the
+// product snapshot of its items.
+func ConvertOrderToOrderpb(o domain.Order) *orderpb.Order {
+	items := make([]*orderpb.OrderItem, len(o.Items))
+	for i, item := range o.Items {
+		items[i] = &orderpb.OrderItem{
+			ProductId: item.ProductID.String(),
+			Quantity:  int32(item.Quantity),
+			Name:      item.Name,
+			Volume:    int32(item.Volume),
+			UnitPrice: ConvertMoneyToProto(item.UnitPrice),
+			LineTotal: ConvertMoneyToProto(item.LineTotal),
+		}
+		for _, modifier := range item.Modifiers {
+			items[i].Modifiers = append(items[i].Modifiers, &orderpb.OrderItemModifier{
+				ModifierId: modifier.ModifierID.String(),
+				Name:       modifier.Name,
+				PriceDelta: ConvertMoneyToProto(modifier.PriceDelta),
+			})
+		}
+	}
+
+	converted := &orderpb.Order{
+		Id:        o.ID.String(),
+		UserId:    o.UserID.String(),
+		Items:     items,
+		Total:     ConvertMoneyToProto(o.Total),
+		Refunded:  ConvertMoneyToProto(o.Refunded),
+		Status:    ConvertStatusToStatusProto(o.Status),
+		CreatedAt: timestamppb.New(o.CreatedAt),
+		UpdatedAt: timestamppb.New(o.UpdatedAt),
+	}
+	if o.DeletedAt.Valid {
+		converted.DeletedAt = timestamppb.New(o.DeletedAt.Time)
+	}
+	return converted
+}
+
+func ConvertMoneyToProto(m money.Money) *orderpb.Money {
+	return &orderpb.Money{Amount: m.Amount, Currency: m.Currency}
+}
+
+func ConvertMoneyFromProto(m *orderpb.Money) money.Money {
+	currency := m.GetCurrency()
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+	return money.New(m.GetAmount(), currency)
+}
+
+func ConvertOrdersToOrderpb(orders []domain.Order) []*orderpb.Order {
+	pbOrders := make([]*orderpb.Order, len(orders))
+	for i, o := range orders {
+		pbOrders[i] = ConvertOrderToOrderpb(o)
+	}
+	return pbOrders
+}
+
+// ConvertStatusToProto maps a status onto the coarser OrderStatus of the
+// order API: the steps between CREATED and COMPLETED are all PROCESSING and
+// FAILED orders are reported as CANCELLED. So are CANCELLING ones, since a
+// cancel cannot be undone once accepted.
+func ConvertStatusToProto(status domain.OrderStatus) order.OrderStatus {
 	switch status {
-	case "PENDING":
+	case domain.StatusCreated:
 		return order.OrderStatus_CREATED
-	case "PROCESSING":
+	case domain.StatusReserved, domain.StatusPreparing, domain.StatusReady:
 		return order.OrderStatus_PROCESSING
-	case "COMPLETED":
+	case domain.StatusCompleted:
 		return order.OrderStatus_COMPLETED
-	case "CANCELLED":
+	case domain.StatusCancelling, domain.StatusCancelled, domain.StatusFailed:
 		return order.OrderStatus_CANCELLED
 	default:
 		return order.OrderStatus_CREATED
 	}
 }
 
-func ConvertItemstoEventItems(items []domain.OrderItem) []domain.OrderItemEvent {
-	order_items := make([]domain.OrderItemEvent, len(items))
+const statusProtoPrefix = "ORDER_STATUS_"
+
+func ConvertStatusToStatusProto(status domain.OrderStatus) orderpb.OrderStatus {
+	return orderpb.OrderStatus(orderpb.OrderStatus_value[statusProtoPrefix+string(status)])
+}
+
+func ConvertStatusFromStatusProto(status orderpb.OrderStatus) (domain.OrderStatus, error) {
+	return domain.ParseOrderStatus(strings.TrimPrefix(status.String(), statusProtoPrefix))
+}
+
+var statusActors = map[domain.StatusActor]orderpb.StatusActor{
+	domain.ActorUser:  orderpb.StatusActor_STATUS_ACTOR_USER,
+	domain.ActorAdmin: orderpb.StatusActor_STATUS_ACTOR_ADMIN,
+	domain.ActorSaga:  orderpb.StatusActor_STATUS_ACTOR_SAGA,
+}
+
+var sortFields = map[orderpb.OrderSortField]domain.OrderSortField{
+	orderpb.OrderSortField_ORDER_SORT_FIELD_UNSPECIFIED: domain.SortByCreatedAt,
+	orderpb.OrderSortField_ORDER_SORT_FIELD_CREATED_AT:  domain.SortByCreatedAt,
+	orderpb.OrderSortField_ORDER_SORT_FIELD_TOTAL:       domain.SortByTotal,
+}
+
+func ConvertSortFieldFromProto(field orderpb.OrderSortField) (domain.OrderSortField, bool) {
+	sortField, ok := sortFields[field]
+	return sortField, ok
+}
+
+func ConvertActorToProto(actor domain.StatusActor) orderpb.StatusActor {
+	return statusActors[actor]
+}
+
+func ConvertActorFromProto(actor orderpb.StatusActor) (domain.StatusActor, bool) {
+	for a, pb := range statusActors {
+		if pb == actor {
+			return a, true
+		}
+	}
+	return "", false
+}
+
+func ConvertHistoryToProto(history []domain.OrderStatusHistory) []*orderpb.OrderStatusHistoryEntry {
+	entries := make([]*orderpb.OrderStatusHistoryEntry, len(history))
+	for i, h := range history {
+		entries[i] = &orderpb.OrderStatusHistoryEntry{
+			From:      ConvertStatusToStatusProto(h.FromStatus),
+			To:        ConvertStatusToStatusProto(h.ToStatus),
+			Actor:     ConvertActorToProto(h.Actor),
+			ActorId:   h.ActorID,
+			Reason:    h.Reason,
+			ChangedAt: timestamppb.New(h.ChangedAt),
+		}
+		if h.RefundID != nil {
+			entries[i].RefundId = h.RefundID.String()
+			entries[i].RefundAmount = ConvertMoneyToProto(h.RefundAmount)
+		}
+	}
+	return entries
+}
+
+const refundStatusProtoPrefix = "REFUND_STATUS_"
+
+func ConvertRefundToProto(r domain.Refund) *orderpb.Refund {
+	items := make([]*orderpb.RefundItem, len(r.Items))
+	for i, item := range r.Items {
+		items[i] = &orderpb.RefundItem{
+			ProductId: item.ProductID.String(),
+			Quantity:  int32(item.Quantity),
+			Amount:    ConvertMoneyToProto(item.Amount),
+		}
+	}
+	return &orderpb.Refund{
+		Id:            r.ID.String(),
+		OrderId:       r.OrderID.String(),
+		Items:         items,
+		Amount:        ConvertMoneyToProto(r.Amount),
+		Restock:       r.Restock,
+		Reason:        r.Reason,
+		Status:        orderpb.RefundStatus(orderpb.RefundStatus_value[refundStatusProtoPrefix+string(r.Status)]),
+		FailureReason: r.FailureReason,
+		RequestedBy:   r.RequestedBy,
+		CreatedAt:     timestamppb.New(r.CreatedAt),
+		UpdatedAt:     timestamppb.New(r.UpdatedAt),
+	}
+}
+
+func ConvertRefundsToProto(refunds []domain.Refund) []*orderpb.Refund {
+	pbRefunds := make([]*orderpb.Refund, len(refunds))
+	for i, r := range refunds {
+		pbRefunds[i] = ConvertRefundToProto(r)
+	}
+	return pbRefunds
+}
+
+const cartItemAvailabilityProtoPrefix = "CART_ITEM_AVAILABILITY_"
+
+func ConvertCartToProto(c domain.PricedCart) *orderpb.Cart {
+	items := make([]*orderpb.CartItem, len(c.Items))
+	for i, item := range c.Items {
+		items[i] = &orderpb.CartItem{
+			ProductId:         item.ProductID.String(),
+			Quantity:          int32(item.Quantity),
+			Name:              item.Name,
+			Volume:            int32(item.Volume),
+			UnitPrice:         ConvertMoneyToProto(item.UnitPrice),
+			LineTotal:         ConvertMoneyToProto(item.LineTotal),
+			AvailableQuantity: int64(item.Available),
+			Availability:      orderpb.CartItemAvailability(orderpb.CartItemAvailability_value[cartItemAvailabilityProtoPrefix+string(item.Availability)]),
+		}
+		for _, modifier := range item.Modifiers {
+			items[i].Modifiers = append(items[i].Modifiers, &orderpb.CartItemModifier{
+				ModifierId: modifier.ID.String(),
+				Name:       modifier.Name,
+				PriceDelta: ConvertMoneyToProto(modifier.PriceDelta),
+			})
+		}
+	}
+	cart := &orderpb.Cart{
+		Items:         items,
+		Subtotal:      ConvertMoneyToProto(c.Subtotal),
+		CheckoutReady: c.CheckoutReady(),
+	}
+	if c.Cart.ID != uuid.Nil {
+		cart.Id = c.Cart.ID.String()
+		cart.UpdatedAt = timestamppb.New(c.Cart.UpdatedAt)
+		cart.ExpiresAt = timestamppb.New(c.Cart.ExpiresAt)
+	}
+	if c.Cart.UserID != nil {
+		cart.UserId = c.Cart.UserID.String()
+	}
+	return cart
+}
+
+func ConvertItemstoEventItems(items []domain.OrderItem) []events.Item {
+	order_items := make([]events.Item, len(items))
 	for i, item := range items {
-		order_items[i] = domain.OrderItemEvent{
-			GoodID:   item.ProductID,
-			Quantity: item.Quantity,
+		order_items[i] = events.Item{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		}
+		for _, modifier := range item.Modifiers {
+			order_items[i].Modifiers = append(order_items[i].Modifiers, events.ItemModifier{ModifierID: modifier.ModifierID})
 		}
 	}
 
 	return order_items
 }
+
+// ConvertEventItemsToItems reads the product snapshot of reserved items.
+func ConvertEventItemsToItems(items []events.Item) []domain.OrderItem {
+	orderItems := make([]domain.OrderItem, len(items))
+	for i, item := range items {
+		orderItems[i] = domain.OrderItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Name:      item.Name,
+			Volume:    item.Volume,
+			UnitPrice: item.Price,
+			LineTotal: item.Price.Mul(item.Quantity),
+		}
+		for _, modifier := range item.Modifiers {
+			orderItems[i].Modifiers = append(orderItems[i].Modifiers, domain.OrderItemModifier{
+				ModifierID: modifier.ModifierID,
+				Name:       modifier.Name,
+				PriceDelta: modifier.PriceDelta,
+			})
+		}
+	}
+	return orderItems
+}
@@ -0,0 +1,444 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/domain"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/lib/logger/sl"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type CartInteractor struct {
+	cartRepo   domain.CartRepository
+	catalog    domain.Catalog
+	orders     domain.OrderCreator
+	transactor domain.Transactor
+	log        *slog.Logger
+	ttl        time.Duration
+}
+
+func NewCartInteractor(cartRepo domain.CartRepository, catalog domain.Catalog, orders domain.OrderCreator, transactor domain.Transactor, log *slog.Logger, ttl time.Duration) *CartInteractor {
+	return &CartInteractor{cartRepo: cartRepo, catalog: catalog, orders: orders, transactor: transactor, log: log, ttl: ttl}
+}
+
+// Cart returns the cart of owner priced with the live catalog. A cart that
+// does not exist or has expired reads as empty.
+func (ci *CartInteractor) Cart(ctx context.Context, owner domain.CartOwner) (domain.PricedCart, error) {
+	const op = "service.cart.get"
+	log := ci.log.With(
+		slog.String("op", op),
+		slog.String("user_id", owner.UserID.String()),
+		slog.String("cart_id", owner.CartID.String()),
+	)
+	tracer := otel.Tracer("order-service")
+	ctx, span := tracer.Start(ctx, "CartService.GetCart")
+	defer span.End()
+
+	cart, err := ci.ownedCart(ctx, owner, false)
+	if errors.Is(err, domain.ErrCartNotFound) {
+		cart, err = emptyCart(owner), nil
+	}
+	if err != nil {
+		log.Error("failed to get cart", sl.Err(err))
+		span.RecordError(err)
+		return domain.PricedCart{}, fmt.Errorf("%s: %w", op, err)
+	}
+	priced, err := ci.price(ctx, cart)
+	if err != nil {
+		log.Error("failed to price cart", sl.Err(err))
+		span.RecordError(err)
+		return domain.PricedCart{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return priced, nil
+}
+
+// AddItem adds quantity of a product with modifierIDs to the cart of owner.
+// An owner with neither a user nor a live cart gets a new anonymous cart.
+func (ci *CartInteractor) AddItem(ctx context.Context, owner domain.CartOwner, productID uuid.UUID, modifierIDs []uuid.UUID, quantity int) (domain.PricedCart, error) {
+	const op = "service.cart.addItem"
+	log := ci.log.With(
+		slog.String("op", op),
+		slog.String("user_id", owner.UserID.String()),
+		slog.String("cart_id", owner.CartID.String()),
+		slog.String("product_id", productID.String()),
+		slog.Int("modifiers", len(modifierIDs)),
+		slog.Int("quantity", quantity),
+	)
+	log.Info("adding cart item")
+	tracer := otel.Tracer("order-service")
+	ctx, span := tracer.Start(ctx, "CartService.AddCartItem")
+	span.SetAttributes(
+		attribute.String("product.id", productID.String()),
+	)
+	defer span.End()
+
+	products, err := ci.catalog.Products(ctx, []uuid.UUID{productID})
+	if err == nil {
+		product, ok := products[productID]
+		if !ok {
+			err = domain.ErrProductNotFound
+		} else {
+			_, err = product.SelectModifiers(modifierIDs)
+		}
+	}
+	if err != nil {
+		log.Error("failed to look up product", sl.Err(err))
+		span.RecordError(err)
+		return domain.PricedCart{}, fmt.Errorf("%s: %w", op, err)
+	}
+	priced, err := ci.update(ctx, owner, true, func(ctx context.Context, cart *domain.Cart) error {
+		return ci.cartRepo.AddItem(ctx, cart.ID, productID, domain.ModifierKey(modifierIDs), quantity)
+	})
+	if err != nil {
+		log.Error("failed to add cart item", sl.Err(err))
+		span.RecordError(err)
+		return domain.PricedCart{}, fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("cart item added", slog.String("cart_id", priced.Cart.ID.String()))
+	return priced, nil
+}
+
+// UpdateItem sets the quantity of the line of a product with modifierIDs in
+// the cart of owner; zero removes it.
+func (ci *CartInteractor) UpdateItem(ctx context.Context, owner domain.CartOwner, productID uuid.UUID, modifierIDs []uuid.UUID, quantity int) (domain.PricedCart, error) {
+	const op = "service.cart.updateItem"
+	log := ci.log.With(
+		slog.String("op", op),
+		slog.String("user_id", owner.UserID.String()),
+		slog.String("cart_id", owner.CartID.String()),
+		slog.String("product_id", productID.String()),
+		slog.Int("quantity", quantity),
+	)
+	log.Info("updating cart item")
+	tracer := otel.Tracer("order-service")
+	ctx, span := tracer.Start(ctx, "CartService.UpdateCartItem")
+	span.SetAttributes(
+		attribute.String("product.id", productID.String()),
+	)
+	defer span.End()
+
+	modifierKey := domain.ModifierKey(modifierIDs)
+	priced, err := ci.update(ctx, owner, false, func(ctx context.Context, cart *domain.Cart) error {
+		if quantity == 0 {
+			return ci.cartRepo.RemoveItem(ctx, cart.ID, productID, modifierKey)
+		}
+		if !hasItem(*cart, productID, modifierKey) {
+			return domain.ErrProductNotFound
+		}
+		return ci.cartRepo.SetItem(ctx, cart.ID, productID, modifierKey, quantity)
+	})
+	if err != nil {
+		log.Error("failed to update cart item", sl.Err(err))
+		span.RecordError(err)
+		return domain.PricedCart{}, fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("cart item updated")
+	return priced, nil
+}
+
+func (ci *CartInteractor) RemoveItem(ctx context.Context, owner domain.CartOwner, productID uuid.UUID, modifierIDs []uuid.UUID) (domain.PricedCart, error) {
+	const op = "service.cart.removeItem"
+	log := ci.log.With(
+		slog.String("op", op),
+		slog.String("user_id", owner.UserID.String()),
+		slog.String("cart_id", owner.CartID.String()),
+		slog.String("product_id", productID.String()),
+	)
+	log.Info("removing cart item")
+	tracer := otel.Tracer("order-service")
+	ctx, span := tracer.Start(ctx, "CartService.RemoveCartItem")
+	span.SetAttributes(
+		attribute.String("product.id", productID.String()),
+	)
+	defer span.End()
+
+	priced, err := ci.update(ctx, owner, false, func(ctx context.Context, cart *domain.Cart) error {
+		return ci.cartRepo.RemoveItem(ctx, cart.ID, productID, domain.ModifierKey(modifierIDs))
+	})
+	if err != nil {
+		log.Error("failed to remove cart item", sl.Err(err))
+		span.RecordError(err)
+		return domain.PricedCart{}, fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("cart item removed")
+	return priced, nil
+}
+
+// MergeCarts adds the items of the anonymous cart cartID to the cart of
+// userID and deletes the anonymous cart. Carts that are gone, expired or
+// already belong to a user are left alone.
+func (ci *CartInteractor) MergeCarts(ctx context.Context, cartID, userID uuid.UUID) (domain.PricedCart, error) {
+	const op = "service.cart.merge"
+	log := ci.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID.String()),
+		slog.String("cart_id", cartID.String()),
+	)
+	log.Info("merging carts")
+	tracer := otel.Tracer("order-service")
+	ctx, span := tracer.Start(ctx, "CartService.MergeCarts")
+	span.SetAttributes(
+		attribute.String("user.id", userID.String()),
+		attribute.String("cart.id", cartID.String()),
+	)
+	defer span.End()
+
+	var merged int
+	priced, err := ci.update(ctx, domain.CartOwner{UserID: userID}, true, func(ctx context.Context, cart *domain.Cart) error {
+		anonymous, err := ci.cartRepo.Cart(ctx, cartID)
+		if errors.Is(err, domain.ErrCartNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if anonymous.UserID != nil {
+			return nil
+		}
+		if !anonymous.Expired(time.Now()) {
+			for _, item := range anonymous.Items {
+				if err := ci.cartRepo.AddItem(ctx, cart.ID, item.ProductID, item.ModifierKey, item.Quantity); err != nil {
+					return err
+				}
+			}
+			merged = len(anonymous.Items)
+		}
+		return ci.cartRepo.DeleteCart(ctx, anonymous.ID)
+	})
+	if err != nil {
+		log.Error("failed to merge carts", sl.Err(err))
+		span.RecordError(err)
+		return domain.PricedCart{}, fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("carts merged", slog.Int("items", merged))
+	return priced, nil
+}
+
+// Checkout places an order for the items of the user's cart through
+// CreateOrder and empties the cart. Retrying with the idempotency key of the
+// last checkout returns its order.
+func (ci *CartInteractor) Checkout(ctx context.Context, userID uuid.UUID, idempotencyKey string) (uuid.UUID, domain.OrderStatus, error) {
+	const op = "service.cart.checkout"
+	log := ci.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID.String()),
+	)
+	log.Info("checking out cart")
+	tracer := otel.Tracer("order-service")
+	ctx, span := tracer.Start(ctx, "CartService.CheckoutCart")
+	span.SetAttributes(
+		attribute.String("user.id", userID.String()),
+	)
+	defer span.End()
+
+	var (
+		orderID     uuid.UUID
+		orderStatus domain.OrderStatus
+	)
+	err := ci.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		cart, err := ci.ownedCart(ctx, domain.CartOwner{UserID: userID}, false)
+		if errors.Is(err, domain.ErrCartNotFound) {
+			return domain.ErrCartEmpty
+		}
+		if err != nil {
+			return err
+		}
+		if idempotencyKey != "" && cart.CheckoutKey == idempotencyKey && cart.CheckoutOrderID != nil {
+			order, err := ci.orders.Order(ctx, *cart.CheckoutOrderID)
+			if err != nil {
+				return err
+			}
+			orderID, orderStatus = order.ID, order.Status
+			return nil
+		}
+		priced, err := ci.price(ctx, cart)
+		if err != nil {
+			return err
+		}
+		if len(priced.Items) == 0 {
+			return domain.ErrCartEmpty
+		}
+		if !priced.CheckoutReady() {
+			return domain.ErrCartNotInStock
+		}
+		items := make([]domain.OrderItem, len(cart.Items))
+		for i, item := range cart.Items {
+			items[i] = domain.OrderItem{ProductID: item.ProductID, Quantity: item.Quantity}
+			for _, modifierID := range item.ModifierIDs() {
+				items[i].Modifiers = append(items[i].Modifiers, domain.OrderItemModifier{ModifierID: modifierID})
+			}
+		}
+		orderID, orderStatus, err = ci.orders.CreateOrder(ctx, userID, items, idempotencyKey)
+		if err != nil {
+			return err
+		}
+		if err := ci.cartRepo.ClearItems(ctx, cart.ID); err != nil {
+			return err
+		}
+		cart.CheckoutKey = idempotencyKey
+		cart.CheckoutOrderID = &orderID
+		cart.ExpiresAt = time.Now().Add(ci.ttl)
+		return ci.cartRepo.UpdateCart(ctx, &cart)
+	})
+	if err != nil {
+		log.Error("failed to check out cart", sl.Err(err))
+		span.RecordError(err)
+		return uuid.Nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("cart checked out", slog.String("order_id", orderID.String()))
+	return orderID, orderStatus, nil
+}
+
+// update runs fn on the cart of owner in a transaction, pushes its expiry
+// back and returns it priced. With create, a missing cart is created first.
+func (ci *CartInteractor) update(ctx context.Context, owner domain.CartOwner, create bool, fn func(ctx context.Context, cart *domain.Cart) error) (domain.PricedCart, error) {
+	var cart domain.Cart
+	err := ci.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		cart, err = ci.ownedCart(ctx, owner, create)
+		if err != nil {
+			return err
+		}
+		if err := fn(ctx, &cart); err != nil {
+			return err
+		}
+		cart.ExpiresAt = time.Now().Add(ci.ttl)
+		if err := ci.cartRepo.UpdateCart(ctx, &cart); err != nil {
+			return err
+		}
+		cart, err = ci.cartRepo.Cart(ctx, cart.ID)
+		return err
+	})
+	if err != nil {
+		return domain.PricedCart{}, err
+	}
+	return ci.price(ctx, cart)
+}
+
+// ownedCart returns the live cart of owner. An anonymous owner cannot reach a
+// user's cart. An expired cart is emptied and returned as new when create is
+// set, and reads as missing otherwise.
+func (ci *CartInteractor) ownedCart(ctx context.Context, owner domain.CartOwner, create bool) (domain.Cart, error) {
+	var (
+		cart domain.Cart
+		err  error
+	)
+	switch {
+	case owner.UserID != uuid.Nil:
+		cart, err = ci.cartRepo.CartByUser(ctx, owner.UserID)
+	case owner.CartID != uuid.Nil:
+		cart, err = ci.cartRepo.Cart(ctx, owner.CartID)
+		if err == nil && cart.UserID != nil {
+			err = domain.ErrCartNotFound
+		}
+	default:
+		err = domain.ErrCartNotFound
+	}
+	if errors.Is(err, domain.ErrCartNotFound) && create {
+		return ci.createCart(ctx, owner)
+	}
+	if err != nil {
+		return domain.Cart{}, err
+	}
+	if !cart.Expired(time.Now()) {
+		return cart, nil
+	}
+	if !create {
+		return domain.Cart{}, domain.ErrCartNotFound
+	}
+	if err := ci.cartRepo.ClearItems(ctx, cart.ID); err != nil {
+		return domain.Cart{}, err
+	}
+	cart.Items = nil
+	cart.CheckoutKey, cart.CheckoutOrderID = "", nil
+	return cart, nil
+}
+
+func (ci *CartInteractor) createCart(ctx context.Context, owner domain.CartOwner) (domain.Cart, error) {
+	cart := domain.Cart{
+		ID:        uuid.New(),
+		ExpiresAt: time.Now().Add(ci.ttl),
+	}
+	if owner.UserID == uuid.Nil {
+		return cart, ci.cartRepo.CreateCart(ctx, &cart)
+	}
+	cart.UserID = &owner.UserID
+	if err := ci.cartRepo.CreateCart(ctx, &cart); err != nil {
+		return domain.Cart{}, err
+	}
+	return ci.cartRepo.CartByUser(ctx, owner.UserID)
+}
+
+func (ci *CartInteractor) price(ctx context.Context, cart domain.Cart) (domain.PricedCart, error) {
+	if len(cart.Items) == 0 {
+		return domain.PriceCart(cart, nil)
+	}
+	ids := make([]uuid.UUID, len(cart.Items))
+	for i, item := range cart.Items {
+		ids[i] = item.ProductID
+	}
+	products, err := ci.catalog.Products(ctx, ids)
+	if err != nil {
+		return domain.PricedCart{}, err
+	}
+	return domain.PriceCart(cart, products)
+}
+
+// RunCartJob periodically deletes expired carts until ctx is cancelled.
+func (ci *CartInteractor) RunCartJob(ctx context.Context, interval time.Duration, batchSize int) {
+	ci.log.Info("expired cart cleanup started", slog.Duration("interval", interval))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			ci.log.Info("expired cart cleanup stopped")
+			return
+		case <-ticker.C:
+			ci.DeleteExpiredCarts(ctx, batchSize)
+		}
+	}
+}
+
+func (ci *CartInteractor) DeleteExpiredCarts(ctx context.Context, batchSize int) {
+	const op = "service.cart.deleteExpired"
+	log := ci.log.With(
+		slog.String("op", op),
+	)
+	for {
+		deleted, err := ci.cartRepo.DeleteExpired(ctx, time.Now(), batchSize)
+		if err != nil {
+			log.Error("failed to delete expired carts", sl.Err(err))
+			return
+		}
+		if deleted > 0 {
+			log.Info("expired carts deleted", slog.Int64("count", deleted))
+		}
+		if deleted < int64(batchSize) {
+			return
+		}
+	}
+}
+
+func emptyCart(owner domain.CartOwner) domain.Cart {
+	cart := domain.Cart{}
+	if owner.UserID != uuid.Nil {
+		cart.UserID = &owner.UserID
+	}
+	return cart
+}
+
+func hasItem(cart domain.Cart, productID uuid.UUID, modifierKey string) bool {
+	for _, item := range cart.Items {
+		if item.ProductID == productID && item.ModifierKey == modifierKey {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package order
+
+import (
+	"context"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/lib/logger/sl"
+	"log/slog"
+	"time"
+)
+
+// RunArchiveJob periodically moves completed orders untouched for longer
+// than after into the archive until ctx is cancelled.
+func (oi *OrderInteractor) RunArchiveJob(ctx context.Context, interval, after time.Duration, batchSize int) {
+	oi.log.Info("order archive job started", slog.Duration("interval", interval), slog.Duration("after", after))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			oi.log.Info("order archive job stopped")
+			return
+		case <-ticker.C:
+			oi.ArchiveOrders(ctx, time.Now().Add(-after), batchSize)
+		}
+	}
+}
+
+func (oi *OrderInteractor) ArchiveOrders(ctx context.Context, cutoff time.Time, batchSize int) {
+	const op = "service.order.archive"
+	log := oi.log.With(
+		slog.String("op", op),
+		slog.Time("cutoff", cutoff),
+	)
+	for {
+		archived, err := oi.archiveRepo.Archive(ctx, cutoff, batchSize)
+		if err != nil {
+			log.Error("failed to archive orders", sl.Err(err))
+			return
+		}
+		if archived > 0 {
+			log.Info("orders archived", slog.Int64("count", archived))
+		}
+		if archived < int64(batchSize) {
+			return
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package order
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/domain"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/lib/logger/sl"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+// requestHash identifies the items of a CreateOrder request regardless of
+// their order or the order of their modifiers.
+func requestHash(items []domain.OrderItem) string {
+	lines := make([]string, len(items))
+	for i, item := range items {
+		lines[i] = fmt.Sprintf("%s:%d", item.ProductID, item.Quantity)
+		if len(item.Modifiers) > 0 {
+			lines[i] = fmt.Sprintf("%s:%d", item.LineKey(), item.Quantity)
+		}
+	}
+	slices.Sort(lines)
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// RunIdempotencyJob periodically deletes expired idempotency keys until ctx
+// is cancelled.
+func (oi *OrderInteractor) RunIdempotencyJob(ctx context.Context, interval time.Duration, batchSize int) {
+	oi.log.Info("idempotency key cleanup started", slog.Duration("interval", interval))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			oi.log.Info("idempotency key cleanup stopped")
+			return
+		case <-ticker.C:
+			oi.DeleteExpiredIdempotencyKeys(ctx, batchSize)
+		}
+	}
+}
+
+func (oi *OrderInteractor) DeleteExpiredIdempotencyKeys(ctx context.Context, batchSize int) {
+	const op = "service.order.deleteExpiredIdempotencyKeys"
+	log := oi.log.With(
+		slog.String("op", op),
+	)
+	for {
+		deleted, err := oi.idempotencyRepo.DeleteExpired(ctx, time.Now(), batchSize)
+		if err != nil {
+			log.Error("failed to delete expired idempotency keys", sl.Err(err))
+			return
+		}
+		if deleted > 0 {
+			log.Info("expired idempotency keys deleted", slog.Int64("count", deleted))
+		}
+		if deleted < int64(batchSize) {
+			return
+		}
+	}
+}
@@ -2,30 +2,50 @@ package order
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"immxrtalbeast/order_microservices/cmd/order-service/internal/domain"
 	"immxrtalbeast/order_microservices/cmd/order-service/internal/lib"
 	"immxrtalbeast/order_microservices/cmd/order-service/internal/lib/logger/sl"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
 	"log/slog"
-
-	kafka "github.com/ozzus/order_kafka"
+	"time"
 
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 )
 
+const (
+	sagaRepliesTopic = "saga-replies"
+	orderEventsTopic = "order-events"
+
+	defaultListLimit = 50
+	maxListLimit     = 500
+)
+
 type OrderInteractor struct {
-	orderRepo domain.OrderRepository
-	log       *slog.Logger
-	producer  *kafka.Producer
+	orderRepo       domain.OrderRepository
+	outboxRepo      domain.OutboxRepository
+	historyRepo     domain.StatusHistoryRepository
+	idempotencyRepo domain.IdempotencyRepository
+	archiveRepo     domain.ArchiveRepository
+	refundRepo      domain.RefundRepository
+	watcher         domain.StatusWatcher
+	transactor      domain.Transactor
+	log             *slog.Logger
+	idempotencyTTL  time.Duration
 }
 
-func NewOrderInteractor(orderRepo domain.OrderRepository, log *slog.Logger, producer *kafka.Producer) *OrderInteractor {
-	return &OrderInteractor{orderRepo: orderRepo, log: log, producer: producer}
+func NewOrderInteractor(orderRepo domain.OrderRepository, outboxRepo domain.OutboxRepository, historyRepo domain.StatusHistoryRepository, idempotencyRepo domain.IdempotencyRepository, archiveRepo domain.ArchiveRepository, refundRepo domain.RefundRepository, watcher domain.StatusWatcher, transactor domain.Transactor, log *slog.Logger, idempotencyTTL time.Duration) *OrderInteractor {
+	return &OrderInteractor{orderRepo: orderRepo, outboxRepo: outboxRepo, historyRepo: historyRepo, idempotencyRepo: idempotencyRepo, archiveRepo: archiveRepo, refundRepo: refundRepo, watcher: watcher, transactor: transactor, log: log, idempotencyTTL: idempotencyTTL}
 }
 
-func (oi *OrderInteractor) CreateOrder(ctx context.Context, userID uuid.UUID, items []domain.OrderItem) (uuid.UUID, string, error) {
+// CreateOrder creates an order for userID. With a non-empty idempotencyKey a
+// repeated call returns the order created by the first one, and a call with
+// the same key but other items fails with ErrIdempotencyKeyReused.
+func (oi *OrderInteractor) CreateOrder(ctx context.Context, userID uuid.UUID, items []domain.OrderItem, idempotencyKey string) (uuid.UUID, domain.OrderStatus, error) {
 	const op = "service.order.create"
 	log := oi.log.With(
 		slog.String("op", op),
@@ -46,41 +66,79 @@ func (oi *OrderInteractor) CreateOrder(ctx context.Context, userID uuid.UUID, it
 		ID:     uuid.New(),
 		UserID: userID,
 		Items:  items,
-		Total:  0,
-		Status: "PROCESSING",
+		Total:  money.New(0, money.DefaultCurrency),
+		Status: domain.StatusCreated,
 	}
 
 	log = log.With(slog.String("order_id", order.ID.String()))
 	log.Debug("order details")
 
-	if _, err := oi.orderRepo.SaveOrder(ctx, order); err != nil {
+	replayed := false
+	err := oi.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if idempotencyKey != "" {
+			hash := requestHash(items)
+			stored, claimed, err := oi.idempotencyRepo.Claim(ctx, &domain.IdempotencyKey{
+				UserID:      userID,
+				Key:         idempotencyKey,
+				RequestHash: hash,
+				OrderID:     order.ID,
+				Status:      order.Status,
+				ExpiresAt:   time.Now().Add(oi.idempotencyTTL),
+			})
+			if err != nil {
+				return err
+			}
+			if !claimed {
+				if stored.RequestHash != hash {
+					return domain.ErrIdempotencyKeyReused
+				}
+				// The saga may have moved the order on since the key was stored.
+				current, err := oi.orderRepo.GetOrder(ctx, stored.OrderID)
+				if err != nil {
+					return err
+				}
+				order.ID, order.Status = current.ID, current.Status
+				replayed = true
+				return nil
+			}
+		}
+		if _, err := oi.orderRepo.SaveOrder(ctx, order); err != nil {
+			return err
+		}
+		err := oi.historyRepo.Append(ctx, &domain.OrderStatusHistory{
+			OrderID:   order.ID,
+			ToStatus:  order.Status,
+			Actor:     domain.ActorUser,
+			ActorID:   userID.String(),
+			ChangedAt: time.Now().UTC(),
+		})
+		if err != nil {
+			return err
+		}
+
+		event := events.OrderCreated{
+			OrderID: order.ID,
+			UserID:  order.UserID,
+			Items:   lib.ConvertItemstoEventItems(order.Items),
+		}
+		return oi.enqueue(ctx, sagaRepliesTopic, order.ID, event)
+	})
+	if err != nil {
 		log.Error("failed to create order", sl.Err(err))
 		span.RecordError(err)
 		return uuid.Nil, "", fmt.Errorf("%s: %w", op, err)
 	}
-
-	products := lib.ConvertItemstoEventItems(order.Items)
-
-	event := domain.OrderCreatedEvent{
-		OrderID:  order.ID,
-		Products: products,
-		UserID:   order.UserID,
-	}
-
-	if err := oi.producer.PublishEventWithEventType(ctx, "OrderCreatedEvent", event, "OrderCreatedEvent"); err != nil {
-		log.Error("failed to publish event", sl.Err(err))
-		span.RecordError(err)
-		if statusErr := oi.orderRepo.UpdateOrderStatus(ctx, order.ID, "CANCELLED"); statusErr != nil {
-			log.Error("failed to mark order as cancelled after publish failure", sl.Err(statusErr))
-			span.RecordError(statusErr)
-		}
-		return uuid.Nil, "", fmt.Errorf("%s: publish order event: %w", op, err)
+	if replayed {
+		log.Info("order replayed for idempotency key", slog.String("stored_order_id", order.ID.String()))
+		return order.ID, order.Status, nil
 	}
 
 	log.Info("Order created")
 	return order.ID, order.Status, nil
 }
 
+// DeleteOrder soft deletes an order. Orders still in progress may hold stock
+// or be driven by a saga, so only terminal ones can be deleted.
 func (oi *OrderInteractor) DeleteOrder(ctx context.Context, orderID uuid.UUID) error {
 	const op = "service.order.delete"
 	log := oi.log.With(
@@ -94,7 +152,17 @@ func (oi *OrderInteractor) DeleteOrder(ctx context.Context, orderID uuid.UUID) e
 		attribute.String("order.id", orderID.String()),
 	)
 	defer span.End()
-	if err := oi.orderRepo.DeleteOrder(ctx, orderID); err != nil {
+	err := oi.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		order, err := oi.orderRepo.GetOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if !order.Status.IsTerminal() {
+			return domain.ErrOrderNotDeletable
+		}
+		return oi.orderRepo.DeleteOrder(ctx, orderID)
+	})
+	if err != nil {
 		log.Error("failed to delete order", sl.Err(err))
 		span.RecordError(err)
 		return fmt.Errorf("%s: %w", op, err)
@@ -103,6 +171,42 @@ func (oi *OrderInteractor) DeleteOrder(ctx context.Context, orderID uuid.UUID) e
 	return nil
 }
 
+// RestoreOrder brings back an archived or soft deleted order.
+func (oi *OrderInteractor) RestoreOrder(ctx context.Context, orderID uuid.UUID) (domain.Order, error) {
+	const op = "service.order.restore"
+	log := oi.log.With(
+		slog.String("op", op),
+		slog.String("order_id", orderID.String()),
+	)
+	log.Info("restoring order")
+	tracer := otel.Tracer("order-service")
+	ctx, span := tracer.Start(ctx, "OrderService.RestoreOrder")
+	span.SetAttributes(
+		attribute.String("order.id", orderID.String()),
+	)
+	defer span.End()
+
+	var order domain.Order
+	err := oi.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := oi.archiveRepo.Restore(ctx, orderID)
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			err = oi.orderRepo.RestoreOrder(ctx, orderID)
+		}
+		if err != nil {
+			return err
+		}
+		order, err = oi.orderRepo.GetOrder(ctx, orderID)
+		return err
+	})
+	if err != nil {
+		log.Error("failed to restore order", sl.Err(err))
+		span.RecordError(err)
+		return domain.Order{}, fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("order restored")
+	return order, nil
+}
+
 func (oi *OrderInteractor) Order(ctx context.Context, orderID uuid.UUID) (domain.Order, error) {
 	const op = "service.order.get"
 	log := oi.log.With(
@@ -127,101 +231,262 @@ func (oi *OrderInteractor) Order(ctx context.Context, orderID uuid.UUID) (domain
 	return order, nil
 }
 
-func (oi *OrderInteractor) ListOrdersByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.Order, error) {
-	const op = "service.order.list_by_user"
+// ListOrders returns a page of orders matching query. A zero limit defaults
+// to defaultListLimit and larger ones are capped at maxListLimit.
+func (oi *OrderInteractor) ListOrders(ctx context.Context, query domain.OrderQuery) (domain.OrderPage, error) {
+	const op = "service.order.list"
+	if query.Limit <= 0 {
+		query.Limit = defaultListLimit
+	}
+	query.Limit = min(query.Limit, maxListLimit)
 	log := oi.log.With(
 		slog.String("op", op),
-		slog.String("user_id", userID.String()),
-		slog.Int("limit", limit),
-		slog.Int("offset", offset),
+		slog.Any("filter", query.Filter),
+		slog.Any("sort", query.Sort),
+		slog.Int("limit", query.Limit),
+		slog.Bool("paged", query.PageToken != ""),
 	)
-	log.Info("getting a list of orders by user")
+	log.Info("listing orders")
 
 	tracer := otel.Tracer("order-service")
-	ctx, span := tracer.Start(ctx, "OrderService.ListOrdersByUser")
-	span.SetAttributes(
-		attribute.String("user.id", userID.String()),
-	)
+	ctx, span := tracer.Start(ctx, "OrderService.ListOrders")
+	if query.Filter.UserID != uuid.Nil {
+		span.SetAttributes(
+			attribute.String("user.id", query.Filter.UserID.String()),
+		)
+	}
 	defer span.End()
 
-	orders, err := oi.orderRepo.ListOrdersByUser(ctx, userID, limit, offset)
+	page, err := oi.orderRepo.ListOrders(ctx, query)
 	if err != nil {
-		log.Error("failed to get a list of orders by user", sl.Err(err))
+		log.Error("failed to list orders", sl.Err(err))
 		span.RecordError(err)
-		return nil, fmt.Errorf("%s: %w", op, err)
+		return domain.OrderPage{}, fmt.Errorf("%s: %w", op, err)
 	}
-	log.Info("orders listed", slog.Int("count", len(orders)))
-	return orders, nil
+	log.Info("orders listed", slog.Int("count", len(page.Orders)))
+	return page, nil
 }
 
-func (oi *OrderInteractor) ListOrders(ctx context.Context, limit, offset int) ([]domain.Order, error) {
-	const op = "service.order.list"
+// WatchOrder returns the order together with its later status changes. It
+// subscribes before reading the order so no change in between is missed; the
+// caller must call stop once done.
+func (oi *OrderInteractor) WatchOrder(ctx context.Context, orderID uuid.UUID) (domain.Order, <-chan events.OrderStatusChanged, func(), error) {
+	const op = "service.order.watch"
 	log := oi.log.With(
 		slog.String("op", op),
-		slog.Int("limit", limit),
-		slog.Int("offset", offset),
+		slog.String("order_id", orderID.String()),
 	)
-	log.Info("getting a list of all orders")
-
+	log.Info("watching order")
 	tracer := otel.Tracer("order-service")
-	ctx, span := tracer.Start(ctx, "OrderService.ListOrders")
+	ctx, span := tracer.Start(ctx, "OrderService.WatchOrder")
+	span.SetAttributes(
+		attribute.String("order.id", orderID.String()),
+	)
 	defer span.End()
 
-	orders, err := oi.orderRepo.ListOrders(ctx, limit, offset)
+	changes, stop := oi.watcher.Watch(orderID)
+	order, err := oi.orderRepo.GetOrder(ctx, orderID)
 	if err != nil {
-		log.Error("failed to get a list of orders", sl.Err(err))
+		stop()
+		log.Error("failed to get watched order", sl.Err(err))
 		span.RecordError(err)
-		return nil, fmt.Errorf("%s: %w", op, err)
+		return domain.Order{}, nil, nil, fmt.Errorf("%s: %w", op, err)
 	}
-	log.Info("orders listed", slog.Int("count", len(orders)))
-	return orders, nil
+	return order, changes, stop, nil
 }
 
-func (oi *OrderInteractor) UpdateOrderStatus(ctx context.Context, orderID uuid.UUID, status string) error {
+func (oi *OrderInteractor) UpdateOrderStatus(ctx context.Context, orderID uuid.UUID, change domain.StatusChange) (domain.Order, error) {
 	const op = "service.order.update_status"
 	log := oi.log.With(
 		slog.String("op", op),
 		slog.String("order_id", orderID.String()),
-		slog.String("status", status),
+		slog.String("status", string(change.Status)),
+		slog.String("actor", string(change.Actor)),
 	)
 	log.Info("updating order status")
 	tracer := otel.Tracer("order-service")
 	ctx, span := tracer.Start(ctx, "OrderService.UpdateOrderStatus")
 	span.SetAttributes(
 		attribute.String("order.id", orderID.String()),
-		attribute.String("order.status", status),
+		attribute.String("order.status", string(change.Status)),
+		attribute.String("order.status_actor", string(change.Actor)),
 	)
 	defer span.End()
 
-	if err := oi.orderRepo.UpdateOrderStatus(ctx, orderID, status); err != nil {
+	// Users and admins only request a cancellation: the saga cancels the
+	// order once its stock is released.
+	if change.Status == domain.StatusCancelled && change.Actor != domain.ActorSaga {
+		change.Status = domain.StatusCancelling
+	}
+
+	var order domain.Order
+	err := oi.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if order, err = oi.orderRepo.GetOrder(ctx, orderID); err != nil {
+			return err
+		}
+		if order.Status == change.Status ||
+			change.Status == domain.StatusCancelling && order.Status == domain.StatusCancelled {
+			log.Info("order already has the status")
+			return nil
+		}
+		return oi.transition(ctx, &order, change)
+	})
+	if err != nil {
 		log.Error("failed to update order status", sl.Err(err))
 		span.RecordError(err)
-		return fmt.Errorf("%s: %w", op, err)
+		return domain.Order{}, fmt.Errorf("%s: %w", op, err)
 	}
 	log.Info("order status updated")
-	return nil
+	return order, nil
+}
+
+func (oi *OrderInteractor) StatusHistory(ctx context.Context, orderID uuid.UUID) ([]domain.OrderStatusHistory, error) {
+	const op = "service.order.status_history"
+	log := oi.log.With(
+		slog.String("op", op),
+		slog.String("order_id", orderID.String()),
+	)
+	log.Info("getting order status history")
+	tracer := otel.Tracer("order-service")
+	ctx, span := tracer.Start(ctx, "OrderService.StatusHistory")
+	span.SetAttributes(
+		attribute.String("order.id", orderID.String()),
+	)
+	defer span.End()
+
+	if _, err := oi.orderRepo.GetOrder(ctx, orderID); err != nil {
+		log.Error("failed to get order", sl.Err(err))
+		span.RecordError(err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	history, err := oi.historyRepo.History(ctx, orderID)
+	if err != nil {
+		log.Error("failed to get order status history", sl.Err(err))
+		span.RecordError(err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return history, nil
 }
 
-func (oi *OrderInteractor) SetTotalSum(ctx context.Context, event domain.ReserveProductsEventReply) error {
-	const op = "service.order.set-total-sum"
+// HandleInventoryReserved records the order total and the reserved prices of
+// its items and marks the order reserved.
+func (oi *OrderInteractor) HandleInventoryReserved(ctx context.Context, event events.InventoryReserved) error {
+	const op = "service.order.handle-inventory-reserved"
 	log := oi.log.With(
 		slog.String("op", op),
 		slog.String("order_id", event.OrderID.String()),
 		slog.String("saga_id", event.SagaID.String()),
-		slog.Int("total-sum", event.TotalSum),
+		slog.String("total", event.Total.String()),
 	)
 	log.Info("setting sum")
 	tracer := otel.Tracer("order-service")
-	ctx, span := tracer.Start(ctx, "OrderService.SetTotalSum")
+	ctx, span := tracer.Start(ctx, "OrderService.HandleInventoryReserved")
 	span.SetAttributes(
 		attribute.String("saga.id", event.SagaID.String()),
-		attribute.Int("total-sum", event.TotalSum),
+		attribute.String("total", event.Total.String()),
 	)
 	defer span.End()
-	if err := oi.orderRepo.SetTotalSum(ctx, event.OrderID, event.TotalSum); err != nil {
-		log.Error("failed to update order sum", sl.Err(err))
+	err := oi.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		order, err := oi.orderRepo.GetOrder(ctx, event.OrderID)
+		if err != nil {
+			return err
+		}
+		if err := oi.orderRepo.SetTotal(ctx, event.OrderID, event.Total); err != nil {
+			return err
+		}
+		if err := oi.orderRepo.SnapshotItems(ctx, event.OrderID, lib.ConvertEventItemsToItems(event.Items)); err != nil {
+			return err
+		}
+		return oi.transition(ctx, &order, domain.StatusChange{
+			Status: domain.StatusReserved,
+			Actor:  domain.ActorSaga,
+			Reason: "inventory reserved",
+		})
+	})
+	if err != nil {
+		log.Error("failed to mark order reserved", sl.Err(err))
 		span.RecordError(err)
 		return fmt.Errorf("%s: %w", op, err)
 	}
 	return nil
 }
+
+// HandlePaymentCaptured records that the order is paid, so it can be completed.
+func (oi *OrderInteractor) HandlePaymentCaptured(ctx context.Context, event events.PaymentCaptured) error {
+	const op = "service.order.handle-payment-captured"
+	log := oi.log.With(
+		slog.String("op", op),
+		slog.String("order_id", event.OrderID.String()),
+		slog.String("saga_id", event.SagaID.String()),
+	)
+	tracer := otel.Tracer("order-service")
+	ctx, span := tracer.Start(ctx, "OrderService.HandlePaymentCaptured")
+	span.SetAttributes(
+		attribute.String("saga.id", event.SagaID.String()),
+		attribute.String("order.id", event.OrderID.String()),
+	)
+	defer span.End()
+	if err := oi.orderRepo.MarkPaid(ctx, event.OrderID, time.Now().UTC()); err != nil {
+		log.Error("failed to mark order paid", sl.Err(err))
+		span.RecordError(err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("order paid")
+	return nil
+}
+
+// transition moves order to change.Status if the status machine allows it,
+// records the change in the status history and queues the StatusChanged event.
+// Call it inside a transaction.
+func (oi *OrderInteractor) transition(ctx context.Context, order *domain.Order, change domain.StatusChange) error {
+	current, next := order.Status, change.Status
+	if !current.CanTransitionTo(next) {
+		return fmt.Errorf("%w: %s -> %s", domain.ErrIllegalTransition, current, next)
+	}
+	if next == domain.StatusCompleted && order.PaidAt == nil {
+		return domain.ErrOrderNotPaid
+	}
+	if err := oi.orderRepo.UpdateOrderStatus(ctx, order.ID, current, next); err != nil {
+		return err
+	}
+	order.Status = next
+	changedAt := time.Now().UTC()
+	err := oi.historyRepo.Append(ctx, &domain.OrderStatusHistory{
+		OrderID:    order.ID,
+		FromStatus: current,
+		ToStatus:   next,
+		Actor:      change.Actor,
+		ActorID:    change.ActorID,
+		Reason:     change.Reason,
+		ChangedAt:  changedAt,
+	})
+	if err != nil {
+		return err
+	}
+	changed := events.OrderStatusChanged{
+		OrderID:   order.ID,
+		From:      string(current),
+		To:        string(next),
+		ChangedAt: changedAt,
+	}
+	if err := oi.enqueue(ctx, orderEventsTopic, order.ID, changed); err != nil {
+		return err
+	}
+	switch next {
+	case domain.StatusReady:
+		return oi.enqueue(ctx, sagaRepliesTopic, order.ID, events.OrderReady{OrderID: order.ID})
+	case domain.StatusCompleted:
+		return oi.enqueue(ctx, sagaRepliesTopic, order.ID, events.OrderCompleted{OrderID: order.ID})
+	case domain.StatusCancelling:
+		// Keyed like OrderCreated, so the saga always sees it after the saga
+		// has started.
+		return oi.enqueue(ctx, sagaRepliesTopic, order.ID, events.CancelOrder{OrderID: order.ID})
+	}
+	return nil
+}
+
+// enqueue writes event to the outbox within the caller's transaction.
+func (oi *OrderInteractor) enqueue(ctx context.Context, topic string, orderID uuid.UUID, event events.Event) error {
+	return oi.outboxRepo.Enqueue(ctx, topic, orderID.String(), event)
+}
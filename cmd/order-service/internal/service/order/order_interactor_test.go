@@ -0,0 +1,146 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/domain"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type fakeOrderRepo struct {
+	domain.OrderRepository
+	saved []domain.Order
+}
+
+func (r *fakeOrderRepo) SaveOrder(ctx context.Context, order *domain.Order) (uuid.UUID, error) {
+	r.saved = append(r.saved, *order)
+	return order.ID, nil
+}
+
+func (r *fakeOrderRepo) GetOrder(ctx context.Context, orderID uuid.UUID) (domain.Order, error) {
+	for _, order := range r.saved {
+		if order.ID == orderID {
+			return order, nil
+		}
+	}
+	return domain.Order{}, domain.ErrOrderNotFound
+}
+
+type fakeHistoryRepo struct {
+	domain.StatusHistoryRepository
+}
+
+func (fakeHistoryRepo) Append(ctx context.Context, entry *domain.OrderStatusHistory) error {
+	return nil
+}
+
+// fakeIdempotencyRepo keeps keys by user and value, like the primary key of
+// idempotency_keys.
+type fakeIdempotencyRepo struct {
+	domain.IdempotencyRepository
+	keys map[string]domain.IdempotencyKey
+}
+
+func (r *fakeIdempotencyRepo) Claim(ctx context.Context, key *domain.IdempotencyKey) (domain.IdempotencyKey, bool, error) {
+	id := key.UserID.String() + "/" + key.Key
+	if stored, ok := r.keys[id]; ok {
+		return stored, false, nil
+	}
+	r.keys[id] = *key
+	return *key, true, nil
+}
+
+type fakeOutbox struct {
+	sent []string
+}
+
+func (o *fakeOutbox) Enqueue(ctx context.Context, topic, aggregateID string, event events.Event) error {
+	o.sent = append(o.sent, event.EventType())
+	return nil
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestCreateOrderIdempotencyKey(t *testing.T) {
+	user := uuid.New()
+	pen, ink := uuid.New(), uuid.New()
+	items := []domain.OrderItem{{ProductID: pen, Quantity: 2}, {ProductID: ink, Quantity: 1}}
+
+	tests := []struct {
+		name        string
+		user        uuid.UUID
+		key         string
+		items       []domain.OrderItem
+		wantReplay  bool
+		wantErr     error
+		wantCreated int
+	}{
+		{name: "retry with the same body", user: user, key: "k-1", items: items, wantReplay: true, wantCreated: 1},
+		{
+			name:        "same lines in another order",
+			user:        user,
+			key:         "k-1",
+			items:       []domain.OrderItem{{ProductID: ink, Quantity: 1}, {ProductID: pen, Quantity: 2}},
+			wantReplay:  true,
+			wantCreated: 1,
+		},
+		{
+			name:        "same key with another quantity",
+			user:        user,
+			key:         "k-1",
+			items:       []domain.OrderItem{{ProductID: pen, Quantity: 3}, {ProductID: ink, Quantity: 1}},
+			wantErr:     domain.ErrIdempotencyKeyReused,
+			wantCreated: 1,
+		},
+		{name: "another key", user: user, key: "k-2", items: items, wantCreated: 2},
+		{name: "same key of another user", user: uuid.New(), key: "k-1", items: items, wantCreated: 2},
+		{name: "no key", user: user, items: items, wantCreated: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := &fakeOrderRepo{}
+			outbox := &fakeOutbox{}
+			oi := NewOrderInteractor(orders, outbox, fakeHistoryRepo{}, &fakeIdempotencyRepo{keys: map[string]domain.IdempotencyKey{}}, nil, nil, nil, fakeTransactor{}, slog.New(slog.NewTextHandler(io.Discard, nil)), time.Hour)
+			ctx := context.Background()
+
+			firstID, _, err := oi.CreateOrder(ctx, user, items, "k-1")
+			if err != nil {
+				t.Fatalf("first call: %v", err)
+			}
+			// The saga reserves the stock before the client retries.
+			orders.saved[0].Status = domain.StatusReserved
+			id, status, err := oi.CreateOrder(ctx, tt.user, tt.items, tt.key)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if len(orders.saved) != tt.wantCreated || len(outbox.sent) != tt.wantCreated {
+				t.Errorf("created %d orders and %d events, want %d", len(orders.saved), len(outbox.sent), tt.wantCreated)
+			}
+			switch {
+			case tt.wantErr != nil:
+				if id != uuid.Nil {
+					t.Errorf("id = %s on error, want none", id)
+				}
+			case tt.wantReplay:
+				if id != firstID || status != domain.StatusReserved {
+					t.Errorf("got %s %s, want the first order %s as it is now, RESERVED", id, status, firstID)
+				}
+			default:
+				if id == firstID {
+					t.Errorf("got the first order %s, want a new one", id)
+				}
+			}
+		})
+	}
+}
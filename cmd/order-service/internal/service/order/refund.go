@@ -0,0 +1,208 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/domain"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/lib/logger/sl"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// RefundOrder records a pending refund of a completed order and hands it to
+// the refund saga. The order is locked so concurrent refunds cannot together
+// go beyond what was paid.
+func (oi *OrderInteractor) RefundOrder(ctx context.Context, orderID uuid.UUID, request domain.RefundRequest) (domain.Refund, error) {
+	const op = "service.order.refund"
+	log := oi.log.With(
+		slog.String("op", op),
+		slog.String("order_id", orderID.String()),
+		slog.String("admin_id", request.AdminID),
+		slog.Int("items", len(request.Items)),
+		slog.Bool("restock", request.Restock),
+	)
+	log.Info("refunding order")
+	tracer := otel.Tracer("order-service")
+	ctx, span := tracer.Start(ctx, "OrderService.RefundOrder")
+	span.SetAttributes(
+		attribute.String("order.id", orderID.String()),
+		attribute.Bool("refund.restock", request.Restock),
+	)
+	defer span.End()
+
+	var refund *domain.Refund
+	err := oi.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		order, err := oi.orderRepo.LockOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if order.DeletedAt.Valid {
+			return domain.ErrOrderNotFound
+		}
+		refunds, err := oi.refundRepo.Refunds(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if refund, err = domain.NewRefund(order, refunds, request); err != nil {
+			return err
+		}
+		if err := oi.refundRepo.SaveRefund(ctx, refund); err != nil {
+			return err
+		}
+
+		items := make([]events.Item, 0, len(refund.Items))
+		for _, item := range refund.Items {
+			items = append(items, events.Item{ProductID: item.ProductID, Quantity: item.Quantity})
+		}
+		return oi.enqueue(ctx, sagaRepliesTopic, order.ID, events.RefundOrder{
+			OrderID:  order.ID,
+			RefundID: refund.ID,
+			UserID:   order.UserID,
+			Amount:   refund.Amount,
+			Items:    items,
+			Restock:  refund.Restock,
+			Reason:   refund.Reason,
+		})
+	})
+	if err != nil {
+		log.Error("failed to refund order", sl.Err(err))
+		span.RecordError(err)
+		return domain.Refund{}, fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("refund requested", slog.String("refund_id", refund.ID.String()), slog.String("amount", refund.Amount.String()))
+	return *refund, nil
+}
+
+func (oi *OrderInteractor) Refunds(ctx context.Context, orderID uuid.UUID) ([]domain.Refund, error) {
+	const op = "service.order.refunds"
+	log := oi.log.With(
+		slog.String("op", op),
+		slog.String("order_id", orderID.String()),
+	)
+	log.Info("listing order refunds")
+	tracer := otel.Tracer("order-service")
+	ctx, span := tracer.Start(ctx, "OrderService.Refunds")
+	span.SetAttributes(
+		attribute.String("order.id", orderID.String()),
+	)
+	defer span.End()
+
+	if _, err := oi.orderRepo.GetOrder(ctx, orderID); err != nil {
+		log.Error("failed to get order", sl.Err(err))
+		span.RecordError(err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	refunds, err := oi.refundRepo.Refunds(ctx, orderID)
+	if err != nil {
+		log.Error("failed to list order refunds", sl.Err(err))
+		span.RecordError(err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return refunds, nil
+}
+
+// HandleRefundCompleted takes the refunded amount off the order total and adds
+// a refund line to its history. Redelivered events find the refund finished
+// and are skipped.
+func (oi *OrderInteractor) HandleRefundCompleted(ctx context.Context, event events.OrderRefundCompleted) error {
+	const op = "service.order.handle-refund-completed"
+	log := oi.log.With(
+		slog.String("op", op),
+		slog.String("order_id", event.OrderID.String()),
+		slog.String("refund_id", event.RefundID.String()),
+		slog.String("amount", event.Amount.String()),
+	)
+	log.Info("completing refund")
+	tracer := otel.Tracer("order-service")
+	ctx, span := tracer.Start(ctx, "OrderService.HandleRefundCompleted")
+	span.SetAttributes(
+		attribute.String("order.id", event.OrderID.String()),
+		attribute.String("refund.id", event.RefundID.String()),
+	)
+	defer span.End()
+
+	err := oi.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		order, err := oi.orderRepo.LockOrder(ctx, event.OrderID)
+		if err != nil {
+			return err
+		}
+		refund, err := oi.refundRepo.Refund(ctx, event.RefundID)
+		if err != nil {
+			return err
+		}
+		if refund.Status != domain.RefundPending {
+			log.Info("refund already finished", slog.String("status", string(refund.Status)))
+			return nil
+		}
+		if err := oi.refundRepo.FinishRefund(ctx, refund.ID, domain.RefundCompleted, ""); err != nil {
+			return err
+		}
+		total, err := order.Total.Sub(refund.Amount)
+		if err != nil {
+			return err
+		}
+		refunded, err := order.Refunded.Add(refund.Amount)
+		if err != nil {
+			return err
+		}
+		if err := oi.orderRepo.SetRefunded(ctx, order.ID, total, refunded); err != nil {
+			return err
+		}
+		return oi.historyRepo.Append(ctx, &domain.OrderStatusHistory{
+			OrderID:      order.ID,
+			FromStatus:   order.Status,
+			ToStatus:     order.Status,
+			Actor:        domain.ActorAdmin,
+			ActorID:      refund.RequestedBy,
+			Reason:       refund.Reason,
+			ChangedAt:    time.Now().UTC(),
+			RefundID:     &refund.ID,
+			RefundAmount: refund.Amount,
+		})
+	})
+	if err != nil {
+		log.Error("failed to complete refund", sl.Err(err))
+		span.RecordError(err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("refund completed")
+	return nil
+}
+
+// HandleRefundFailed marks the refund failed, which frees its amount and
+// quantities for another attempt.
+func (oi *OrderInteractor) HandleRefundFailed(ctx context.Context, event events.OrderRefundFailed) error {
+	const op = "service.order.handle-refund-failed"
+	log := oi.log.With(
+		slog.String("op", op),
+		slog.String("order_id", event.OrderID.String()),
+		slog.String("refund_id", event.RefundID.String()),
+		slog.String("reason", event.Reason),
+	)
+	log.Warn("refund failed")
+	tracer := otel.Tracer("order-service")
+	ctx, span := tracer.Start(ctx, "OrderService.HandleRefundFailed")
+	span.SetAttributes(
+		attribute.String("order.id", event.OrderID.String()),
+		attribute.String("refund.id", event.RefundID.String()),
+	)
+	defer span.End()
+
+	err := oi.refundRepo.FinishRefund(ctx, event.RefundID, domain.RefundFailed, event.Reason)
+	if errors.Is(err, domain.ErrRefundNotFound) {
+		log.Info("refund already finished")
+		return nil
+	}
+	if err != nil {
+		log.Error("failed to mark refund failed", sl.Err(err))
+		span.RecordError(err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package watch
+
+import (
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// bufferSize is how many changes a subscriber may have pending before it is
+// considered lagging and dropped.
+const bufferSize = 16
+
+// Hub fans order status changes out to the subscribers of each order.
+type Hub struct {
+	mu     sync.Mutex
+	subs   map[uuid.UUID]map[*subscription]struct{}
+	closed bool
+}
+
+type subscription struct {
+	ch chan events.OrderStatusChanged
+}
+
+func NewHub() *Hub {
+	return &Hub{subs: make(map[uuid.UUID]map[*subscription]struct{})}
+}
+
+func (h *Hub) Watch(orderID uuid.UUID) (<-chan events.OrderStatusChanged, func()) {
+	sub := &subscription{ch: make(chan events.OrderStatusChanged, bufferSize)}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(sub.ch)
+		return sub.ch, func() {}
+	}
+	if h.subs[orderID] == nil {
+		h.subs[orderID] = make(map[*subscription]struct{})
+	}
+	h.subs[orderID][sub] = struct{}{}
+	return sub.ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.drop(orderID, sub)
+	}
+}
+
+// Publish hands change to the subscribers of its order without blocking.
+func (h *Hub) Publish(change events.OrderStatusChanged) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs[change.OrderID] {
+		select {
+		case sub.ch <- change:
+		default:
+			h.drop(change.OrderID, sub)
+		}
+	}
+}
+
+// Close ends every subscription and refuses new ones.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for orderID, subs := range h.subs {
+		for sub := range subs {
+			h.drop(orderID, sub)
+		}
+	}
+}
+
+// drop closes sub once; h.mu must be held.
+func (h *Hub) drop(orderID uuid.UUID, sub *subscription) {
+	subs, ok := h.subs[orderID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	close(sub.ch)
+	if len(subs) == 0 {
+		delete(h.subs, orderID)
+	}
+}
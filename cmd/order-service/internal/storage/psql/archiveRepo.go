@@ -0,0 +1,153 @@
+package psql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ArchiveRepository struct {
+	db *gorm.DB
+}
+
+func NewArchiveRepository(db *gorm.DB) *ArchiveRepository {
+	return &ArchiveRepository{db: db}
+}
+
+// archivedPayload is what an archived order keeps of the rows it replaced.
+type archivedPayload struct {
+	Order   domain.Order                `json:"order"`
+	History []domain.OrderStatusHistory `json:"history"`
+	Refunds []domain.Refund             `json:"refunds,omitempty"`
+}
+
+func (r *ArchiveRepository) Archive(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	var archived int64
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var orders []domain.Order
+		// Soft deleted orders stay where RestoreOrder can find them.
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND updated_at < ?", domain.StatusCompleted, cutoff).
+			// A pending refund still has to be finished on the live order.
+			Where("NOT EXISTS (SELECT 1 FROM order_refunds WHERE order_refunds.order_id = orders.id AND order_refunds.status = ?)", domain.RefundPending).
+			Order("updated_at").
+			Limit(limit).
+			Find(&orders).Error; err != nil {
+			return err
+		}
+		if len(orders) == 0 {
+			return nil
+		}
+
+		ids := make([]uuid.UUID, len(orders))
+		for i, order := range orders {
+			ids[i] = order.ID
+		}
+		var items []domain.OrderItem
+		if err := tx.Preload("Modifiers").Where("order_id IN ?", ids).Find(&items).Error; err != nil {
+			return err
+		}
+		var history []domain.OrderStatusHistory
+		if err := tx.Where("order_id IN ?", ids).Order("changed_at, id").Find(&history).Error; err != nil {
+			return err
+		}
+		var refunds []domain.Refund
+		if err := tx.Preload("Items").Where("order_id IN ?", ids).Order("created_at, id").Find(&refunds).Error; err != nil {
+			return err
+		}
+		itemsByOrder := make(map[uuid.UUID][]domain.OrderItem, len(orders))
+		for _, item := range items {
+			itemsByOrder[item.OrderID] = append(itemsByOrder[item.OrderID], item)
+		}
+		historyByOrder := make(map[uuid.UUID][]domain.OrderStatusHistory, len(orders))
+		for _, entry := range history {
+			historyByOrder[entry.OrderID] = append(historyByOrder[entry.OrderID], entry)
+		}
+
+		refundsByOrder := make(map[uuid.UUID][]domain.Refund, len(orders))
+		for _, refund := range refunds {
+			refundsByOrder[refund.OrderID] = append(refundsByOrder[refund.OrderID], refund)
+		}
+
+		rows := make([]domain.ArchivedOrder, len(orders))
+		for i, order := range orders {
+			order.Items = itemsByOrder[order.ID]
+			payload, err := json.Marshal(archivedPayload{
+				Order:   order,
+				History: historyByOrder[order.ID],
+				Refunds: refundsByOrder[order.ID],
+			})
+			if err != nil {
+				return err
+			}
+			rows[i] = domain.ArchivedOrder{
+				OrderID:   order.ID,
+				UserID:    order.UserID,
+				Status:    order.Status,
+				Total:     order.Total,
+				CreatedAt: order.CreatedAt,
+				Payload:   string(payload),
+			}
+		}
+		if err := tx.Create(&rows).Error; err != nil {
+			return err
+		}
+		// Items, history and refunds go with the order through ON DELETE CASCADE.
+		result := tx.Unscoped().Where("id IN ?", ids).Delete(&domain.Order{})
+		if result.Error != nil {
+			return result.Error
+		}
+		archived = result.RowsAffected
+		return nil
+	})
+	return archived, err
+}
+
+func (r *ArchiveRepository) Restore(ctx context.Context, orderID uuid.UUID) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var row domain.ArchivedOrder
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("order_id = ?", orderID).
+			First(&row).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrOrderNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		var payload archivedPayload
+		if err := json.Unmarshal([]byte(row.Payload), &payload); err != nil {
+			return err
+		}
+		// The restored order is live again: it is not soft deleted, and it is
+		// only archived again once it goes unchanged for the archive period.
+		payload.Order.DeletedAt = gorm.DeletedAt{}
+		payload.Order.UpdatedAt = time.Now()
+		if err := tx.Omit("Items").Create(&payload.Order).Error; err != nil {
+			return err
+		}
+		if len(payload.Order.Items) > 0 {
+			if err := tx.Create(&payload.Order.Items).Error; err != nil {
+				return err
+			}
+		}
+		if len(payload.History) > 0 {
+			if err := tx.Create(&payload.History).Error; err != nil {
+				return err
+			}
+		}
+		for i := range payload.Refunds {
+			if err := tx.Create(&payload.Refunds[i]).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&row).Error
+	})
+}
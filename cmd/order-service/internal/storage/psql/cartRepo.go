@@ -0,0 +1,106 @@
+package psql
+
+import (
+	"context"
+	"errors"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CartRepository struct {
+	db *gorm.DB
+}
+
+func NewCartRepository(db *gorm.DB) *CartRepository {
+	return &CartRepository{db: db}
+}
+
+// CreateCart leaves cart unsaved when the user already has a cart, so two
+// concurrent first adds end up in the same one.
+func (r *CartRepository) CreateCart(ctx context.Context, cart *domain.Cart) error {
+	return conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(cart).Error
+}
+
+func (r *CartRepository) Cart(ctx context.Context, cartID uuid.UUID) (domain.Cart, error) {
+	return r.cart(ctx, "id = ?", cartID)
+}
+
+func (r *CartRepository) CartByUser(ctx context.Context, userID uuid.UUID) (domain.Cart, error) {
+	return r.cart(ctx, "user_id = ?", userID)
+}
+
+func (r *CartRepository) cart(ctx context.Context, query string, args ...interface{}) (domain.Cart, error) {
+	var cart domain.Cart
+	err := conn(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(query, args...).
+		First(&cart).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Cart{}, domain.ErrCartNotFound
+	}
+	if err != nil {
+		return domain.Cart{}, err
+	}
+	err = conn(ctx, r.db).
+		Where("cart_id = ?", cart.ID).
+		Order("created_at, id").
+		Find(&cart.Items).Error
+	return cart, err
+}
+
+func (r *CartRepository) AddItem(ctx context.Context, cartID, productID uuid.UUID, modifierKey string, quantity int) error {
+	return conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cart_id"}, {Name: "product_id"}, {Name: "modifier_key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"quantity": gorm.Expr("cart_items.quantity + excluded.quantity")}),
+	}).Create(&domain.CartItem{CartID: cartID, ProductID: productID, ModifierKey: modifierKey, Quantity: quantity}).Error
+}
+
+func (r *CartRepository) SetItem(ctx context.Context, cartID, productID uuid.UUID, modifierKey string, quantity int) error {
+	return conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cart_id"}, {Name: "product_id"}, {Name: "modifier_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"quantity"}),
+	}).Create(&domain.CartItem{CartID: cartID, ProductID: productID, ModifierKey: modifierKey, Quantity: quantity}).Error
+}
+
+func (r *CartRepository) RemoveItem(ctx context.Context, cartID, productID uuid.UUID, modifierKey string) error {
+	return conn(ctx, r.db).
+		Where("cart_id = ? AND product_id = ? AND modifier_key = ?", cartID, productID, modifierKey).
+		Delete(&domain.CartItem{}).Error
+}
+
+func (r *CartRepository) ClearItems(ctx context.Context, cartID uuid.UUID) error {
+	return conn(ctx, r.db).Where("cart_id = ?", cartID).Delete(&domain.CartItem{}).Error
+}
+
+func (r *CartRepository) UpdateCart(ctx context.Context, cart *domain.Cart) error {
+	return conn(ctx, r.db).Model(&domain.Cart{}).
+		Where("id = ?", cart.ID).
+		Updates(map[string]interface{}{
+			"checkout_key":      cart.CheckoutKey,
+			"checkout_order_id": cart.CheckoutOrderID,
+			"expires_at":        cart.ExpiresAt,
+			"updated_at":        time.Now(),
+		}).Error
+}
+
+func (r *CartRepository) DeleteCart(ctx context.Context, cartID uuid.UUID) error {
+	return conn(ctx, r.db).Where("id = ?", cartID).Delete(&domain.Cart{}).Error
+}
+
+func (r *CartRepository) DeleteExpired(ctx context.Context, now time.Time, limit int) (int64, error) {
+	result := conn(ctx, r.db).Exec(`
+		DELETE FROM carts
+		WHERE id IN (
+			SELECT id FROM carts
+			WHERE expires_at <= ?
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)`,
+		now, limit,
+	)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,14 @@
+package psql
+
+import (
+	"context"
+	"immxrtalbeast/order_microservices/internal/pkg/outbox"
+
+	"gorm.io/gorm"
+)
+
+// conn returns the transaction ctx carries from outbox.Transactor, so the
+// repositories join it, or db outside of one.
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	return outbox.Conn(ctx, db)
+}
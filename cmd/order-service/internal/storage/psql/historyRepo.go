@@ -0,0 +1,30 @@
+package psql
+
+import (
+	"context"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type StatusHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewStatusHistoryRepository(db *gorm.DB) *StatusHistoryRepository {
+	return &StatusHistoryRepository{db: db}
+}
+
+func (r *StatusHistoryRepository) Append(ctx context.Context, entry *domain.OrderStatusHistory) error {
+	return conn(ctx, r.db).Create(entry).Error
+}
+
+func (r *StatusHistoryRepository) History(ctx context.Context, orderID uuid.UUID) ([]domain.OrderStatusHistory, error) {
+	var entries []domain.OrderStatusHistory
+	err := conn(ctx, r.db).
+		Where("order_id = ?", orderID).
+		Order("changed_at, id").
+		Find(&entries).Error
+	return entries, err
+}
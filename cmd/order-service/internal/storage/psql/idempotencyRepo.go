@@ -0,0 +1,55 @@
+package psql
+
+import (
+	"context"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+func (r *IdempotencyRepository) Claim(ctx context.Context, key *domain.IdempotencyKey) (domain.IdempotencyKey, bool, error) {
+	db := conn(ctx, r.db)
+	if err := db.
+		Where("user_id = ? AND key = ? AND expires_at <= ?", key.UserID, key.Key, time.Now()).
+		Delete(&domain.IdempotencyKey{}).Error; err != nil {
+		return domain.IdempotencyKey{}, false, err
+	}
+	// A concurrent claim of the same key blocks here until its transaction
+	// ends and then conflicts, so only one request creates the order.
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(key)
+	if result.Error != nil {
+		return domain.IdempotencyKey{}, false, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return *key, true, nil
+	}
+	var stored domain.IdempotencyKey
+	if err := db.Where("user_id = ? AND key = ?", key.UserID, key.Key).First(&stored).Error; err != nil {
+		return domain.IdempotencyKey{}, false, err
+	}
+	return stored, false, nil
+}
+
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time, limit int) (int64, error) {
+	result := conn(ctx, r.db).Exec(`
+		DELETE FROM idempotency_keys
+		WHERE (user_id, key) IN (
+			SELECT user_id, key FROM idempotency_keys
+			WHERE expires_at <= ?
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)`,
+		now, limit,
+	)
+	return result.RowsAffected, result.Error
+}
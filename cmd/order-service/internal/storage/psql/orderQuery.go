@@ -0,0 +1,85 @@
+package psql
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var sortColumns = map[domain.OrderSortField]string{
+	domain.SortByCreatedAt: "created_at",
+	domain.SortByTotal:     "total_amount",
+}
+
+func filterOrders(db *gorm.DB, filter domain.OrderFilter) *gorm.DB {
+	if filter.IncludeDeleted {
+		db = db.Unscoped()
+	}
+	if filter.UserID != uuid.Nil {
+		db = db.Where("user_id = ?", filter.UserID)
+	}
+	if len(filter.Statuses) > 0 {
+		db = db.Where("status IN ?", filter.Statuses)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		db = db.Where("created_at >= ?", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		db = db.Where("created_at < ?", filter.CreatedTo)
+	}
+	if filter.ProductID != uuid.Nil {
+		db = db.Where("EXISTS (SELECT 1 FROM order_items oi WHERE oi.order_id = orders.id AND oi.product_id = ?)", filter.ProductID)
+	}
+	if !filter.MinTotal.IsZero() {
+		db = db.Where("total_currency = ? AND total_amount >= ?", filter.MinTotal.Currency, filter.MinTotal.Amount)
+	}
+	return db
+}
+
+// orderCursor is the sort key of the last order of a page. It carries the
+// sort it was made for so a token is not reused with another one.
+type orderCursor struct {
+	Sort      domain.OrderSort `json:"s"`
+	CreatedAt time.Time        `json:"c,omitzero"`
+	Total     int64            `json:"t,omitempty"`
+	ID        uuid.UUID        `json:"id"`
+}
+
+func newCursor(sort domain.OrderSort, last domain.Order) orderCursor {
+	cursor := orderCursor{Sort: sort, ID: last.ID}
+	switch sort.Field {
+	case domain.SortByTotal:
+		cursor.Total = last.Total.Amount
+	default:
+		cursor.CreatedAt = last.CreatedAt
+	}
+	return cursor
+}
+
+func (c orderCursor) key(field domain.OrderSortField) any {
+	if field == domain.SortByTotal {
+		return c.Total
+	}
+	return c.CreatedAt
+}
+
+func encodeCursor(cursor orderCursor) string {
+	b, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(token string, sort domain.OrderSort) (orderCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return orderCursor{}, domain.ErrInvalidPageToken
+	}
+	var cursor orderCursor
+	if err := json.Unmarshal(b, &cursor); err != nil || cursor.Sort != sort || cursor.ID == uuid.Nil {
+		return orderCursor{}, domain.ErrInvalidPageToken
+	}
+	return cursor, nil
+}
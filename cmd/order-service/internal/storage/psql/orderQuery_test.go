@@ -0,0 +1,76 @@
+package psql
+
+import (
+	"encoding/base64"
+	"errors"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/domain"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestOrderCursorRoundTrip(t *testing.T) {
+	last := domain.Order{
+		ID:        uuid.New(),
+		CreatedAt: time.Date(2026, 10, 18, 9, 30, 15, 123456789, time.UTC),
+		Total:     money.New(129990, "RUB"),
+	}
+	tests := []struct {
+		name    string
+		sort    domain.OrderSort
+		wantKey any
+	}{
+		{name: "newest first", sort: domain.OrderSort{Field: domain.SortByCreatedAt}, wantKey: last.CreatedAt},
+		{name: "oldest first", sort: domain.OrderSort{Field: domain.SortByCreatedAt, Ascending: true}, wantKey: last.CreatedAt},
+		{name: "by total", sort: domain.OrderSort{Field: domain.SortByTotal}, wantKey: int64(129990)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := decodeCursor(encodeCursor(newCursor(tt.sort, last)), tt.sort)
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if cursor.ID != last.ID {
+				t.Errorf("id = %s, want %s", cursor.ID, last.ID)
+			}
+			switch want := tt.wantKey.(type) {
+			case time.Time:
+				// Sub-second precision must survive, or a page ending inside
+				// a second would skip or repeat orders.
+				if got, _ := cursor.key(tt.sort.Field).(time.Time); !got.Equal(want) {
+					t.Errorf("key = %v, want %v", got, want)
+				}
+			default:
+				if got := cursor.key(tt.sort.Field); got != want {
+					t.Errorf("key = %v, want %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejects(t *testing.T) {
+	byDate := domain.OrderSort{Field: domain.SortByCreatedAt}
+	last := domain.Order{ID: uuid.New(), CreatedAt: time.Now()}
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "not base64", token: "!!!"},
+		{name: "not json", token: encode("created_at")},
+		{name: "no order id", token: encode(`{"s":{"Field":"created_at","Ascending":false},"c":"2026-10-18T09:30:15Z"}`)},
+		{name: "made for another sort field", token: encodeCursor(newCursor(domain.OrderSort{Field: domain.SortByTotal}, last))},
+		{name: "made for the other direction", token: encodeCursor(newCursor(domain.OrderSort{Field: domain.SortByCreatedAt, Ascending: true}, last))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeCursor(tt.token, byDate); !errors.Is(err, domain.ErrInvalidPageToken) {
+				t.Errorf("err = %v, want %v", err, domain.ErrInvalidPageToken)
+			}
+		})
+	}
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"immxrtalbeast/order_microservices/cmd/order-service/internal/domain"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OrderRepository struct {
@@ -24,33 +27,23 @@ func (r *OrderRepository) SaveOrder(ctx context.Context, order *domain.Order) (u
 		return uuid.Nil, errors.New("order items cannot be empty")
 	}
 
-	tx := r.db.WithContext(ctx).Begin()
-	if tx.Error != nil {
-		return uuid.Nil, tx.Error
-	}
-
-	// 1. Сохраняем основной заказ
-	if err := tx.Omit("Items").Create(order).Error; err != nil {
-		tx.Rollback()
-		return uuid.Nil, err
-	}
-
-	// 2. Подготовка элементов
-	for i := range order.Items {
-		order.Items[i].OrderID = order.ID // Устанавливаем связь
-		order.Items[i].ID = uuid.Nil      // Сбрасываем ID для генерации нового
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// 1. Сохраняем основной заказ
+		if err := tx.Omit("Items").Create(order).Error; err != nil {
+			return err
+		}
 
-	}
+		// 2. Подготовка элементов
+		for i := range order.Items {
+			order.Items[i].OrderID = order.ID // Устанавливаем связь
+			order.Items[i].ID = uuid.Nil      // Сбрасываем ID для генерации нового
 
-	// 3. Массовое сохранение элементов
-	if err := tx.Create(&order.Items).Error; err != nil {
-		tx.Rollback()
-		return uuid.Nil, err
-	}
+		}
 
-	// 4. Фиксация транзакции
-	if err := tx.Commit().Error; err != nil {
-		tx.Rollback()
+		// 3. Массовое сохранение элементов
+		return tx.Create(&order.Items).Error
+	})
+	if err != nil {
 		return uuid.Nil, err
 	}
 
@@ -58,64 +51,204 @@ func (r *OrderRepository) SaveOrder(ctx context.Context, order *domain.Order) (u
 }
 
 func (r *OrderRepository) DeleteOrder(ctx context.Context, orderID uuid.UUID) error {
-	return r.db.WithContext(ctx).Where("id = ?", orderID).Delete(&domain.Order{}).Error
+	result := conn(ctx, r.db).Where("id = ?", orderID).Delete(&domain.Order{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrOrderNotFound
+	}
+	return nil
+}
+
+func (r *OrderRepository) RestoreOrder(ctx context.Context, orderID uuid.UUID) error {
+	result := conn(ctx, r.db).Unscoped().Model(&domain.Order{}).
+		Where("id = ? AND deleted_at IS NOT NULL", orderID).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrOrderNotFound
+	}
+	return nil
 }
 
 func (r *OrderRepository) GetOrder(ctx context.Context, orderID uuid.UUID) (domain.Order, error) {
 	var order domain.Order
 
-	result := r.db.WithContext(ctx).Preload("Items").Where("id = ?", orderID).First(&order)
+	result := conn(ctx, r.db).Preload("Items.Modifiers").Where("id = ?", orderID).First(&order)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return domain.Order{}, domain.ErrOrderNotFound
+		}
+		return domain.Order{}, fmt.Errorf("database error: %w", result.Error)
+	}
+
+	return order, nil
+}
+
+func (r *OrderRepository) LockOrder(ctx context.Context, orderID uuid.UUID) (domain.Order, error) {
+	var order domain.Order
 
+	result := conn(ctx, r.db).Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", orderID).First(&order)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return domain.Order{}, fmt.Errorf("order not found")
+			return domain.Order{}, domain.ErrOrderNotFound
 		}
 		return domain.Order{}, fmt.Errorf("database error: %w", result.Error)
 	}
+	if err := conn(ctx, r.db).Where("order_id = ?", orderID).Find(&order.Items).Error; err != nil {
+		return domain.Order{}, fmt.Errorf("database error: %w", err)
+	}
 
 	return order, nil
 }
 
-func (r *OrderRepository) UpdateOrderStatus(ctx context.Context, orderID uuid.UUID, status string) error {
-	result := r.db.WithContext(ctx).Model(&domain.Order{}).Where("id = ?", orderID).Update("status", status)
+func (r *OrderRepository) UpdateOrderStatus(ctx context.Context, orderID uuid.UUID, from, to domain.OrderStatus) error {
+	result := conn(ctx, r.db).Model(&domain.Order{}).
+		Where("id = ? AND status = ?", orderID, from).
+		Update("status", to)
 	if result.Error != nil {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("order not found")
+		return domain.ErrIllegalTransition
 	}
 	return nil
 }
 
-func (r *OrderRepository) ListOrdersByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.Order, error) {
+// ListOrders returns a page of orders using keyset pagination on the sort
+// column and the order ID.
+func (r *OrderRepository) ListOrders(ctx context.Context, query domain.OrderQuery) (domain.OrderPage, error) {
+	sort := query.Sort
+	if sort.Field == "" {
+		sort.Field = domain.SortByCreatedAt
+	}
+	column, ok := sortColumns[sort.Field]
+	if !ok {
+		return domain.OrderPage{}, fmt.Errorf("unknown sort field %q", sort.Field)
+	}
+	direction, cmp := "DESC", "<"
+	if sort.Ascending {
+		direction, cmp = "ASC", ">"
+	}
+
+	var page domain.OrderPage
+	if query.WithTotalCount {
+		var count int64
+		if err := filterOrders(conn(ctx, r.db).Model(&domain.Order{}), query.Filter).Count(&count).Error; err != nil {
+			return domain.OrderPage{}, err
+		}
+		page.TotalCount = &count
+	}
+
+	db := filterOrders(conn(ctx, r.db).Preload("Items.Modifiers"), query.Filter)
+	if query.PageToken != "" {
+		cursor, err := decodeCursor(query.PageToken, sort)
+		if err != nil {
+			return domain.OrderPage{}, err
+		}
+		db = db.Where(fmt.Sprintf("(%s, id) %s (?, ?)", column, cmp), cursor.key(sort.Field), cursor.ID)
+	} else if query.Offset > 0 {
+		db = db.Offset(query.Offset)
+	}
+
 	var orders []domain.Order
-	err := r.db.WithContext(ctx).
-		Preload("Items").
-		Where("user_id = ?", userID).
-		Limit(limit).
-		Offset(offset).
+	err := db.
+		Order(fmt.Sprintf("%s %s, id %s", column, direction, direction)).
+		Limit(query.Limit + 1).
 		Find(&orders).Error
-	return orders, err
+	if err != nil {
+		return domain.OrderPage{}, err
+	}
+	if len(orders) > query.Limit {
+		orders = orders[:query.Limit]
+		page.NextPageToken = encodeCursor(newCursor(sort, orders[len(orders)-1]))
+	}
+	page.Orders = orders
+	return page, nil
 }
 
-func (r *OrderRepository) ListOrders(ctx context.Context, limit, offset int) ([]domain.Order, error) {
-	var orders []domain.Order
-	err := r.db.WithContext(ctx).
-		Preload("Items").
-		Order("created_at DESC").
-		Limit(limit).
-		Offset(offset).
-		Find(&orders).Error
-	return orders, err
+func (r *OrderRepository) SetTotal(ctx context.Context, orderID uuid.UUID, total money.Money) error {
+	result := conn(ctx, r.db).Model(&domain.Order{}).Where("id = ?", orderID).
+		Updates(map[string]interface{}{
+			"total_amount":   total.Amount,
+			"total_currency": total.Currency,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrOrderNotFound
+	}
+	return nil
+}
+
+func (r *OrderRepository) MarkPaid(ctx context.Context, orderID uuid.UUID, paidAt time.Time) error {
+	return conn(ctx, r.db).Model(&domain.Order{}).
+		Where("id = ? AND paid_at IS NULL", orderID).
+		Update("paid_at", paidAt).Error
 }
 
-func (r *OrderRepository) SetTotalSum(ctx context.Context, orderID uuid.UUID, sum int) error {
-	result := r.db.WithContext(ctx).Model(&domain.Order{}).Where("id = ?", orderID).Update("total", sum)
+func (r *OrderRepository) SetRefunded(ctx context.Context, orderID uuid.UUID, total, refunded money.Money) error {
+	result := conn(ctx, r.db).Model(&domain.Order{}).Where("id = ?", orderID).
+		Updates(map[string]interface{}{
+			"total_amount":      total.Amount,
+			"total_currency":    total.Currency,
+			"refunded_amount":   refunded.Amount,
+			"refunded_currency": refunded.Currency,
+		})
 	if result.Error != nil {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("order not found")
+		return domain.ErrOrderNotFound
+	}
+	return nil
+}
+
+func (r *OrderRepository) SnapshotItems(ctx context.Context, orderID uuid.UUID, items []domain.OrderItem) error {
+	var lines []domain.OrderItem
+	if err := conn(ctx, r.db).Preload("Modifiers").Where("order_id = ?", orderID).Find(&lines).Error; err != nil {
+		return err
+	}
+	linesByKey := make(map[string][]domain.OrderItem, len(lines))
+	for _, line := range lines {
+		linesByKey[line.LineKey()] = append(linesByKey[line.LineKey()], line)
+	}
+
+	for _, item := range items {
+		for _, line := range linesByKey[item.LineKey()] {
+			lineTotal := item.UnitPrice.Mul(line.Quantity)
+			err := conn(ctx, r.db).Model(&domain.OrderItem{}).
+				Where("id = ?", line.ID).
+				Updates(map[string]interface{}{
+					"name":                item.Name,
+					"volume":              item.Volume,
+					"unit_price_amount":   item.UnitPrice.Amount,
+					"unit_price_currency": item.UnitPrice.Currency,
+					"line_total_amount":   lineTotal.Amount,
+					"line_total_currency": lineTotal.Currency,
+				}).Error
+			if err != nil {
+				return err
+			}
+			for _, modifier := range item.Modifiers {
+				err := conn(ctx, r.db).Model(&domain.OrderItemModifier{}).
+					Where("order_item_id = ? AND modifier_id = ?", line.ID, modifier.ModifierID).
+					Updates(map[string]interface{}{
+						"name":                 modifier.Name,
+						"price_delta_amount":   modifier.PriceDelta.Amount,
+						"price_delta_currency": modifier.PriceDelta.Currency,
+					}).Error
+				if err != nil {
+					return err
+				}
+			}
+		}
 	}
 	return nil
 }
@@ -0,0 +1,65 @@
+package psql
+
+import (
+	"context"
+	"errors"
+	"immxrtalbeast/order_microservices/cmd/order-service/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RefundRepository struct {
+	db *gorm.DB
+}
+
+func NewRefundRepository(db *gorm.DB) *RefundRepository {
+	return &RefundRepository{db: db}
+}
+
+func (r *RefundRepository) SaveRefund(ctx context.Context, refund *domain.Refund) error {
+	return conn(ctx, r.db).Create(refund).Error
+}
+
+func (r *RefundRepository) Refund(ctx context.Context, refundID uuid.UUID) (domain.Refund, error) {
+	var refund domain.Refund
+	err := conn(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", refundID).
+		First(&refund).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Refund{}, domain.ErrRefundNotFound
+	}
+	if err != nil {
+		return domain.Refund{}, err
+	}
+	err = conn(ctx, r.db).Where("refund_id = ?", refundID).Find(&refund.Items).Error
+	return refund, err
+}
+
+func (r *RefundRepository) Refunds(ctx context.Context, orderID uuid.UUID) ([]domain.Refund, error) {
+	var refunds []domain.Refund
+	err := conn(ctx, r.db).
+		Preload("Items").
+		Where("order_id = ?", orderID).
+		Order("created_at, id").
+		Find(&refunds).Error
+	return refunds, err
+}
+
+func (r *RefundRepository) FinishRefund(ctx context.Context, refundID uuid.UUID, status domain.RefundStatus, failureReason string) error {
+	result := conn(ctx, r.db).Model(&domain.Refund{}).
+		Where("id = ? AND status = ?", refundID, domain.RefundPending).
+		Updates(map[string]interface{}{
+			"status":         status,
+			"failure_reason": failureReason,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrRefundNotFound
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+module immxrtalbeast/order_microservices/payment-service
+
+go 1.24.5
+
+require (
+	github.com/fatih/color v1.18.0
+	github.com/google/uuid v1.6.0
+	github.com/ilyakaznacheev/cleanenv v1.5.0
+	github.com/joho/godotenv v1.5.1
+	github.com/segmentio/kafka-go v0.4.51
+	go.opentelemetry.io/otel v1.38.0
+	go.opentelemetry.io/otel/exporters/jaeger v1.17.0
+	go.opentelemetry.io/otel/sdk v1.38.0
+	gorm.io/driver/postgres v1.6.0
+	gorm.io/gorm v1.31.0
+	immxrtalbeast/order_microservices/internal/pkg/events v0.0.0-00010101000000-000000000000
+	immxrtalbeast/order_microservices/internal/pkg/kafka v0.0.0-00010101000000-000000000000
+	immxrtalbeast/order_microservices/internal/pkg/money v0.0.0-00010101000000-000000000000
+	immxrtalbeast/order_microservices/internal/pkg/outbox v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/BurntSushi/toml v1.2.1 // indirect
+	github.com/go-logr/logr v1.4.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
+	github.com/jackc/pgx/v5 v5.6.0 // indirect
+	github.com/jackc/puddle/v2 v2.2.2 // indirect
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+	github.com/klauspost/compress v1.15.9 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/pierrec/lz4/v4 v4.1.15 // indirect
+	github.com/rogpeppe/go-internal v1.14.1 // indirect
+	go.opentelemetry.io/auto/sdk v1.1.0 // indirect
+	go.opentelemetry.io/otel/metric v1.38.0 // indirect
+	go.opentelemetry.io/otel/trace v1.38.0 // indirect
+	golang.org/x/crypto v0.42.0 // indirect
+	golang.org/x/sync v0.17.0 // indirect
+	golang.org/x/sys v0.36.0 // indirect
+	golang.org/x/text v0.29.0 // indirect
+	google.golang.org/protobuf v1.36.9 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+	olympos.io/encoding/edn v0.0.0-20201019073823-d3554ca0b0a3 // indirect
+)
+
+replace immxrtalbeast/order_microservices/internal/pkg/kafka => ../../internal/pkg/kafka
+
+replace immxrtalbeast/order_microservices/internal/pkg/events => ../../internal/pkg/events
+
+replace immxrtalbeast/order_microservices/internal/pkg/money => ../../internal/pkg/money
+
+replace immxrtalbeast/order_microservices/internal/pkg/outbox => ../../internal/pkg/outbox
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	mykafka "immxrtalbeast/order_microservices/internal/pkg/kafka"
+	"immxrtalbeast/order_microservices/payment-service/internal/domain"
+	"log/slog"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func NewPaymentEventsHandler(paymentInteractor domain.PaymentInteractor, inbox *Inbox, log *slog.Logger) mykafka.Handler {
+	propagator := propagation.TraceContext{}
+	return func(ctx context.Context, msg kafka.Message) error {
+		log.Info("EventReceived")
+		env, err := events.Unmarshal(msg.Value, mykafka.Header(msg, mykafka.HeaderContentType), mykafka.Header(msg, mykafka.HeaderEventType))
+		if err != nil {
+			return mykafka.Permanent(err)
+		}
+		event, err := env.Decode()
+		if errors.Is(err, events.ErrUnknownType) {
+			return nil
+		}
+		if err != nil {
+			return mykafka.Permanent(err)
+		}
+
+		carrier := propagation.MapCarrier{}
+		for _, header := range msg.Headers {
+			carrier[header.Key] = string(header.Value)
+		}
+		ctx = propagator.Extract(ctx, carrier)
+		ctx = events.WithCorrelationID(ctx, env.CorrelationID)
+		handle := func(fn func(ctx context.Context) error) error {
+			return inbox.Handle(ctx, log, msg, env.Type, fn)
+		}
+		switch e := event.(type) {
+		case events.AuthorizePayment:
+			log.Info("authorize payment command received", "command", e)
+			return handle(func(ctx context.Context) error {
+				return paymentInteractor.AuthorizePayment(ctx, e)
+			})
+
+		case events.CapturePayment:
+			log.Info("capture payment command received", "command", e)
+			return handle(func(ctx context.Context) error {
+				return paymentInteractor.CapturePayment(ctx, e)
+			})
+
+		case events.VoidPayment:
+			log.Info("void payment command received", "command", e)
+			return handle(func(ctx context.Context) error {
+				return paymentInteractor.VoidPayment(ctx, e)
+			})
+
+		case events.RefundPayment:
+			log.Info("refund payment command received", "command", e)
+			return handle(func(ctx context.Context) error {
+				return paymentInteractor.RefundPayment(ctx, e)
+			})
+
+		default:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"flag"
+	kafka "immxrtalbeast/order_microservices/internal/pkg/kafka"
+	"os"
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
+
+type Config struct {
+	Env      string         `yaml:"env" env-default:"local"`
+	Jaeger   Client         `yaml:"jaeger"`
+	Payment  PaymentConfig  `yaml:"payment"`
+	Outbox   OutboxConfig   `yaml:"outbox"`
+	Consumer ConsumerConfig `yaml:"consumer"`
+	Kafka    KafkaConfig    `yaml:"kafka"`
+}
+
+type KafkaConfig struct {
+	Partitions        int `yaml:"partitions" env-default:"6"`
+	ReplicationFactor int `yaml:"replication_factor" env-default:"1"`
+	// ContentType is the encoding of published events, application/x-protobuf
+	// or application/json. Consumers accept both.
+	ContentType string `yaml:"content_type" env-default:"application/x-protobuf"`
+}
+type Client struct {
+	Address string `yaml:"address"`
+}
+
+type PaymentConfig struct {
+	// Provider names the payment provider payments go through. Only "fake" is
+	// available so far.
+	Provider string `yaml:"provider" env-default:"fake"`
+}
+
+type OutboxConfig struct {
+	PollInterval time.Duration `yaml:"poll_interval" env-default:"500ms"`
+	BatchSize    int           `yaml:"batch_size" env-default:"100"`
+}
+
+type ConsumerConfig struct {
+	Workers         int                          `yaml:"workers" env-default:"8"`
+	QueueSize       int                          `yaml:"queue_size" env-default:"64"`
+	HandlerTimeout  time.Duration                `yaml:"handler_timeout" env-default:"30s"`
+	ShutdownTimeout time.Duration                `yaml:"shutdown_timeout" env-default:"20s"`
+	Retry           kafka.RetryPolicy            `yaml:"retry"`
+	EventRetry      map[string]kafka.RetryPolicy `yaml:"event_retry"`
+}
+
+func (c ConsumerConfig) PoolConfig(deadLetters *kafka.DeadLetterQueue) kafka.PoolConfig {
+	return kafka.PoolConfig{
+		Workers:        c.Workers,
+		QueueSize:      c.QueueSize,
+		HandlerTimeout: c.HandlerTimeout,
+		Retry:          c.Retry,
+		EventRetry:     c.EventRetry,
+		DeadLetters:    deadLetters,
+	}
+}
+
+func MustLoad() *Config {
+	configPath := fetchConfigPath()
+	if configPath == "" {
+		panic("config path is empty")
+	}
+
+	return MustLoadPath(configPath)
+}
+
+func MustLoadPath(configPath string) *Config {
+	// check if file exists
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		panic("config file does not exist: " + configPath)
+	}
+
+	var cfg Config
+
+	if err := cleanenv.ReadConfig(configPath, &cfg); err != nil {
+		panic("cannot read config: " + err.Error())
+	}
+
+	return &cfg
+}
+
+func fetchConfigPath() string {
+	var res string
+
+	flag.StringVar(&res, "config", "", "path to config file")
+	flag.Parse()
+
+	if res == "" {
+		res = os.Getenv("CONFIG_PATH")
+	}
+
+	return res
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrPaymentNotFound  = errors.New("payment not found")
+	ErrPaymentDeclined  = errors.New("payment declined")
+	ErrRefundNotFound   = errors.New("refund not found")
+	ErrRefundTooLarge   = errors.New("refund exceeds the captured amount not refunded yet")
+	ErrPaymentNotActive = errors.New("payment is not in a state that allows this operation")
+)
+
+type PaymentStatus string
+
+const (
+	PaymentAuthorized PaymentStatus = "AUTHORIZED"
+	PaymentDeclined   PaymentStatus = "DECLINED"
+	PaymentCaptured   PaymentStatus = "CAPTURED"
+	PaymentVoided     PaymentStatus = "VOIDED"
+	// PaymentRefunded is a captured payment refunded in full.
+	PaymentRefunded PaymentStatus = "REFUNDED"
+)
+
+// Payment is the charge of one order. Provider names the payment provider and
+// the references are the IDs the provider knows the charge by.
+type Payment struct {
+	ID               uuid.UUID     `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	OrderID          uuid.UUID     `gorm:"type:uuid;not null;uniqueIndex"`
+	SagaID           uuid.UUID     `gorm:"type:uuid;not null"`
+	UserID           uuid.UUID     `gorm:"type:uuid;not null;index"`
+	Amount           money.Money   `gorm:"embedded;embeddedPrefix:amount_"`
+	Refunded         money.Money   `gorm:"embedded;embeddedPrefix:refunded_"`
+	Status           PaymentStatus `gorm:"type:varchar(20);not null"`
+	Provider         string        `gorm:"not null"`
+	AuthorizationRef string
+	CaptureRef       string
+	FailureReason    string
+	Refunds          []PaymentRefund `gorm:"foreignKey:PaymentID;constraint:OnDelete:CASCADE"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// Refundable is the captured amount not refunded yet.
+func (p *Payment) Refundable() money.Money {
+	if p.Status != PaymentCaptured {
+		return money.New(0, p.Amount.Currency)
+	}
+	left, _ := p.Amount.Sub(p.Refunded)
+	return left
+}
+
+// PaymentRefund is one refund of a captured payment. A saga refunds a
+// payment at most once, so SagaID tells a retried refund from a new one.
+type PaymentRefund struct {
+	ID          uuid.UUID   `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	PaymentID   uuid.UUID   `gorm:"type:uuid;not null;index"`
+	SagaID      uuid.UUID   `gorm:"type:uuid;uniqueIndex"`
+	Amount      money.Money `gorm:"embedded;embeddedPrefix:amount_"`
+	ProviderRef string      `gorm:"not null"`
+	CreatedAt   time.Time
+}
+
+type PaymentRepository interface {
+	SavePayment(ctx context.Context, payment *Payment) error
+	// PaymentByOrderID returns the order's payment, locked until the end of
+	// the transaction in ctx.
+	PaymentByOrderID(ctx context.Context, orderID uuid.UUID) (*Payment, error)
+	UpdatePayment(ctx context.Context, payment *Payment) error
+	SaveRefund(ctx context.Context, refund *PaymentRefund) error
+	// RefundBySagaID returns the refund a saga made, if any.
+	RefundBySagaID(ctx context.Context, sagaID uuid.UUID) (*PaymentRefund, error)
+}
+
+type PaymentInteractor interface {
+	AuthorizePayment(ctx context.Context, command events.AuthorizePayment) error
+	CapturePayment(ctx context.Context, command events.CapturePayment) error
+	VoidPayment(ctx context.Context, command events.VoidPayment) error
+	RefundPayment(ctx context.Context, command events.RefundPayment) error
+}
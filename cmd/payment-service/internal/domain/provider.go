@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
+)
+
+// PaymentProvider moves money through an external payment processor. Every
+// call carries an idempotency key: repeating a call with the same key returns
+// the result of the first one instead of charging again. Refusals by the
+// processor fail with ErrPaymentDeclined, other errors may be retried.
+type PaymentProvider interface {
+	// Name is stored on payments to tell which provider holds them.
+	Name() string
+	// Authorize holds the amount and returns the authorization reference.
+	Authorize(ctx context.Context, request AuthorizeRequest) (string, error)
+	// Capture charges the held amount and returns the capture reference.
+	Capture(ctx context.Context, idempotencyKey, authorizationRef string, amount money.Money) (string, error)
+	// Void releases a hold that was not captured.
+	Void(ctx context.Context, idempotencyKey, authorizationRef string) error
+	// Refund returns part or all of a capture and returns the refund reference.
+	Refund(ctx context.Context, idempotencyKey, captureRef string, amount money.Money) (string, error)
+}
+
+type AuthorizeRequest struct {
+	IdempotencyKey string
+	CustomerID     string
+	Amount         money.Money
+	Description    string
+}
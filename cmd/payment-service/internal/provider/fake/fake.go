@@ -0,0 +1,86 @@
+// Package fake is a PaymentProvider for local runs and tests that talks to no
+// processor. It is deterministic: references are derived from the idempotency
+// key, so a retried call gets the same reference, and the minor units of the
+// amount pick failures, like test card numbers do for real processors.
+package fake
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
+	"immxrtalbeast/order_microservices/payment-service/internal/domain"
+	"strings"
+)
+
+const (
+	// DeclineAuthorization is the minor units ending of amounts whose
+	// authorization is declined, e.g. 100.13 RUB.
+	DeclineAuthorization = 13
+	// DeclineCapture is the minor units ending of amounts whose capture is
+	// declined.
+	DeclineCapture = 14
+)
+
+type Provider struct{}
+
+func New() *Provider {
+	return &Provider{}
+}
+
+func (p *Provider) Name() string {
+	return "fake"
+}
+
+func (p *Provider) Authorize(_ context.Context, request domain.AuthorizeRequest) (string, error) {
+	if err := validate(request.Amount); err != nil {
+		return "", err
+	}
+	if request.Amount.Amount%100 == DeclineAuthorization {
+		return "", fmt.Errorf("%w: insufficient funds", domain.ErrPaymentDeclined)
+	}
+	return reference("auth", request.IdempotencyKey), nil
+}
+
+func (p *Provider) Capture(_ context.Context, idempotencyKey, authorizationRef string, amount money.Money) (string, error) {
+	if err := validate(amount); err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(authorizationRef, "fake_auth_") {
+		return "", fmt.Errorf("%w: unknown authorization %q", domain.ErrPaymentDeclined, authorizationRef)
+	}
+	if amount.Amount%100 == DeclineCapture {
+		return "", fmt.Errorf("%w: authorization expired", domain.ErrPaymentDeclined)
+	}
+	return reference("capture", idempotencyKey), nil
+}
+
+func (p *Provider) Void(_ context.Context, _, authorizationRef string) error {
+	if !strings.HasPrefix(authorizationRef, "fake_auth_") {
+		return fmt.Errorf("%w: unknown authorization %q", domain.ErrPaymentDeclined, authorizationRef)
+	}
+	return nil
+}
+
+func (p *Provider) Refund(_ context.Context, idempotencyKey, captureRef string, amount money.Money) (string, error) {
+	if err := validate(amount); err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(captureRef, "fake_capture_") {
+		return "", fmt.Errorf("%w: unknown capture %q", domain.ErrPaymentDeclined, captureRef)
+	}
+	return reference("refund", idempotencyKey), nil
+}
+
+func validate(amount money.Money) error {
+	if amount.Amount <= 0 {
+		return fmt.Errorf("%w: amount must be positive, got %s", domain.ErrPaymentDeclined, amount)
+	}
+	return nil
+}
+
+func reference(kind, idempotencyKey string) string {
+	sum := sha256.Sum256([]byte(idempotencyKey))
+	return "fake_" + kind + "_" + hex.EncodeToString(sum[:])[:24]
+}
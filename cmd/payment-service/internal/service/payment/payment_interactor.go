@@ -0,0 +1,392 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
+	"immxrtalbeast/order_microservices/payment-service/internal/domain"
+	"immxrtalbeast/order_microservices/payment-service/internal/lib/logger/sl"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const sagaRepliesTopic = "saga-replies"
+
+// PaymentInteractor runs the saga's payment commands against the provider.
+// Commands run in the inbox transaction and may be delivered again after a
+// failure, so every step first looks at the stored payment and provider calls
+// carry idempotency keys derived from the order.
+type PaymentInteractor struct {
+	log         *slog.Logger
+	paymentRepo domain.PaymentRepository
+	outboxRepo  domain.OutboxRepository
+	transactor  domain.Transactor
+	provider    domain.PaymentProvider
+}
+
+func NewPaymentInteractor(paymentRepo domain.PaymentRepository, outboxRepo domain.OutboxRepository, transactor domain.Transactor, provider domain.PaymentProvider, log *slog.Logger) *PaymentInteractor {
+	return &PaymentInteractor{paymentRepo: paymentRepo, outboxRepo: outboxRepo, transactor: transactor, provider: provider, log: log}
+}
+
+func (pi *PaymentInteractor) AuthorizePayment(ctx context.Context, command events.AuthorizePayment) error {
+	const op = "service.payment.authorize"
+	log := pi.log.With(
+		slog.String("op", op),
+		slog.String("order_id", command.OrderID.String()),
+		slog.String("saga_id", command.SagaID.String()),
+		slog.String("amount", command.Amount.String()),
+	)
+	log.Info("authorizing payment")
+	tracer := otel.Tracer("payment-service")
+	ctx, span := tracer.Start(ctx, "PaymentService.AuthorizePayment")
+	span.SetAttributes(
+		attribute.String("saga.id", command.SagaID.String()),
+		attribute.String("order.id", command.OrderID.String()),
+	)
+	defer span.End()
+	err := pi.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		payment, err := pi.paymentRepo.PaymentByOrderID(ctx, command.OrderID)
+		switch {
+		case err == nil:
+			log.Warn("order already has a payment", slog.String("status", string(payment.Status)))
+			return pi.enqueue(ctx, command.OrderID, authorizationReply(payment))
+		case !errors.Is(err, domain.ErrPaymentNotFound):
+			return err
+		}
+
+		now := time.Now()
+		payment = &domain.Payment{
+			ID:        uuid.New(),
+			OrderID:   command.OrderID,
+			SagaID:    command.SagaID,
+			UserID:    command.UserID,
+			Amount:    command.Amount,
+			Refunded:  money.New(0, command.Amount.Currency),
+			Provider:  pi.provider.Name(),
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		ref, err := pi.provider.Authorize(ctx, domain.AuthorizeRequest{
+			IdempotencyKey: "authorize:" + command.OrderID.String(),
+			CustomerID:     command.UserID.String(),
+			Amount:         command.Amount,
+			Description:    "order " + command.OrderID.String(),
+		})
+		switch {
+		case errors.Is(err, domain.ErrPaymentDeclined):
+			span.RecordError(err)
+			log.Warn("payment declined", sl.Err(err))
+			payment.Status = domain.PaymentDeclined
+			payment.FailureReason = err.Error()
+		case err != nil:
+			return err
+		default:
+			payment.Status = domain.PaymentAuthorized
+			payment.AuthorizationRef = ref
+			log.Info("payment authorized", slog.String("payment_id", payment.ID.String()))
+		}
+		if err := pi.paymentRepo.SavePayment(ctx, payment); err != nil {
+			return err
+		}
+		return pi.enqueue(ctx, command.OrderID, authorizationReply(payment))
+	})
+	if err != nil {
+		span.RecordError(err)
+		log.Error("failed to authorize payment", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (pi *PaymentInteractor) CapturePayment(ctx context.Context, command events.CapturePayment) error {
+	const op = "service.payment.capture"
+	log := pi.log.With(
+		slog.String("op", op),
+		slog.String("order_id", command.OrderID.String()),
+		slog.String("saga_id", command.SagaID.String()),
+	)
+	log.Info("capturing payment")
+	tracer := otel.Tracer("payment-service")
+	ctx, span := tracer.Start(ctx, "PaymentService.CapturePayment")
+	span.SetAttributes(
+		attribute.String("saga.id", command.SagaID.String()),
+		attribute.String("order.id", command.OrderID.String()),
+	)
+	defer span.End()
+	err := pi.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		failed := func(reason string) error {
+			log.Warn("payment not captured", slog.String("reason", reason))
+			return pi.enqueue(ctx, command.OrderID, events.PaymentCaptureFailed{
+				OrderID: command.OrderID,
+				SagaID:  command.SagaID,
+				Reason:  reason,
+			})
+		}
+		payment, err := pi.paymentRepo.PaymentByOrderID(ctx, command.OrderID)
+		if errors.Is(err, domain.ErrPaymentNotFound) {
+			return failed(err.Error())
+		}
+		if err != nil {
+			return err
+		}
+
+		switch payment.Status {
+		case domain.PaymentCaptured, domain.PaymentRefunded:
+			log.Warn("payment already captured")
+		case domain.PaymentAuthorized:
+			ref, err := pi.provider.Capture(ctx, "capture:"+command.OrderID.String(), payment.AuthorizationRef, payment.Amount)
+			if errors.Is(err, domain.ErrPaymentDeclined) {
+				span.RecordError(err)
+				payment.FailureReason = err.Error()
+				payment.UpdatedAt = time.Now()
+				if err := pi.paymentRepo.UpdatePayment(ctx, payment); err != nil {
+					return err
+				}
+				return failed(err.Error())
+			}
+			if err != nil {
+				return err
+			}
+			payment.Status = domain.PaymentCaptured
+			payment.CaptureRef = ref
+			payment.UpdatedAt = time.Now()
+			if err := pi.paymentRepo.UpdatePayment(ctx, payment); err != nil {
+				return err
+			}
+			log.Info("payment captured", slog.String("payment_id", payment.ID.String()))
+		default:
+			return failed(fmt.Sprintf("payment is %s", payment.Status))
+		}
+		return pi.enqueue(ctx, command.OrderID, events.PaymentCaptured{
+			OrderID:   command.OrderID,
+			SagaID:    command.SagaID,
+			PaymentID: payment.ID,
+			Amount:    payment.Amount,
+		})
+	})
+	if err != nil {
+		span.RecordError(err)
+		log.Error("failed to capture payment", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (pi *PaymentInteractor) VoidPayment(ctx context.Context, command events.VoidPayment) error {
+	const op = "service.payment.void"
+	log := pi.log.With(
+		slog.String("op", op),
+		slog.String("order_id", command.OrderID.String()),
+		slog.String("saga_id", command.SagaID.String()),
+	)
+	log.Info("voiding payment")
+	tracer := otel.Tracer("payment-service")
+	ctx, span := tracer.Start(ctx, "PaymentService.VoidPayment")
+	span.SetAttributes(
+		attribute.String("saga.id", command.SagaID.String()),
+		attribute.String("order.id", command.OrderID.String()),
+	)
+	defer span.End()
+	err := pi.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		voided := events.PaymentVoided{OrderID: command.OrderID, SagaID: command.SagaID}
+		payment, err := pi.paymentRepo.PaymentByOrderID(ctx, command.OrderID)
+		if errors.Is(err, domain.ErrPaymentNotFound) {
+			log.Warn("nothing to void, the payment was never authorized")
+			return pi.enqueue(ctx, command.OrderID, voided)
+		}
+		if err != nil {
+			return err
+		}
+
+		switch payment.Status {
+		case domain.PaymentDeclined, domain.PaymentVoided:
+			log.Warn("nothing to void", slog.String("status", string(payment.Status)))
+			return pi.enqueue(ctx, command.OrderID, voided)
+		case domain.PaymentCaptured, domain.PaymentRefunded:
+			log.Info("payment already captured, refunding it")
+			refunded, err := pi.refund(ctx, payment, command.SagaID, payment.Refundable())
+			if errors.Is(err, domain.ErrPaymentDeclined) {
+				span.RecordError(err)
+				return pi.enqueue(ctx, command.OrderID, events.PaymentVoidFailed{
+					OrderID: command.OrderID,
+					SagaID:  command.SagaID,
+					Reason:  err.Error(),
+				})
+			}
+			if err != nil {
+				return err
+			}
+			return pi.enqueue(ctx, command.OrderID, events.PaymentRefunded{
+				OrderID: command.OrderID,
+				SagaID:  command.SagaID,
+				Amount:  refunded,
+			})
+		}
+
+		err = pi.provider.Void(ctx, "void:"+command.OrderID.String(), payment.AuthorizationRef)
+		if errors.Is(err, domain.ErrPaymentDeclined) {
+			span.RecordError(err)
+			log.Warn("void declined", sl.Err(err))
+			return pi.enqueue(ctx, command.OrderID, events.PaymentVoidFailed{
+				OrderID: command.OrderID,
+				SagaID:  command.SagaID,
+				Reason:  err.Error(),
+			})
+		}
+		if err != nil {
+			return err
+		}
+		payment.Status = domain.PaymentVoided
+		payment.UpdatedAt = time.Now()
+		if err := pi.paymentRepo.UpdatePayment(ctx, payment); err != nil {
+			return err
+		}
+		log.Info("payment voided", slog.String("payment_id", payment.ID.String()))
+		return pi.enqueue(ctx, command.OrderID, voided)
+	})
+	if err != nil {
+		span.RecordError(err)
+		log.Error("failed to void payment", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (pi *PaymentInteractor) RefundPayment(ctx context.Context, command events.RefundPayment) error {
+	const op = "service.payment.refund"
+	log := pi.log.With(
+		slog.String("op", op),
+		slog.String("order_id", command.OrderID.String()),
+		slog.String("saga_id", command.SagaID.String()),
+		slog.String("amount", command.Amount.String()),
+	)
+	log.Info("refunding payment")
+	tracer := otel.Tracer("payment-service")
+	ctx, span := tracer.Start(ctx, "PaymentService.RefundPayment")
+	span.SetAttributes(
+		attribute.String("saga.id", command.SagaID.String()),
+		attribute.String("order.id", command.OrderID.String()),
+	)
+	defer span.End()
+	err := pi.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		failed := func(err error) error {
+			span.RecordError(err)
+			log.Warn("payment not refunded", sl.Err(err))
+			return pi.enqueue(ctx, command.OrderID, events.PaymentRefundFailed{
+				OrderID: command.OrderID,
+				SagaID:  command.SagaID,
+				Reason:  err.Error(),
+			})
+		}
+		payment, err := pi.paymentRepo.PaymentByOrderID(ctx, command.OrderID)
+		if errors.Is(err, domain.ErrPaymentNotFound) {
+			return failed(err)
+		}
+		if err != nil {
+			return err
+		}
+		if payment.Status != domain.PaymentCaptured && payment.Status != domain.PaymentRefunded {
+			return failed(fmt.Errorf("%w: payment is %s", domain.ErrPaymentNotActive, payment.Status))
+		}
+
+		amount := command.Amount
+		if amount.IsZero() {
+			amount = payment.Refundable()
+		}
+		refunded, err := pi.refund(ctx, payment, command.SagaID, amount)
+		if errors.Is(err, domain.ErrPaymentDeclined) || errors.Is(err, domain.ErrRefundTooLarge) {
+			return failed(err)
+		}
+		if err != nil {
+			return err
+		}
+		log.Info("payment refunded", slog.String("refunded", refunded.String()))
+		return pi.enqueue(ctx, command.OrderID, events.PaymentRefunded{
+			OrderID: command.OrderID,
+			SagaID:  command.SagaID,
+			Amount:  refunded,
+		})
+	})
+	if err != nil {
+		span.RecordError(err)
+		log.Error("failed to refund payment", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// refund returns amount of the captured payment for sagaID and records it. A
+// saga that already refunded the payment gets its earlier refund back instead.
+// Nothing is refunded for a zero amount, which is what is left of a fully
+// refunded payment.
+func (pi *PaymentInteractor) refund(ctx context.Context, payment *domain.Payment, sagaID uuid.UUID, amount money.Money) (money.Money, error) {
+	done, err := pi.paymentRepo.RefundBySagaID(ctx, sagaID)
+	if err == nil {
+		return done.Amount, nil
+	}
+	if !errors.Is(err, domain.ErrRefundNotFound) {
+		return money.Money{}, err
+	}
+	if amount.IsZero() {
+		return money.New(0, payment.Amount.Currency), nil
+	}
+	if amount.Currency != payment.Amount.Currency || amount.Amount < 0 || amount.Amount > payment.Refundable().Amount {
+		return money.Money{}, fmt.Errorf("%w: asked %s, refundable %s", domain.ErrRefundTooLarge, amount, payment.Refundable())
+	}
+
+	ref, err := pi.provider.Refund(ctx, "refund:"+sagaID.String(), payment.CaptureRef, amount)
+	if err != nil {
+		return money.Money{}, err
+	}
+	refunded, err := payment.Refunded.Add(amount)
+	if err != nil {
+		return money.Money{}, err
+	}
+	payment.Refunded = refunded
+	if refunded == payment.Amount {
+		payment.Status = domain.PaymentRefunded
+	}
+	payment.UpdatedAt = time.Now()
+	if err := pi.paymentRepo.UpdatePayment(ctx, payment); err != nil {
+		return money.Money{}, err
+	}
+	err = pi.paymentRepo.SaveRefund(ctx, &domain.PaymentRefund{
+		ID:          uuid.New(),
+		PaymentID:   payment.ID,
+		SagaID:      sagaID,
+		Amount:      amount,
+		ProviderRef: ref,
+		CreatedAt:   payment.UpdatedAt,
+	})
+	if err != nil {
+		return money.Money{}, err
+	}
+	return amount, nil
+}
+
+func authorizationReply(payment *domain.Payment) events.Event {
+	if payment.Status == domain.PaymentDeclined {
+		return events.PaymentAuthorizationFailed{
+			OrderID: payment.OrderID,
+			SagaID:  payment.SagaID,
+			Reason:  payment.FailureReason,
+		}
+	}
+	return events.PaymentAuthorized{
+		OrderID:   payment.OrderID,
+		SagaID:    payment.SagaID,
+		PaymentID: payment.ID,
+		Amount:    payment.Amount,
+	}
+}
+
+// enqueue writes a reply to the outbox within the caller's transaction.
+func (pi *PaymentInteractor) enqueue(ctx context.Context, orderID uuid.UUID, event events.Event) error {
+	return pi.outboxRepo.Enqueue(ctx, sagaRepliesTopic, orderID.String(), event)
+}
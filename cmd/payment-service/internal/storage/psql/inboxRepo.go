@@ -0,0 +1,34 @@
+package psql
+
+import (
+	"context"
+	"immxrtalbeast/order_microservices/payment-service/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InboxRepository struct {
+	db       *gorm.DB
+	consumer string
+}
+
+func NewInboxRepository(db *gorm.DB, consumer string) *InboxRepository {
+	return &InboxRepository{db: db, consumer: consumer}
+}
+
+// Record marks messageID as processed, returning domain.ErrDuplicateMessage if
+// it already was. Call it in the handler's transaction so a failed handler
+// leaves the message unrecorded.
+func (r *InboxRepository) Record(ctx context.Context, messageID, eventType string) error {
+	result := conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&domain.InboxMessage{Consumer: r.consumer, MessageID: messageID, EventType: eventType})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrDuplicateMessage
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package psql
+
+import (
+	"context"
+	"errors"
+	"immxrtalbeast/order_microservices/payment-service/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PaymentRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentRepository(db *gorm.DB) *PaymentRepository {
+	return &PaymentRepository{db: db}
+}
+
+func (r *PaymentRepository) SavePayment(ctx context.Context, payment *domain.Payment) error {
+	return conn(ctx, r.db).Omit("Refunds").Create(payment).Error
+}
+
+func (r *PaymentRepository) PaymentByOrderID(ctx context.Context, orderID uuid.UUID) (*domain.Payment, error) {
+	var payment domain.Payment
+	err := conn(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("order_id = ?", orderID).
+		First(&payment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrPaymentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (r *PaymentRepository) UpdatePayment(ctx context.Context, payment *domain.Payment) error {
+	return conn(ctx, r.db).Model(&domain.Payment{}).
+		Where("id = ?", payment.ID).
+		Updates(map[string]interface{}{
+			"status":            payment.Status,
+			"capture_ref":       payment.CaptureRef,
+			"refunded_amount":   payment.Refunded.Amount,
+			"refunded_currency": payment.Refunded.Currency,
+			"failure_reason":    payment.FailureReason,
+			"updated_at":        payment.UpdatedAt,
+		}).Error
+}
+
+func (r *PaymentRepository) SaveRefund(ctx context.Context, refund *domain.PaymentRefund) error {
+	return conn(ctx, r.db).Create(refund).Error
+}
+
+func (r *PaymentRepository) RefundBySagaID(ctx context.Context, sagaID uuid.UUID) (*domain.PaymentRefund, error) {
+	var refund domain.PaymentRefund
+	err := conn(ctx, r.db).Where("saga_id = ?", sagaID).First(&refund).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrRefundNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
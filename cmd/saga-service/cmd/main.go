@@ -2,27 +2,36 @@ package main
 
 import (
 	"context"
+	kafka "immxrtalbeast/order_microservices/internal/pkg/kafka"
+	"immxrtalbeast/order_microservices/internal/pkg/outbox"
 	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"immxrtalbeast/order_microservices/saga-service/internal/client"
 	"immxrtalbeast/order_microservices/saga-service/internal/config"
 	"immxrtalbeast/order_microservices/saga-service/internal/domain"
+	"immxrtalbeast/order_microservices/saga-service/internal/lib/logger/sl"
 	"immxrtalbeast/order_microservices/saga-service/internal/lib/logger/slogpretty"
 	"immxrtalbeast/order_microservices/saga-service/internal/service/saga"
 	"immxrtalbeast/order_microservices/saga-service/internal/tracing"
 	"immxrtalbeast/order_microservices/saga-service/storage/psql"
 
-	kafka "github.com/ozzus/order_kafka"
 	"github.com/joho/godotenv"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// serviceName stamps the outbox messages and inbox records of this service.
+const serviceName = "saga-service"
+
 func main() {
 	cfg := config.MustLoad()
 	log := setupLogger(cfg.Env)
 	log.Info("starting application")
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 	if err := godotenv.Load(".env"); err != nil {
 		panic(err)
 	}
@@ -46,15 +55,33 @@ func main() {
 		panic("failed to connect database")
 	}
 	log.Info("db connected")
-	db.AutoMigrate(&domain.Saga{})
+	db.AutoMigrate(&domain.Saga{}, &domain.SagaItem{}, &domain.SagaStep{}, &outbox.Message{}, &domain.InboxMessage{})
 
+	if err := kafka.EnsureTopics(ctx, []string{os.Getenv("KAFKA_ADDRESS")},
+		kafka.TopicsWithDeadLetters(cfg.Kafka.Partitions, cfg.Kafka.ReplicationFactor, "saga-commands", "saga-replies")...,
+	); err != nil {
+		panic(err)
+	}
 	producer := kafka.NewProducer(
 		[]string{os.Getenv("KAFKA_ADDRESS")},
 		"saga-commands",
 	)
 	defer producer.Close()
+	deadLetters := kafka.NewDeadLetterQueue([]string{os.Getenv("KAFKA_ADDRESS")})
+	defer deadLetters.Close()
 	sagaRepo := psql.NewSagaRepository(db)
-	sagaInteractor := saga.NewSagaInteractor(log, producer, sagaRepo)
+	outboxRepo := outbox.NewRepository(db, serviceName)
+	inboxRepo := psql.NewInboxRepository(db, serviceName)
+	transactor := outbox.NewTransactor(db)
+	sagaInteractor := saga.NewSagaInteractor(log, sagaRepo, outboxRepo, transactor, cfg.Saga.StepTimeout, cfg.Saga.MaxRetries)
+
+	relay := outbox.NewRelay(log, outboxRepo, map[string]*kafka.Producer{"saga-commands": producer}, cfg.Kafka.ContentType)
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		relay.Run(ctx, cfg.Outbox.PollInterval, cfg.Outbox.BatchSize)
+	}()
+	go sagaInteractor.RunSweeper(ctx, cfg.Saga.SweepInterval, cfg.Saga.SweepBatch)
 
 	repliesConsumer := kafka.NewConsumer(
 		[]string{os.Getenv("KAFKA_ADDRESS")},
@@ -62,7 +89,23 @@ func main() {
 		"saga-service-replies-group",
 	)
 	defer repliesConsumer.Close()
-	client.ProcessSagaEvents(repliesConsumer, sagaInteractor, log)
+	pool := kafka.NewPool(
+		repliesConsumer,
+		client.NewSagaEventsHandler(sagaInteractor, client.NewInbox(inboxRepo, transactor), log),
+		log,
+		cfg.Consumer.PoolConfig(deadLetters),
+	)
+	go pool.Run(ctx)
+
+	<-ctx.Done()
+	log.Info("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Consumer.ShutdownTimeout)
+	defer cancel()
+	if err := pool.Shutdown(shutdownCtx); err != nil {
+		log.Error("in-flight messages were not drained", sl.Err(err))
+	}
+	<-relayDone
+	log.Info("application stopped")
 }
 
 const (
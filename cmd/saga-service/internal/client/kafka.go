@@ -2,179 +2,174 @@ package client
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
-	"immxrtalbeast/order_microservices/saga-service/internal/domain"
-	"immxrtalbeast/order_microservices/saga-service/internal/lib/logger/sl"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	mykafka "immxrtalbeast/order_microservices/internal/pkg/kafka"
 	"immxrtalbeast/order_microservices/saga-service/internal/service/saga"
 	"log/slog"
-	"time"
 
-	mykafka "github.com/ozzus/order_kafka"
 	"github.com/segmentio/kafka-go"
 	"go.opentelemetry.io/otel/propagation"
 )
 
-func ProcessSagaEvents(consumer *mykafka.Consumer, sagaInteractor *saga.SagaInteractor, log *slog.Logger) {
-	log.Info("listening kafka for events")
-	propagator := propagation.TraceContext{}
-	for {
-		readCtx, readCancel := context.WithTimeout(context.Background(), 1*time.Second)
-
-		msg, err := consumer.ReadRawMessage(readCtx)
-		readCancel()
-		if err != nil {
-			if errors.Is(err, context.DeadlineExceeded) {
-				continue
-			}
-			log.Error("Unknown error", sl.Err(err))
-			time.Sleep(1 * time.Second)
-			continue
-		}
-		log.Info("EventReceived")
-		eventType, err := getHeaderValue(msg.Headers, "Event-Type")
-		if err != nil {
-			log.Error("error while parsing eventType", sl.Err(err), "eventType is", eventType)
-			continue
-		}
-		log.Info("event type received", slog.String("event_type", eventType))
-		if eventType == "" {
-			log.Error("missing event type header")
-			continue
-		}
-
-		baseCtx := context.Background()
-		carrier := propagation.MapCarrier{}
-		for _, header := range msg.Headers {
-			carrier[header.Key] = string(header.Value)
-		}
-
-		ctx := propagator.Extract(baseCtx, carrier)
-		processCtx, processCancel := context.WithTimeout(ctx, 30*time.Second)
-		switch eventType {
-		case "OrderCreatedEvent":
-			var event domain.OrderCreatedEvent
-			if err := json.Unmarshal(msg.Value, &event); err != nil {
-				log.Error("failed to unmarshal event", "type", eventType, "error", err)
-				continue
-			}
-			log.Info("Order created event received", "event", event)
-			go func() {
-				defer processCancel()
-				sagaInteractor.StartSaga(processCtx, event)
-			}()
-
-		case "InventoryReservedEvent":
-			var event domain.ProductsReservedEvent
-			if err := json.Unmarshal(msg.Value, &event); err != nil {
-				log.Error("failed to unmarshal event", "type", eventType, "error", err)
-				continue
-			}
-			go func() {
-				defer processCancel()
-				sagaInteractor.HandleProductsReserved(processCtx, event)
-			}()
-
-		case "InventoryReservedEventFailed":
-			var event domain.ProductsReservedEvent
-			if err := json.Unmarshal(msg.Value, &event); err != nil {
-				log.Error("failed to unmarshal event", "type", eventType, "error", err)
-				continue
-			}
-			go func() {
-				defer processCancel()
-				sagaInteractor.HandleProductsReservedError(processCtx, event)
-			}()
-
-		case "PaymentProcessedEvent":
-
-		case "ProductsReservationFailedEvent":
+func NewSagaEventsHandler(sagaInteractor *saga.SagaInteractor, inbox *Inbox, log *slog.Logger) mykafka.Handler {
+	return newEnvelopeHandler(inbox, log, func(handle handleFunc, event events.Event) error {
+		switch e := event.(type) {
+		case events.OrderCreated:
+			log.Info("Order created event received", "event", e)
+			return handle(func(ctx context.Context) error {
+				return sagaInteractor.StartSaga(ctx, e)
+			})
+
+		case events.InventoryReserved:
+			return handle(func(ctx context.Context) error {
+				return sagaInteractor.HandleProductsReserved(ctx, e)
+			})
+
+		case events.InventoryReserveFailed:
+			return handle(func(ctx context.Context) error {
+				return sagaInteractor.HandleProductsReservedError(ctx, e)
+			})
+
+		case events.OrderReady:
+			return handle(func(ctx context.Context) error {
+				return sagaInteractor.HandleOrderReady(ctx, e)
+			})
+
+		case events.OrderCompleted:
+			return handle(func(ctx context.Context) error {
+				return sagaInteractor.HandleOrderCompleted(ctx, e)
+			})
+
+		case events.InventoryCommitted:
+			return handle(func(ctx context.Context) error {
+				return sagaInteractor.HandleInventoryCommitted(ctx, e)
+			})
+
+		case events.InventoryCommitFailed:
+			return handle(func(ctx context.Context) error {
+				return sagaInteractor.HandleInventoryCommitFailed(ctx, e)
+			})
+
+		case events.InventoryReleased:
+			return handle(func(ctx context.Context) error {
+				return sagaInteractor.HandleInventoryReleased(ctx, e)
+			})
+
+		case events.InventoryReleaseFailed:
+			return handle(func(ctx context.Context) error {
+				return sagaInteractor.HandleInventoryReleaseFailed(ctx, e)
+			})
+
+		case events.InventoryHoldExpired:
+			return handle(func(ctx context.Context) error {
+				return sagaInteractor.HandleInventoryHoldExpired(ctx, e)
+			})
+
+		case events.PaymentAuthorized:
+			return handle(func(ctx context.Context) error {
+				return sagaInteractor.HandlePaymentAuthorized(ctx, e)
+			})
+
+		case events.PaymentAuthorizationFailed:
+			return handle(func(ctx context.Context) error {
+				return sagaInteractor.HandlePaymentAuthorizationFailed(ctx, e)
+			})
+
+		case events.PaymentCaptured:
+			return handle(func(ctx context.Context) error {
+				return sagaInteractor.HandlePaymentCaptured(ctx, e)
+			})
+
+		case events.PaymentCaptureFailed:
+			return handle(func(ctx context.Context) error {
+				return sagaInteractor.HandlePaymentCaptureFailed(ctx, e)
+			})
+
+		case events.PaymentVoided:
+			return handle(func(ctx context.Context) error {
+				return sagaInteractor.HandlePaymentVoided(ctx, e)
+			})
+
+		case events.PaymentVoidFailed:
+			return handle(func(ctx context.Context) error {
+				return sagaInteractor.HandlePaymentVoidFailed(ctx, e)
+			})
+
+		case events.PaymentRefunded:
+			return handle(func(ctx context.Context) error {
+				return sagaInteractor.HandlePaymentRefunded(ctx, e)
+			})
+
+		case events.PaymentRefundFailed:
+			return handle(func(ctx context.Context) error {
+				return sagaInteractor.HandlePaymentRefundFailed(ctx, e)
+			})
+
+		case events.RefundOrder:
+			log.Info("Refund order command received", "command", e)
+			return handle(func(ctx context.Context) error {
+				return sagaInteractor.StartRefund(ctx, e)
+			})
+
+		case events.InventoryRestocked:
+			return handle(func(ctx context.Context) error {
+				return sagaInteractor.HandleInventoryRestocked(ctx, e)
+			})
+
+		case events.InventoryRestockFailed:
+			return handle(func(ctx context.Context) error {
+				return sagaInteractor.HandleInventoryRestockFailed(ctx, e)
+			})
+
+		case events.CancelOrder:
+			log.Info("Cancel order command received", "command", e)
+			return handle(func(ctx context.Context) error {
+				return sagaInteractor.HandleCancelOrderCommand(ctx, e)
+			})
+
+		case events.CompensateOrder:
+			log.Info("Compensate order command received", "command", e)
+			return handle(func(ctx context.Context) error {
+				return sagaInteractor.HandleCompensateOrderCommand(ctx, e)
+			})
 
 		default:
-			log.Error("unknown event type", "type", eventType)
+			return nil
 		}
-	}
+	})
 }
 
-func ProcessSagaCommands(consumer *mykafka.Consumer, sagaInteractor *saga.SagaInteractor, log *slog.Logger) {
-	log.Info("listening kafka for commands")
-	propagator := propagation.TraceContext{}
-	for {
-		readCtx, readCancel := context.WithTimeout(context.Background(), 1*time.Second)
+type handleFunc func(fn func(ctx context.Context) error) error
 
-		msg, err := consumer.ReadRawMessage(readCtx)
-		readCancel()
-		if err != nil {
-			if errors.Is(err, context.DeadlineExceeded) {
-				continue
-			}
-			log.Error("Unknown error", sl.Err(err))
-			time.Sleep(1 * time.Second)
-			continue
-		}
+// newEnvelopeHandler decodes the envelope of each message and passes the event
+// to dispatch, together with a handle func that runs it through the inbox.
+func newEnvelopeHandler(inbox *Inbox, log *slog.Logger, dispatch func(handle handleFunc, event events.Event) error) mykafka.Handler {
+	propagator := propagation.TraceContext{}
+	return func(ctx context.Context, msg kafka.Message) error {
 		log.Info("EventReceived")
-
-		eventType, err := getHeaderValue(msg.Headers, "Event-Type")
+		env, err := events.Unmarshal(msg.Value, mykafka.Header(msg, mykafka.HeaderContentType), mykafka.Header(msg, mykafka.HeaderEventType))
 		if err != nil {
-			log.Error("error while parsing eventType", sl.Err(err), "eventType is", eventType)
-			continue
+			return mykafka.Permanent(err)
+		}
+		log.Info("event type received", slog.String("event_type", env.Type))
+		event, err := env.Decode()
+		if errors.Is(err, events.ErrUnknownType) {
+			log.Error("unknown event type", "type", env.Type)
+			return nil
 		}
-		log.Info("event type received", slog.String("event_type", eventType))
-		if eventType == "" {
-			log.Error("missing event type header")
-			continue
+		if err != nil {
+			return mykafka.Permanent(err)
 		}
 
-		baseCtx := context.Background()
 		carrier := propagation.MapCarrier{}
-
 		for _, header := range msg.Headers {
 			carrier[header.Key] = string(header.Value)
 		}
-
-		ctx := propagator.Extract(baseCtx, carrier)
-		processCtx, processCancel := context.WithTimeout(ctx, 30*time.Second)
-
-		switch eventType {
-		case "CancelOrderCommand":
-			var command domain.CancelOrderCommand
-			if err := json.Unmarshal(msg.Value, &command); err != nil {
-				log.Error("failed to unmarshal command", "type", eventType, "error", err)
-				continue
-			}
-
-			log.Info("Cancel order command received", "command", command)
-
-			go func() {
-				defer processCancel()
-				sagaInteractor.HandleCancelOrderCommand(processCtx, command)
-			}()
-
-		case "CompensateOrderCommand":
-			var command domain.CompensateOrderCommand
-			if err := json.Unmarshal(msg.Value, &command); err != nil {
-				log.Error("failed to unmarshal command", "type", eventType, "error", err)
-				continue
-			}
-
-			log.Info("Compensate order command received", "command", command)
-
-			go func() {
-				defer processCancel()
-				sagaInteractor.HandleCompensateOrderCommand(processCtx, command)
-			}()
-
-		default:
-			log.Error("unknown command type", "type", eventType)
-		}
-	}
-}
-
-func getHeaderValue(headers []kafka.Header, key string) (string, error) {
-	for _, h := range headers {
-		if h.Key == key {
-			return string(h.Value), nil
-		}
+		ctx = propagator.Extract(ctx, carrier)
+		ctx = events.WithCorrelationID(ctx, env.CorrelationID)
+		return dispatch(func(fn func(ctx context.Context) error) error {
+			return inbox.Handle(ctx, log, msg, env.Type, fn)
+		}, event)
 	}
-	return "", nil
 }
@@ -2,13 +2,59 @@ package config
 
 import (
 	"flag"
+	kafka "immxrtalbeast/order_microservices/internal/pkg/kafka"
 	"os"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
 type Config struct {
-	Env string `yaml:"env" env-default:"local"`
+	Env      string         `yaml:"env" env-default:"local"`
+	Saga     SagaConfig     `yaml:"saga"`
+	Outbox   OutboxConfig   `yaml:"outbox"`
+	Consumer ConsumerConfig `yaml:"consumer"`
+	Kafka    KafkaConfig    `yaml:"kafka"`
+}
+
+type KafkaConfig struct {
+	Partitions        int `yaml:"partitions" env-default:"6"`
+	ReplicationFactor int `yaml:"replication_factor" env-default:"1"`
+	// ContentType is the encoding of published events, application/x-protobuf
+	// or application/json. Consumers accept both.
+	ContentType string `yaml:"content_type" env-default:"application/x-protobuf"`
+}
+
+type SagaConfig struct {
+	StepTimeout   time.Duration `yaml:"step_timeout" env-default:"30s"`
+	MaxRetries    int           `yaml:"max_retries" env-default:"3"`
+	SweepInterval time.Duration `yaml:"sweep_interval" env-default:"5s"`
+	SweepBatch    int           `yaml:"sweep_batch" env-default:"50"`
+}
+
+type OutboxConfig struct {
+	PollInterval time.Duration `yaml:"poll_interval" env-default:"500ms"`
+	BatchSize    int           `yaml:"batch_size" env-default:"100"`
+}
+
+type ConsumerConfig struct {
+	Workers         int                          `yaml:"workers" env-default:"8"`
+	QueueSize       int                          `yaml:"queue_size" env-default:"64"`
+	HandlerTimeout  time.Duration                `yaml:"handler_timeout" env-default:"30s"`
+	ShutdownTimeout time.Duration                `yaml:"shutdown_timeout" env-default:"20s"`
+	Retry           kafka.RetryPolicy            `yaml:"retry"`
+	EventRetry      map[string]kafka.RetryPolicy `yaml:"event_retry"`
+}
+
+func (c ConsumerConfig) PoolConfig(deadLetters *kafka.DeadLetterQueue) kafka.PoolConfig {
+	return kafka.PoolConfig{
+		Workers:        c.Workers,
+		QueueSize:      c.QueueSize,
+		HandlerTimeout: c.HandlerTimeout,
+		Retry:          c.Retry,
+		EventRetry:     c.EventRetry,
+		DeadLetters:    deadLetters,
+	}
 }
 
 func MustLoad() *Config {
@@ -2,42 +2,182 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+var (
+	ErrIllegalTransition = errors.New("illegal saga state transition")
+	ErrSagaNotFound      = errors.New("saga not found")
+	ErrSagaExists        = errors.New("saga for order already exists")
+)
+
+// SagaKind tells the saga placing an order from the ones refunding it. An
+// order has one order saga and a refund saga per refund.
+type SagaKind string
+
+const (
+	KindOrder  SagaKind = "ORDER"
+	KindRefund SagaKind = "REFUND"
+)
+
 type Saga struct {
-	ID          string    `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
-	CurrentStep string    `gorm:"not null"`
-	UserID      uuid.UUID `gorm:"not null"`
+	ID          string     `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Kind        SagaKind   `gorm:"type:varchar(20);not null;default:'ORDER'"`
+	CurrentStep string     `gorm:"not null"`
+	UserID      uuid.UUID  `gorm:"not null"`
+	OrderID     uuid.UUID  `gorm:"type:uuid;uniqueIndex:idx_sagas_order_id,where:kind = 'ORDER'"`
+	Items       []SagaItem `gorm:"foreignKey:SagaID;constraint:OnDelete:CASCADE"`
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	ErrorReason string
+	// Command the saga is waiting a reply for, re-published by the sweeper
+	// until DeadlineAt passes MaxRetries times.
+	PendingCommandType string
+	PendingCommand     string     `gorm:"type:jsonb"`
+	DeadlineAt         *time.Time `gorm:"index"`
+	Attempts           int        `gorm:"not null;default:0"`
+	// Total is the reserved order total the payment is authorized for, or
+	// the amount a refund saga returns.
+	Total           money.Money `gorm:"embedded;embeddedPrefix:total_"`
+	PaymentID       *uuid.UUID  `gorm:"type:uuid"`
+	PaymentCaptured bool        `gorm:"not null;default:false"`
+	// CompleteRequested records an order ready before its payment was
+	// authorized; the payment is captured as soon as it is.
+	CompleteRequested bool `gorm:"not null;default:false"`
+	// CancelRequested tells a payment reversed because the order was
+	// cancelled from one reversed because a step failed, and releases a
+	// reservation that arrives after the cancel.
+	CancelRequested bool `gorm:"not null;default:false"`
+	// RefundID is the order-service refund a refund saga carries out; its
+	// items go back in stock when Restock is set.
+	RefundID *uuid.UUID `gorm:"type:uuid;uniqueIndex"`
+	Restock  bool       `gorm:"not null;default:false"`
+}
+
+func (s *Saga) State() SagaState {
+	return SagaState(s.CurrentStep)
+}
+
+// RefundedItems returns the line items a refund saga returns.
+func (s *Saga) RefundedItems() []events.Item {
+	items := make([]events.Item, 0, len(s.Items))
+	for _, item := range s.Items {
+		items = append(items, events.Item{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+	return items
+}
+
+// ReservedItems returns the line items inventory has confirmed as reserved.
+func (s *Saga) ReservedItems() []events.Item {
+	items := make([]events.Item, 0, len(s.Items))
+	for _, item := range s.Items {
+		if item.ReservedQuantity > 0 {
+			items = append(items, events.Item{ProductID: item.ProductID, Quantity: item.ReservedQuantity})
+		}
+	}
+	return items
+}
+
+type SagaItem struct {
+	ID               uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	SagaID           string    `gorm:"type:uuid;not null;index"`
+	ProductID        uuid.UUID `gorm:"type:uuid;not null"`
+	Quantity         int       `gorm:"not null"`
+	ReservedQuantity int       `gorm:"not null;default:0"`
+}
+
+// SagaStep is a single persisted transition of a saga, used to reconstruct its history.
+type SagaStep struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	SagaID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	FromState string
+	ToState   string `gorm:"not null"`
+	EventType string `gorm:"not null"`
+	Payload   string `gorm:"type:jsonb"`
+	TraceID   string
+	CreatedAt time.Time `gorm:"autoCreateTime"`
 }
 
 type SagaState string
 
 const (
-	StateOrderCreated       SagaState = "ORDER_CREATED"
-	StateInventoryReserved  SagaState = "INVENTORY_RESERVED"
-	StatePaymentProcessing  SagaState = "PAYMENT_PROCESSING"
-	StateCompleted          SagaState = "COMPLETED"
-	StateInventoryReleasing SagaState = "RELEASING"
-	StatePaymentError       SagaState = "PAYMENT_ERROR"
-	StateCompensated        SagaState = "COMPENSATED"
+	StateOrderCreated        SagaState = "ORDER_CREATED"
+	StateInventoryReserved   SagaState = "INVENTORY_RESERVED"
+	StatePaymentProcessing   SagaState = "PAYMENT_PROCESSING"
+	StatePaymentAuthorized   SagaState = "PAYMENT_AUTHORIZED"
+	StatePaymentCapturing    SagaState = "PAYMENT_CAPTURING"
+	StateInventoryCommitting SagaState = "INVENTORY_COMMITTING"
+	StateCompleted           SagaState = "COMPLETED"
+	StateInventoryReleasing  SagaState = "RELEASING"
+	StatePaymentError        SagaState = "PAYMENT_ERROR"
+	// StatePaymentReversing voids or refunds the payment before the stock is
+	// released.
+	StatePaymentReversing SagaState = "PAYMENT_REVERSING"
+	StateCompensated      SagaState = "COMPENSATED"
+	// StateCancelling releases the stock of an order its user or an admin
+	// cancelled; StateCancelled then records the cancellation.
+	StateCancelling SagaState = "CANCELLING"
+	StateCancelled  SagaState = "CANCELLED"
+
+	// Refund sagas return the money, then optionally put the refunded items
+	// back in stock.
+	StateRefundingPayment SagaState = "REFUNDING_PAYMENT"
+	StateRestocking       SagaState = "RESTOCKING"
+	StateRefunded         SagaState = "REFUNDED"
+	StateRefundFailed     SagaState = "REFUND_FAILED"
 )
 
+// sagaTransitions lists every state a saga may move to from a given state.
+// States without an entry are terminal.
+var sagaTransitions = map[SagaState][]SagaState{
+	StateOrderCreated:        {StateInventoryReserved, StateInventoryReleasing, StateCompensated, StateCancelling},
+	StateInventoryReserved:   {StatePaymentProcessing, StateInventoryCommitting, StateCompleted, StateInventoryReleasing, StateCompensated, StateCancelling},
+	StatePaymentProcessing:   {StatePaymentAuthorized, StatePaymentError, StatePaymentReversing},
+	StatePaymentAuthorized:   {StatePaymentCapturing, StatePaymentReversing},
+	StatePaymentCapturing:    {StateInventoryCommitting, StatePaymentReversing},
+	StateInventoryCommitting: {StateCompleted, StateInventoryReleasing, StatePaymentReversing},
+	StatePaymentError:        {StateInventoryReleasing, StateCompensated},
+	StatePaymentReversing:    {StateInventoryReleasing, StateCancelling},
+	StateInventoryReleasing:  {StateCompensated},
+	StateCancelling:          {StateCancelled},
+	StateRefundingPayment:    {StateRestocking, StateRefunded, StateRefundFailed},
+	StateRestocking:          {StateRefunded},
+}
+
+func (s SagaState) CanTransitionTo(next SagaState) bool {
+	for _, allowed := range sagaTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 type SagaInteractor interface {
-	StartSaga(ctx context.Context)
-	HandleProductsReserved(ctx context.Context, event ProductsReservedEvent)
-	HandleProductsReservedError(ctx context.Context, event ProductsReservedEvent)
-	HandleCancelOrderCommand(ctx context.Context, command CancelOrderCommand)
-	HandleCompensateOrderCommand(ctx context.Context, command CompensateOrderCommand)
+	StartSaga(ctx context.Context, event events.OrderCreated) error
+	HandleProductsReserved(ctx context.Context, event events.InventoryReserved) error
+	HandleProductsReservedError(ctx context.Context, event events.InventoryReserveFailed) error
+	HandleCancelOrderCommand(ctx context.Context, command events.CancelOrder) error
+	HandleCompensateOrderCommand(ctx context.Context, command events.CompensateOrder) error
+	StartRefund(ctx context.Context, command events.RefundOrder) error
 }
 
 type SagaRepository interface {
-	SaveSaga(ctx context.Context, saga *Saga) (uuid.UUID, error)
+	SaveSaga(ctx context.Context, saga *Saga, step *SagaStep) (uuid.UUID, error)
 	Saga(ctx context.Context, sagaID uuid.UUID) (*Saga, error)
+	// SagaByOrderID returns the order saga of an order.
+	SagaByOrderID(ctx context.Context, orderID uuid.UUID) (*Saga, error)
+	// UpdateSaga saves the saga while it is still in its step and awaiting
+	// the same command, returning ErrIllegalTransition otherwise.
 	UpdateSaga(ctx context.Context, saga *Saga) error
+	TransitionSaga(ctx context.Context, saga *Saga, step *SagaStep) error
+	// RequestCompletion sets CompleteRequested while the saga is still in its
+	// current step.
+	RequestCompletion(ctx context.Context, saga *Saga) error
+	ClaimExpiredSagas(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]Saga, error)
 }
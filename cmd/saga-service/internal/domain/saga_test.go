@@ -0,0 +1,48 @@
+package domain
+
+import "testing"
+
+func TestSagaStateCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to SagaState
+		want     bool
+	}{
+		{StateOrderCreated, StateInventoryReserved, true},
+		{StateOrderCreated, StateCompensated, true},
+		{StateOrderCreated, StateInventoryReleasing, true},
+		{StateOrderCreated, StatePaymentProcessing, false},
+		{StateOrderCreated, StateCompleted, false},
+		{StateInventoryReserved, StatePaymentProcessing, true},
+		{StateInventoryReserved, StateCompleted, true},
+		{StateInventoryReserved, StateInventoryReleasing, true},
+		{StateInventoryReserved, StateOrderCreated, false},
+		{StatePaymentProcessing, StatePaymentAuthorized, true},
+		{StatePaymentProcessing, StatePaymentError, true},
+		{StatePaymentProcessing, StateCompleted, false},
+		{StatePaymentProcessing, StateCompensated, false},
+		{StatePaymentAuthorized, StatePaymentCapturing, true},
+		{StatePaymentCapturing, StateInventoryCommitting, true},
+		{StateInventoryCommitting, StateCompleted, true},
+		// A payment taken or held is reversed before the stock goes back.
+		{StatePaymentAuthorized, StatePaymentReversing, true},
+		{StateInventoryCommitting, StatePaymentReversing, true},
+		{StatePaymentAuthorized, StateInventoryReleasing, false},
+		{StatePaymentReversing, StateInventoryReleasing, true},
+		{StatePaymentError, StateInventoryReleasing, true},
+		{StateInventoryReleasing, StateCompensated, true},
+		{StateInventoryReleasing, StateInventoryReserved, false},
+		// A saga does not re-enter the state it is in.
+		{StateInventoryReserved, StateInventoryReserved, false},
+		// Completed and compensated sagas are terminal.
+		{StateCompleted, StateInventoryReleasing, false},
+		{StateCompleted, StateCompensated, false},
+		{StateCompensated, StateInventoryReserved, false},
+		{StateCompensated, StateCompensated, false},
+		{SagaState("UNKNOWN"), StateCompensated, false},
+	}
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("%s -> %s allowed = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package saga
+
+import (
+	"context"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	"immxrtalbeast/order_microservices/saga-service/internal/domain"
+	"immxrtalbeast/order_microservices/saga-service/internal/lib/logger/sl"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+// HandlePaymentAuthorized waits for the order to complete before capturing,
+// unless it already has.
+func (si *SagaInteractor) HandlePaymentAuthorized(ctx context.Context, event events.PaymentAuthorized) error {
+	const op = "service.saga.HandlePaymentAuthorized"
+	log := si.log.With(
+		slog.String("op", op),
+		slog.String("sagaID", event.SagaID.String()),
+		slog.String("order_id", event.OrderID.String()),
+		slog.String("payment_id", event.PaymentID.String()),
+	)
+	tracer := otel.Tracer("saga-service")
+	ctx, span := tracer.Start(ctx, "SagaService.HandlePaymentAuthorized")
+	defer span.End()
+	saga, err := si.findSaga(ctx, event.SagaID, event.OrderID)
+	if err != nil {
+		log.Error("failed to get saga", sl.Err(err))
+		span.RecordError(err)
+		return handled(err)
+	}
+	saga.PaymentID = &event.PaymentID
+	clearPending(saga)
+	err = si.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := si.transition(ctx, log, saga, domain.StatePaymentAuthorized, event.EventType(), event); err != nil {
+			return err
+		}
+		if !saga.CompleteRequested {
+			return nil
+		}
+		if err := si.awaitReply(saga, captureCommandFor(saga)); err != nil {
+			return err
+		}
+		return si.advance(ctx, log, saga, domain.StatePaymentCapturing, event.EventType(), event)
+	})
+	if err != nil {
+		span.RecordError(err)
+		return handled(err)
+	}
+	log.Info("payment authorized")
+	return nil
+}
+
+// HandlePaymentAuthorizationFailed fails the order and releases its stock.
+func (si *SagaInteractor) HandlePaymentAuthorizationFailed(ctx context.Context, event events.PaymentAuthorizationFailed) error {
+	const op = "service.saga.HandlePaymentAuthorizationFailed"
+	log := si.log.With(
+		slog.String("op", op),
+		slog.String("sagaID", event.SagaID.String()),
+		slog.String("order_id", event.OrderID.String()),
+		slog.String("reason", event.Reason),
+	)
+	tracer := otel.Tracer("saga-service")
+	ctx, span := tracer.Start(ctx, "SagaService.HandlePaymentAuthorizationFailed")
+	defer span.End()
+	saga, err := si.findSaga(ctx, event.SagaID, event.OrderID)
+	if err != nil {
+		log.Error("failed to get saga", sl.Err(err))
+		span.RecordError(err)
+		return handled(err)
+	}
+	saga.ErrorReason = event.Reason
+	clearPending(saga)
+	err = si.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := si.transition(ctx, log, saga, domain.StatePaymentError, event.EventType(), event); err != nil {
+			return err
+		}
+		if err := si.awaitReply(saga, releaseCommandFor(saga)); err != nil {
+			return err
+		}
+		if err := si.advance(ctx, log, saga, domain.StateInventoryReleasing, event.EventType(), event); err != nil {
+			return err
+		}
+		return si.failOrder(ctx, saga)
+	})
+	if err != nil {
+		span.RecordError(err)
+		return handled(err)
+	}
+	log.Warn("payment declined, releasing stock")
+	return nil
+}
+
+func (si *SagaInteractor) HandlePaymentCaptured(ctx context.Context, event events.PaymentCaptured) error {
+	const op = "service.saga.HandlePaymentCaptured"
+	log := si.log.With(
+		slog.String("op", op),
+		slog.String("sagaID", event.SagaID.String()),
+		slog.String("order_id", event.OrderID.String()),
+	)
+	tracer := otel.Tracer("saga-service")
+	ctx, span := tracer.Start(ctx, "SagaService.HandlePaymentCaptured")
+	defer span.End()
+	saga, err := si.findSaga(ctx, event.SagaID, event.OrderID)
+	if err != nil {
+		log.Error("failed to get saga", sl.Err(err))
+		span.RecordError(err)
+		return handled(err)
+	}
+	saga.PaymentCaptured = true
+	if err := si.awaitReply(saga, commitCommandFor(saga)); err != nil {
+		log.Error("failed to prepare commit command", sl.Err(err))
+		span.RecordError(err)
+		return handled(err)
+	}
+	if err := si.advance(ctx, log, saga, domain.StateInventoryCommitting, event.EventType(), event); err != nil {
+		span.RecordError(err)
+		return handled(err)
+	}
+	log.Info("payment captured, committing stock")
+	return nil
+}
+
+// HandlePaymentCaptureFailed keeps the capture command pending, so the sweeper
+// re-sends it and reverses the payment once retries run out.
+func (si *SagaInteractor) HandlePaymentCaptureFailed(ctx context.Context, event events.PaymentCaptureFailed) error {
+	const op = "service.saga.HandlePaymentCaptureFailed"
+	log := si.log.With(
+		slog.String("op", op),
+		slog.String("sagaID", event.SagaID.String()),
+		slog.String("order_id", event.OrderID.String()),
+		slog.String("reason", event.Reason),
+	)
+	tracer := otel.Tracer("saga-service")
+	ctx, span := tracer.Start(ctx, "SagaService.HandlePaymentCaptureFailed")
+	defer span.End()
+	return si.recordFailure(ctx, log, event.SagaID, event.OrderID, event.Reason, domain.StatePaymentCapturing)
+}
+
+func (si *SagaInteractor) HandlePaymentVoided(ctx context.Context, event events.PaymentVoided) error {
+	const op = "service.saga.HandlePaymentVoided"
+	log := si.log.With(
+		slog.String("op", op),
+		slog.String("sagaID", event.SagaID.String()),
+		slog.String("order_id", event.OrderID.String()),
+	)
+	tracer := otel.Tracer("saga-service")
+	ctx, span := tracer.Start(ctx, "SagaService.HandlePaymentVoided")
+	defer span.End()
+	saga, err := si.findSaga(ctx, event.SagaID, event.OrderID)
+	if err != nil {
+		log.Error("failed to get saga", sl.Err(err))
+		span.RecordError(err)
+		return handled(err)
+	}
+	return si.paymentReversed(ctx, log, saga, event.EventType(), event)
+}
+
+func (si *SagaInteractor) HandlePaymentRefunded(ctx context.Context, event events.PaymentRefunded) error {
+	const op = "service.saga.HandlePaymentRefunded"
+	log := si.log.With(
+		slog.String("op", op),
+		slog.String("sagaID", event.SagaID.String()),
+		slog.String("order_id", event.OrderID.String()),
+		slog.String("amount", event.Amount.String()),
+	)
+	tracer := otel.Tracer("saga-service")
+	ctx, span := tracer.Start(ctx, "SagaService.HandlePaymentRefunded")
+	defer span.End()
+	saga, err := si.findSaga(ctx, event.SagaID, event.OrderID)
+	if err != nil {
+		log.Error("failed to get saga", sl.Err(err))
+		span.RecordError(err)
+		return handled(err)
+	}
+	if saga.Kind == domain.KindRefund {
+		return si.refundPaid(ctx, log, saga, event)
+	}
+	return si.paymentReversed(ctx, log, saga, event.EventType(), event)
+}
+
+// HandlePaymentVoidFailed keeps the void command pending, so the sweeper
+// re-sends it until retries run out.
+func (si *SagaInteractor) HandlePaymentVoidFailed(ctx context.Context, event events.PaymentVoidFailed) error {
+	const op = "service.saga.HandlePaymentVoidFailed"
+	log := si.log.With(
+		slog.String("op", op),
+		slog.String("sagaID", event.SagaID.String()),
+		slog.String("order_id", event.OrderID.String()),
+		slog.String("reason", event.Reason),
+	)
+	tracer := otel.Tracer("saga-service")
+	ctx, span := tracer.Start(ctx, "SagaService.HandlePaymentVoidFailed")
+	defer span.End()
+	return si.recordFailure(ctx, log, event.SagaID, event.OrderID, event.Reason, domain.StatePaymentReversing)
+}
+
+// HandlePaymentRefundFailed keeps the refund command pending, so the sweeper
+// re-sends it until retries run out.
+func (si *SagaInteractor) HandlePaymentRefundFailed(ctx context.Context, event events.PaymentRefundFailed) error {
+	const op = "service.saga.HandlePaymentRefundFailed"
+	log := si.log.With(
+		slog.String("op", op),
+		slog.String("sagaID", event.SagaID.String()),
+		slog.String("order_id", event.OrderID.String()),
+		slog.String("reason", event.Reason),
+	)
+	tracer := otel.Tracer("saga-service")
+	ctx, span := tracer.Start(ctx, "SagaService.HandlePaymentRefundFailed")
+	defer span.End()
+	return si.recordFailure(ctx, log, event.SagaID, event.OrderID, event.Reason, domain.StatePaymentReversing, domain.StateRefundingPayment)
+}
+
+// paymentReversed releases the stock once the payment is voided or refunded,
+// cancelling the order afterwards if that is why the payment was reversed.
+func (si *SagaInteractor) paymentReversed(ctx context.Context, log *slog.Logger, saga *domain.Saga, eventType string, payload interface{}) error {
+	if err := si.awaitReply(saga, releaseCommandFor(saga)); err != nil {
+		log.Error("failed to prepare release command", sl.Err(err))
+		return handled(err)
+	}
+	if err := si.advance(ctx, log, saga, releaseStateFor(saga), eventType, payload); err != nil {
+		return handled(err)
+	}
+	log.Info("payment reversed, releasing stock")
+	return nil
+}
+
+// releaseStateFor is the step that releases the stock of a saga after its
+// payment was reversed.
+func releaseStateFor(saga *domain.Saga) domain.SagaState {
+	if saga.CancelRequested {
+		return domain.StateCancelling
+	}
+	return domain.StateInventoryReleasing
+}
+
+func authorizeCommandFor(saga *domain.Saga) events.AuthorizePayment {
+	sagaID, _ := uuid.Parse(saga.ID)
+	return events.AuthorizePayment{
+		OrderID: saga.OrderID,
+		SagaID:  sagaID,
+		UserID:  saga.UserID,
+		Amount:  saga.Total,
+	}
+}
+
+func captureCommandFor(saga *domain.Saga) events.CapturePayment {
+	sagaID, _ := uuid.Parse(saga.ID)
+	return events.CapturePayment{
+		OrderID: saga.OrderID,
+		SagaID:  sagaID,
+	}
+}
+
+// reversalCommandFor refunds a captured payment in full and voids any other.
+// A void also covers an authorization that may still be in flight.
+func reversalCommandFor(saga *domain.Saga) events.Event {
+	sagaID, _ := uuid.Parse(saga.ID)
+	if saga.PaymentCaptured {
+		return events.RefundPayment{OrderID: saga.OrderID, SagaID: sagaID}
+	}
+	return events.VoidPayment{OrderID: saga.OrderID, SagaID: sagaID}
+}
@@ -0,0 +1,205 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	"immxrtalbeast/order_microservices/saga-service/internal/domain"
+	"immxrtalbeast/order_microservices/saga-service/internal/lib/logger/sl"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// StartRefund starts a refund saga that returns the refunded amount and, when
+// asked to, puts the refunded items back in stock.
+func (si *SagaInteractor) StartRefund(ctx context.Context, command events.RefundOrder) error {
+	const op = "service.saga.StartRefund"
+	log := si.log.With(
+		slog.String("op", op),
+		slog.String("order_id", command.OrderID.String()),
+		slog.String("refund_id", command.RefundID.String()),
+		slog.String("amount", command.Amount.String()),
+	)
+	log.Info("starting refund saga")
+	tracer := otel.Tracer("saga-service")
+	ctx, span := tracer.Start(ctx, "SagaService.StartRefund")
+	defer span.End()
+	sagaID := uuid.New()
+	saga := &domain.Saga{
+		ID:          sagaID.String(),
+		Kind:        domain.KindRefund,
+		CurrentStep: string(domain.StateRefundingPayment),
+		UserID:      command.UserID,
+		OrderID:     command.OrderID,
+		Total:       command.Amount,
+		RefundID:    &command.RefundID,
+		Restock:     command.Restock,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	for _, product := range command.Items {
+		saga.Items = append(saga.Items, domain.SagaItem{
+			ID:        uuid.New(),
+			SagaID:    saga.ID,
+			ProductID: product.ProductID,
+			Quantity:  product.Quantity,
+		})
+	}
+	refund := events.RefundPayment{
+		OrderID: command.OrderID,
+		SagaID:  sagaID,
+		Amount:  command.Amount,
+	}
+	if err := si.awaitReply(saga, refund); err != nil {
+		log.Error("failed to prepare refund command", sl.Err(err))
+		span.RecordError(err)
+		return err
+	}
+	step := newSagaStep(ctx, "", domain.StateRefundingPayment, command.EventType(), command)
+	err := si.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := si.sagaRepo.SaveSaga(ctx, saga, step); err != nil {
+			return err
+		}
+		return si.ExecuteSaga(ctx, saga)
+	})
+	if errors.Is(err, domain.ErrSagaExists) {
+		log.Warn("refund saga already started")
+		return nil
+	}
+	if err != nil {
+		log.Error("failed to save refund saga", sl.Err(err))
+		span.RecordError(err)
+		return err
+	}
+	log.Info("refund saga saved", slog.String("saga_id", saga.ID))
+	return nil
+}
+
+// refundPaid restocks the refunded items once the money is returned, or
+// finishes the refund if they stay out of stock.
+func (si *SagaInteractor) refundPaid(ctx context.Context, log *slog.Logger, saga *domain.Saga, event events.PaymentRefunded) error {
+	span := trace.SpanFromContext(ctx)
+	clearPending(saga)
+	if !saga.Restock {
+		err := si.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := si.transition(ctx, log, saga, domain.StateRefunded, event.EventType(), event); err != nil {
+				return err
+			}
+			return si.completeRefund(ctx, saga)
+		})
+		if err != nil {
+			span.RecordError(err)
+			return handled(err)
+		}
+		log.Info("refund paid")
+		return nil
+	}
+
+	if err := si.awaitReply(saga, restockCommandFor(saga)); err != nil {
+		log.Error("failed to prepare restock command", sl.Err(err))
+		span.RecordError(err)
+		return handled(err)
+	}
+	if err := si.advance(ctx, log, saga, domain.StateRestocking, event.EventType(), event); err != nil {
+		span.RecordError(err)
+		return handled(err)
+	}
+	log.Info("refund paid, restocking items")
+	return nil
+}
+
+func (si *SagaInteractor) HandleInventoryRestocked(ctx context.Context, event events.InventoryRestocked) error {
+	const op = "service.saga.HandleInventoryRestocked"
+	log := si.log.With(
+		slog.String("op", op),
+		slog.String("sagaID", event.SagaID.String()),
+		slog.String("order_id", event.OrderID.String()),
+	)
+	tracer := otel.Tracer("saga-service")
+	ctx, span := tracer.Start(ctx, "SagaService.HandleInventoryRestocked")
+	defer span.End()
+	saga, err := si.sagaRepo.Saga(ctx, event.SagaID)
+	if err != nil {
+		log.Error("failed to get saga", sl.Err(err))
+		span.RecordError(err)
+		return handled(err)
+	}
+	clearPending(saga)
+	err = si.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := si.transition(ctx, log, saga, domain.StateRefunded, event.EventType(), event); err != nil {
+			return err
+		}
+		return si.completeRefund(ctx, saga)
+	})
+	if err != nil {
+		span.RecordError(err)
+		return handled(err)
+	}
+	log.Info("refund completed")
+	return nil
+}
+
+// HandleInventoryRestockFailed keeps the restock command pending, so the
+// sweeper re-sends it until retries run out.
+func (si *SagaInteractor) HandleInventoryRestockFailed(ctx context.Context, event events.InventoryRestockFailed) error {
+	const op = "service.saga.HandleInventoryRestockFailed"
+	log := si.log.With(
+		slog.String("op", op),
+		slog.String("sagaID", event.SagaID.String()),
+		slog.String("order_id", event.OrderID.String()),
+		slog.String("reason", event.Reason),
+	)
+	tracer := otel.Tracer("saga-service")
+	ctx, span := tracer.Start(ctx, "SagaService.HandleInventoryRestockFailed")
+	defer span.End()
+	return si.recordFailure(ctx, log, event.SagaID, event.OrderID, event.Reason, domain.StateRestocking)
+}
+
+// expireRefund closes a refund saga whose step ran out of retries. An
+// unconfirmed refund fails, so the amount can be refunded again; items that
+// were never restocked have to be put back by hand, but the money is
+// returned, so the refund still completes.
+func (si *SagaInteractor) expireRefund(ctx context.Context, log *slog.Logger, saga *domain.Saga, payload stepTimeoutPayload) error {
+	pending := saga.PendingCommandType
+	clearPending(saga)
+	if pending == events.TypeRestockInventory {
+		return si.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := si.transition(ctx, log, saga, domain.StateRefunded, "SagaStepTimeout", payload); err != nil {
+				return err
+			}
+			return si.completeRefund(ctx, saga)
+		})
+	}
+	return si.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := si.transition(ctx, log, saga, domain.StateRefundFailed, "SagaStepTimeout", payload); err != nil {
+			return err
+		}
+		return si.enqueue(ctx, saga, events.OrderRefundFailed{
+			OrderID:  saga.OrderID,
+			RefundID: *saga.RefundID,
+			Reason:   saga.ErrorReason,
+		})
+	})
+}
+
+// completeRefund tells order-service the refund went through.
+func (si *SagaInteractor) completeRefund(ctx context.Context, saga *domain.Saga) error {
+	return si.enqueue(ctx, saga, events.OrderRefundCompleted{
+		OrderID:  saga.OrderID,
+		RefundID: *saga.RefundID,
+		Amount:   saga.Total,
+	})
+}
+
+func restockCommandFor(saga *domain.Saga) events.RestockInventory {
+	sagaID, _ := uuid.Parse(saga.ID)
+	return events.RestockInventory{
+		OrderID: saga.OrderID,
+		SagaID:  sagaID,
+		Items:   saga.RefundedItems(),
+	}
+}
@@ -2,32 +2,43 @@ package saga
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
 	"immxrtalbeast/order_microservices/saga-service/internal/domain"
 	"immxrtalbeast/order_microservices/saga-service/internal/lib/logger/sl"
 	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
-	kafka "github.com/ozzus/order_kafka"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 )
 
-type SagaInteractor struct {
-	log      *slog.Logger
-	producer *kafka.Producer
-	sagaRepo domain.SagaRepository
-}
+const sagaCommandsTopic = "saga-commands"
 
-type orderStatusUpdateCommand struct {
-	OrderID uuid.UUID `json:"order_id"`
-	Status  string    `json:"status"`
+type SagaInteractor struct {
+	log         *slog.Logger
+	sagaRepo    domain.SagaRepository
+	outboxRepo  domain.OutboxRepository
+	transactor  domain.Transactor
+	stepTimeout time.Duration
+	maxRetries  int
 }
 
-func NewSagaInteractor(log *slog.Logger, producer *kafka.Producer, sagaRepo domain.SagaRepository) *SagaInteractor {
-	return &SagaInteractor{log: log, producer: producer, sagaRepo: sagaRepo}
+func NewSagaInteractor(log *slog.Logger, sagaRepo domain.SagaRepository, outboxRepo domain.OutboxRepository, transactor domain.Transactor, stepTimeout time.Duration, maxRetries int) *SagaInteractor {
+	return &SagaInteractor{
+		log:         log,
+		sagaRepo:    sagaRepo,
+		outboxRepo:  outboxRepo,
+		transactor:  transactor,
+		stepTimeout: stepTimeout,
+		maxRetries:  maxRetries,
+	}
 }
 
-func (si *SagaInteractor) StartSaga(ctx context.Context, event domain.OrderCreatedEvent) error {
+func (si *SagaInteractor) StartSaga(ctx context.Context, event events.OrderCreated) error {
 	const op = "service.saga.start"
 	log := si.log.With(
 		slog.String("op", op),
@@ -36,58 +47,83 @@ func (si *SagaInteractor) StartSaga(ctx context.Context, event domain.OrderCreat
 	tracer := otel.Tracer("saga-service")
 	ctx, span := tracer.Start(ctx, "SagaService.StartSaga")
 	defer span.End()
+	sagaID := uuid.New()
 	saga := &domain.Saga{
+		ID:          sagaID.String(),
 		CurrentStep: string(domain.StateOrderCreated),
 		UserID:      event.UserID,
+		OrderID:     event.OrderID,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
-	sagaID, err := si.sagaRepo.SaveSaga(ctx, saga)
+	for _, product := range event.Items {
+		saga.Items = append(saga.Items, domain.SagaItem{
+			ID:        uuid.New(),
+			SagaID:    saga.ID,
+			ProductID: product.ProductID,
+			Quantity:  product.Quantity,
+		})
+	}
+	command := events.ReserveInventory{
+		SagaID:  sagaID,
+		OrderID: event.OrderID,
+		Items:   event.Items,
+	}
+	if err := si.awaitReply(saga, command); err != nil {
+		log.Error("failed to prepare reserve command", sl.Err(err))
+		span.RecordError(err)
+		return err
+	}
+	step := newSagaStep(ctx, "", domain.StateOrderCreated, event.EventType(), event)
+	err := si.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := si.sagaRepo.SaveSaga(ctx, saga, step); err != nil {
+			return err
+		}
+		return si.ExecuteSaga(ctx, saga)
+	})
+	if errors.Is(err, domain.ErrSagaExists) {
+		log.Warn("saga for order already started", slog.String("order_id", event.OrderID.String()))
+		return nil
+	}
 	if err != nil {
 		log.Error("failed to save saga", sl.Err(err))
-		if err := si.producer.PublishEvent(context.Background(), "StartSagaError", ""); err != nil {
-			log.Error("Failed to publish event", sl.Err(err))
-		}
+		span.RecordError(err)
 		return err
 	}
-	log.Info("saga saved", slog.String("saga_id", sagaID.String()))
-
-	si.ExecuteSaga(ctx, saga, event.OrderID, event.Products)
+	log.Info("saga saved", slog.String("saga_id", saga.ID))
 	return nil
 }
 
-func (si *SagaInteractor) ExecuteSaga(ctx context.Context, saga *domain.Saga, orderID uuid.UUID, products []domain.OrderItem) {
+// ExecuteSaga queues the command of the step the saga is currently waiting on.
+// Call it in the transaction that moved the saga to that step.
+func (si *SagaInteractor) ExecuteSaga(ctx context.Context, saga *domain.Saga) error {
 	const op = "service.saga.execute"
 	log := si.log.With(
 		slog.String("op", op),
 		slog.String("sagaID", saga.ID),
+		slog.String("command", saga.PendingCommandType),
+		slog.Int("attempt", saga.Attempts),
 	)
 	log.Info("Executing saga...")
-	tracer := otel.Tracer("saga-service")
-	ctx, span := tracer.Start(ctx, "SagaService.ExecuteSaga")
-	defer span.End()
-	sagaID, err := uuid.Parse(saga.ID)
-	if err != nil {
-		log.Error("invalid saga id", sl.Err(err))
-		span.RecordError(err)
-		return
+	if saga.PendingCommandType == "" {
+		log.Warn("saga has no pending command")
+		return nil
 	}
 
-	command := domain.ReserveItemsCommand{
-		SagaID:   sagaID,
-		OrderID:  orderID,
-		Products: products,
+	command, err := pendingCommand(saga)
+	if err != nil {
+		log.Error("failed to decode pending command", sl.Err(err))
+		return err
 	}
-	if err := si.producer.PublishEventWithEventType(ctx, "InventoryReserveItemsCommand", command, "InventoryReserveItemsCommand"); err != nil {
-		log.Error("Failed to publish event", sl.Err(err))
-		span.RecordError(err)
-		return
+	if err := si.enqueue(ctx, saga, command); err != nil {
+		log.Error("failed to enqueue pending command", sl.Err(err))
+		return err
 	}
-	log.Info("Command to reserve items sended!")
-
+	log.Info("Pending command queued")
+	return nil
 }
 
-func (si *SagaInteractor) HandleProductsReserved(ctx context.Context, event domain.ProductsReservedEvent) {
+func (si *SagaInteractor) HandleProductsReserved(ctx context.Context, event events.InventoryReserved) error {
 	const op = "service.saga.ProductsReserved"
 	log := si.log.With(
 		slog.String("op", op),
@@ -100,21 +136,40 @@ func (si *SagaInteractor) HandleProductsReserved(ctx context.Context, event doma
 	if err != nil {
 		log.Error("Failed to get saga", sl.Err(err))
 		span.RecordError(err)
-		return
+		return handled(err)
 	}
-	saga.CurrentStep = string(domain.StateInventoryReserved)
-	saga.UpdatedAt = time.Now()
-	err = si.sagaRepo.UpdateSaga(ctx, saga)
+	markReserved(saga, event.Items)
+	if saga.CancelRequested {
+		// The order was cancelled while the reservation was in flight, so the
+		// release sent then may have found nothing to release.
+		if err := si.enqueue(ctx, saga, releaseCommandFor(saga)); err != nil {
+			log.Error("failed to enqueue release command", sl.Err(err))
+			span.RecordError(err)
+			return handled(err)
+		}
+		log.Info("reservation arrived after cancel, releasing it")
+		return nil
+	}
+	saga.Total = event.Total
+	clearPending(saga)
+	err = si.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := si.transition(ctx, log, saga, domain.StateInventoryReserved, event.EventType(), event); err != nil {
+			return err
+		}
+		if err := si.awaitReply(saga, authorizeCommandFor(saga)); err != nil {
+			return err
+		}
+		return si.advance(ctx, log, saga, domain.StatePaymentProcessing, event.EventType(), event)
+	})
 	if err != nil {
-		log.Error("Failed to save saga", sl.Err(err))
 		span.RecordError(err)
-		return
+		return handled(err)
 	}
-	log.Info("Products reservation handled")
-
+	log.Info("Products reservation handled, authorizing payment")
+	return nil
 }
 
-func (si *SagaInteractor) HandleProductsReservedError(ctx context.Context, event domain.ProductsReservedEvent) {
+func (si *SagaInteractor) HandleProductsReservedError(ctx context.Context, event events.InventoryReserveFailed) error {
 	const op = "service.saga.HandleProductsReservedError"
 	log := si.log.With(
 		slog.String("op", op),
@@ -127,29 +182,30 @@ func (si *SagaInteractor) HandleProductsReservedError(ctx context.Context, event
 	if err != nil {
 		log.Error("Failed to get saga", sl.Err(err))
 		span.RecordError(err)
-		return
+		return handled(err)
 	}
 
-	saga.CurrentStep = string(domain.StateCompensated)
-	saga.UpdatedAt = time.Now()
-	if err = si.sagaRepo.UpdateSaga(ctx, saga); err != nil {
-		log.Error("Failed to save saga", sl.Err(err))
-		span.RecordError(err)
-		return
-	}
-	command := orderStatusUpdateCommand{
-		OrderID: event.OrderID,
-		Status:  "CANCELLED",
-	}
-	if err := si.producer.PublishEventWithEventType(ctx, "OrderStatusUpdateCommand", command, "OrderStatusUpdateCommand"); err != nil {
-		log.Error("Failed to publish event", sl.Err(err))
+	saga.ErrorReason = event.Reason
+	clearPending(saga)
+	err = si.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := si.transition(ctx, log, saga, domain.StateCompensated, event.EventType(), event); err != nil {
+			return err
+		}
+		return si.failOrder(ctx, saga)
+	})
+	if err != nil {
 		span.RecordError(err)
-		return
+		return handled(err)
 	}
-	log.Info("Command to cancel order sended!")
+	log.Info("Command to fail order queued")
+	return nil
 }
 
-func (si *SagaInteractor) HandleCancelOrderCommand(ctx context.Context, command domain.CancelOrderCommand) {
+// HandleCancelOrderCommand releases the stock of an order that is cancelled
+// before preparing started, voiding its payment first if there is one. The
+// order is cancelled once the release is confirmed; a reservation that was
+// still in flight is released when it arrives.
+func (si *SagaInteractor) HandleCancelOrderCommand(ctx context.Context, command events.CancelOrder) error {
 	const op = "service.saga.HandleCancelOrderCommand"
 	log := si.log.With(
 		slog.String("op", op),
@@ -161,37 +217,33 @@ func (si *SagaInteractor) HandleCancelOrderCommand(ctx context.Context, command
 	ctx, span := tracer.Start(ctx, "SagaService.HandleCancelOrderCommand")
 	defer span.End()
 
-	saga, err := si.sagaRepo.Saga(ctx, command.SagaID)
+	saga, err := si.findSaga(ctx, command.SagaID, command.OrderID)
 	if err != nil {
 		log.Error("failed to get saga", sl.Err(err))
 		span.RecordError(err)
-		return
+		return handled(err)
 	}
 
-	saga.CurrentStep = string(domain.StateCompensated)
-	saga.UpdatedAt = time.Now()
-
-	if err := si.sagaRepo.UpdateSaga(ctx, saga); err != nil {
-		log.Error("failed to update saga", sl.Err(err))
-		span.RecordError(err)
-		return
+	saga.CancelRequested = true
+	next, pending := domain.StateCancelling, events.Event(releaseCommandFor(saga))
+	if saga.State() == domain.StatePaymentProcessing || saga.State() == domain.StatePaymentAuthorized {
+		next, pending = domain.StatePaymentReversing, reversalCommandFor(saga)
 	}
-
-	releaseCommand := domain.ReleaseInventoryCommand{
-		OrderID: command.OrderID,
-		SagaID:  command.SagaID,
+	if err := si.awaitReply(saga, pending); err != nil {
+		log.Error("failed to prepare cancel command", sl.Err(err))
+		span.RecordError(err)
+		return handled(err)
 	}
-
-	if err := si.producer.PublishEventWithEventType(ctx, "ReleaseInventoryCommand", releaseCommand, "ReleaseInventoryCommand"); err != nil {
-		log.Error("failed to publish release command", sl.Err(err))
+	if err := si.advance(ctx, log, saga, next, command.EventType(), command); err != nil {
 		span.RecordError(err)
-		return
+		return handled(err)
 	}
 
 	log.Info("cancel order command handled successfully")
+	return nil
 }
 
-func (si *SagaInteractor) HandleCompensateOrderCommand(ctx context.Context, command domain.CompensateOrderCommand) {
+func (si *SagaInteractor) HandleCompensateOrderCommand(ctx context.Context, command events.CompensateOrder) error {
 	const op = "saga.HandleCompensateOrderCommand"
 	log := si.log.With(
 		slog.String("op", op),
@@ -204,32 +256,440 @@ func (si *SagaInteractor) HandleCompensateOrderCommand(ctx context.Context, comm
 	ctx, span := tracer.Start(ctx, "SagaService.HandleCompensateOrderCommand")
 	defer span.End()
 
-	saga, err := si.sagaRepo.Saga(ctx, command.SagaID)
+	saga, err := si.findSaga(ctx, command.SagaID, command.OrderID)
 	if err != nil {
 		log.Error("failed to get saga", sl.Err(err))
 		span.RecordError(err)
-		return
+		return handled(err)
 	}
 
-	saga.CurrentStep = string(domain.StateCompensated)
-	saga.UpdatedAt = time.Now()
+	if err := si.awaitReply(saga, releaseCommandFor(saga)); err != nil {
+		log.Error("failed to prepare release command", sl.Err(err))
+		span.RecordError(err)
+		return handled(err)
+	}
+	if err := si.advance(ctx, log, saga, domain.StateInventoryReleasing, command.EventType(), command); err != nil {
+		span.RecordError(err)
+		return handled(err)
+	}
 
-	if err := si.sagaRepo.UpdateSaga(ctx, saga); err != nil {
-		log.Error("failed to update saga", sl.Err(err))
+	log.Info("compensate order command handled successfully")
+	return nil
+}
+
+// HandleOrderReady captures the payment of an order that is ready to be handed
+// over and then commits its stock. order-service only completes the order
+// once the capture is confirmed, so a failed capture fails a READY order.
+func (si *SagaInteractor) HandleOrderReady(ctx context.Context, event events.OrderReady) error {
+	const op = "service.saga.HandleOrderReady"
+	log := si.log.With(
+		slog.String("op", op),
+		slog.String("order_id", event.OrderID.String()),
+	)
+	tracer := otel.Tracer("saga-service")
+	ctx, span := tracer.Start(ctx, "SagaService.HandleOrderReady")
+	defer span.End()
+	if err := si.settle(ctx, log, event.OrderID, event.EventType(), event); err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+// HandleOrderCompleted settles orders that reached READY before it was
+// reported to the saga; for the others the saga has already moved on.
+func (si *SagaInteractor) HandleOrderCompleted(ctx context.Context, event events.OrderCompleted) error {
+	const op = "service.saga.HandleOrderCompleted"
+	log := si.log.With(
+		slog.String("op", op),
+		slog.String("order_id", event.OrderID.String()),
+	)
+	tracer := otel.Tracer("saga-service")
+	ctx, span := tracer.Start(ctx, "SagaService.HandleOrderCompleted")
+	defer span.End()
+	if err := si.settle(ctx, log, event.OrderID, event.EventType(), event); err != nil {
 		span.RecordError(err)
-		return
+		return err
 	}
+	return nil
+}
 
-	releaseCommand := domain.ReleaseInventoryCommand{
-		OrderID: command.OrderID,
-		SagaID:  command.SagaID,
+// settle captures the payment of the order and commits its stock.
+func (si *SagaInteractor) settle(ctx context.Context, log *slog.Logger, orderID uuid.UUID, eventType string, payload interface{}) error {
+	saga, err := si.sagaRepo.SagaByOrderID(ctx, orderID)
+	if err != nil {
+		log.Error("failed to get saga", sl.Err(err))
+		return handled(err)
+	}
+	if saga.State() == domain.StatePaymentProcessing {
+		// A payment authorized meanwhile fails this with ErrIllegalTransition,
+		// and the redelivery captures it.
+		if err := si.sagaRepo.RequestCompletion(ctx, saga); err != nil {
+			log.Error("failed to defer completion", sl.Err(err))
+			return err
+		}
+		log.Info("order ready before payment authorization, capture deferred")
+		return nil
+	}
+	if saga.State() != domain.StateInventoryReserved && saga.State() != domain.StatePaymentAuthorized {
+		log.Info("order already settled", slog.String("step", saga.CurrentStep))
+		return nil
 	}
 
-	if err := si.producer.PublishEventWithEventType(ctx, "ReleaseInventoryCommand", releaseCommand, "ReleaseInventoryCommand"); err != nil {
-		log.Error("failed to publish release command", sl.Err(err))
+	// Sagas started before the payment step commit the stock right away.
+	next, command := domain.StateInventoryCommitting, events.Event(commitCommandFor(saga))
+	if saga.State() == domain.StatePaymentAuthorized {
+		next, command = domain.StatePaymentCapturing, captureCommandFor(saga)
+	}
+	if err := si.awaitReply(saga, command); err != nil {
+		log.Error("failed to prepare command", sl.Err(err))
+		return handled(err)
+	}
+	return handled(si.advance(ctx, log, saga, next, eventType, payload))
+}
+
+func (si *SagaInteractor) HandleInventoryCommitted(ctx context.Context, event events.InventoryCommitted) error {
+	const op = "service.saga.HandleInventoryCommitted"
+	log := si.log.With(
+		slog.String("op", op),
+		slog.String("sagaID", event.SagaID.String()),
+		slog.String("order_id", event.OrderID.String()),
+	)
+	tracer := otel.Tracer("saga-service")
+	ctx, span := tracer.Start(ctx, "SagaService.HandleInventoryCommitted")
+	defer span.End()
+	saga, err := si.findSaga(ctx, event.SagaID, event.OrderID)
+	if err != nil {
+		log.Error("failed to get saga", sl.Err(err))
+		span.RecordError(err)
+		return handled(err)
+	}
+	clearPending(saga)
+	if err := si.transition(ctx, log, saga, domain.StateCompleted, event.EventType(), event); err != nil {
 		span.RecordError(err)
-		return
+		return handled(err)
 	}
+	log.Info("saga completed")
+	return nil
+}
 
-	log.Info("compensate order command handled successfully")
+// HandleInventoryCommitFailed keeps the commit command pending, so the sweeper
+// re-sends it and compensates once retries run out.
+func (si *SagaInteractor) HandleInventoryCommitFailed(ctx context.Context, event events.InventoryCommitFailed) error {
+	const op = "service.saga.HandleInventoryCommitFailed"
+	log := si.log.With(
+		slog.String("op", op),
+		slog.String("sagaID", event.SagaID.String()),
+		slog.String("order_id", event.OrderID.String()),
+		slog.String("reason", event.Reason),
+	)
+	tracer := otel.Tracer("saga-service")
+	ctx, span := tracer.Start(ctx, "SagaService.HandleInventoryCommitFailed")
+	defer span.End()
+	return si.recordFailure(ctx, log, event.SagaID, event.OrderID, event.Reason, domain.StateInventoryCommitting)
+}
+
+func (si *SagaInteractor) HandleInventoryReleased(ctx context.Context, event events.InventoryReleased) error {
+	const op = "service.saga.HandleInventoryReleased"
+	log := si.log.With(
+		slog.String("op", op),
+		slog.String("sagaID", event.SagaID.String()),
+		slog.String("order_id", event.OrderID.String()),
+	)
+	tracer := otel.Tracer("saga-service")
+	ctx, span := tracer.Start(ctx, "SagaService.HandleInventoryReleased")
+	defer span.End()
+	saga, err := si.findSaga(ctx, event.SagaID, event.OrderID)
+	if err != nil {
+		log.Error("failed to get saga", sl.Err(err))
+		span.RecordError(err)
+		return handled(err)
+	}
+	clearPending(saga)
+	if saga.State() == domain.StateCancelling {
+		err = si.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := si.transition(ctx, log, saga, domain.StateCancelled, event.EventType(), event); err != nil {
+				return err
+			}
+			return si.cancelOrder(ctx, saga, "stock released")
+		})
+	} else {
+		err = si.transition(ctx, log, saga, domain.StateCompensated, event.EventType(), event)
+	}
+	if err != nil {
+		span.RecordError(err)
+		return handled(err)
+	}
+	log.Info("inventory release handled")
+	return nil
+}
+
+// HandleInventoryReleaseFailed keeps the release command pending, so the sweeper
+// re-sends it until retries run out.
+func (si *SagaInteractor) HandleInventoryReleaseFailed(ctx context.Context, event events.InventoryReleaseFailed) error {
+	const op = "service.saga.HandleInventoryReleaseFailed"
+	log := si.log.With(
+		slog.String("op", op),
+		slog.String("sagaID", event.SagaID.String()),
+		slog.String("order_id", event.OrderID.String()),
+		slog.String("reason", event.Reason),
+	)
+	tracer := otel.Tracer("saga-service")
+	ctx, span := tracer.Start(ctx, "SagaService.HandleInventoryReleaseFailed")
+	defer span.End()
+	return si.recordFailure(ctx, log, event.SagaID, event.OrderID, event.Reason, domain.StateInventoryReleasing, domain.StateCancelling)
+}
+
+// HandleInventoryHoldExpired fails an order whose stock hold expired before it
+// was committed: the stock may already be reserved by another order. The
+// payment is reversed first if there may be one. Inventory also reports the
+// expiry when it rejects the commit. While a capture is in flight the expiry
+// is left to that rejection, so the reversal knows whether to refund.
+func (si *SagaInteractor) HandleInventoryHoldExpired(ctx context.Context, event events.InventoryHoldExpired) error {
+	const op = "service.saga.HandleInventoryHoldExpired"
+	log := si.log.With(
+		slog.String("op", op),
+		slog.String("sagaID", event.SagaID.String()),
+		slog.String("order_id", event.OrderID.String()),
+	)
+	tracer := otel.Tracer("saga-service")
+	ctx, span := tracer.Start(ctx, "SagaService.HandleInventoryHoldExpired")
+	defer span.End()
+	saga, err := si.findSaga(ctx, event.SagaID, event.OrderID)
+	if err != nil {
+		log.Error("failed to get saga", sl.Err(err))
+		span.RecordError(err)
+		return handled(err)
+	}
+
+	var next domain.SagaState
+	var pending events.Event
+	switch saga.State() {
+	case domain.StateInventoryReserved:
+		next, pending = domain.StateInventoryReleasing, releaseCommandFor(saga)
+	case domain.StatePaymentProcessing, domain.StatePaymentAuthorized, domain.StateInventoryCommitting:
+		next, pending = domain.StatePaymentReversing, reversalCommandFor(saga)
+	}
+	if pending == nil {
+		log.Info("hold expired for saga in another step, ignored", slog.String("step", saga.CurrentStep))
+		return nil
+	}
+
+	saga.ErrorReason = "stock hold expired"
+	if err := si.awaitReply(saga, pending); err != nil {
+		log.Error("failed to prepare compensation command", sl.Err(err))
+		span.RecordError(err)
+		return handled(err)
+	}
+	err = si.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := si.transition(ctx, log, saga, next, event.EventType(), event); err != nil {
+			return err
+		}
+		if err := si.failOrder(ctx, saga); err != nil {
+			return err
+		}
+		return si.ExecuteSaga(ctx, saga)
+	})
+	if err != nil {
+		span.RecordError(err)
+		return handled(err)
+	}
+	log.Warn("stock hold expired, failing order")
+	return nil
+}
+
+// recordFailure notes why the pending command failed without moving the saga,
+// as long as the saga is still in one of the expected steps.
+func (si *SagaInteractor) recordFailure(ctx context.Context, log *slog.Logger, sagaID, orderID uuid.UUID, reason string, expected ...domain.SagaState) error {
+	span := trace.SpanFromContext(ctx)
+	saga, err := si.findSaga(ctx, sagaID, orderID)
+	if err != nil {
+		log.Error("failed to get saga", sl.Err(err))
+		span.RecordError(err)
+		return handled(err)
+	}
+	if !slices.Contains(expected, saga.State()) {
+		log.Warn("failure reply for saga in another step", slog.String("step", saga.CurrentStep))
+		return nil
+	}
+	saga.ErrorReason = reason
+	saga.UpdatedAt = time.Now()
+	err = si.sagaRepo.UpdateSaga(ctx, saga)
+	if errors.Is(err, domain.ErrIllegalTransition) {
+		log.Warn("saga moved on before the failure was recorded")
+		return nil
+	}
+	if err != nil {
+		log.Error("failed to update saga", sl.Err(err))
+		span.RecordError(err)
+		return handled(err)
+	}
+	log.Warn("pending command failed, waiting for retry")
+	return nil
+}
+
+// handled maps errors that a redelivery cannot fix to nil, so the message is
+// acknowledged instead of retried.
+func handled(err error) error {
+	if errors.Is(err, domain.ErrIllegalTransition) || errors.Is(err, domain.ErrSagaNotFound) {
+		return nil
+	}
+	return err
+}
+
+// advance moves the saga to next and queues its pending command in one transaction.
+func (si *SagaInteractor) advance(ctx context.Context, log *slog.Logger, saga *domain.Saga, next domain.SagaState, eventType string, payload interface{}) error {
+	return si.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := si.transition(ctx, log, saga, next, eventType, payload); err != nil {
+			return err
+		}
+		return si.ExecuteSaga(ctx, saga)
+	})
+}
+
+func (si *SagaInteractor) failOrder(ctx context.Context, saga *domain.Saga) error {
+	command := events.OrderStatusUpdate{
+		OrderID: saga.OrderID,
+		Status:  "FAILED",
+		Reason:  saga.ErrorReason,
+	}
+	return si.enqueue(ctx, saga, command)
+}
+
+// cancelOrder asks order-service to move the cancelling order to CANCELLED.
+func (si *SagaInteractor) cancelOrder(ctx context.Context, saga *domain.Saga, reason string) error {
+	command := events.OrderStatusUpdate{
+		OrderID: saga.OrderID,
+		Status:  "CANCELLED",
+		Reason:  reason,
+	}
+	return si.enqueue(ctx, saga, command)
+}
+
+// enqueue writes command to the outbox, ordered with the saga's other commands.
+func (si *SagaInteractor) enqueue(ctx context.Context, saga *domain.Saga, command events.Event) error {
+	return si.outboxRepo.Enqueue(ctx, sagaCommandsTopic, saga.OrderID.String(), command)
+}
+
+// awaitReply marks command as the one the saga is waiting a reply for and starts the step deadline.
+func (si *SagaInteractor) awaitReply(saga *domain.Saga, command events.Event) error {
+	payload, err := json.Marshal(command)
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(si.stepTimeout)
+	saga.PendingCommandType = command.EventType()
+	saga.PendingCommand = string(payload)
+	saga.DeadlineAt = &deadline
+	saga.Attempts = 0
+	return nil
+}
+
+// pendingCommand decodes the command stored by awaitReply. It is kept at the
+// schema version current when it was stored, assumed to be the current one.
+func pendingCommand(saga *domain.Saga) (events.Event, error) {
+	version, err := events.SchemaVersion(saga.PendingCommandType)
+	if err != nil {
+		return nil, err
+	}
+	return events.DecodePayload(saga.PendingCommandType, version, json.RawMessage(saga.PendingCommand))
+}
+
+// findSaga looks the saga up by id, falling back to the order id for callers
+// that only know which order they are acting on.
+func (si *SagaInteractor) findSaga(ctx context.Context, sagaID, orderID uuid.UUID) (*domain.Saga, error) {
+	if sagaID != uuid.Nil {
+		return si.sagaRepo.Saga(ctx, sagaID)
+	}
+	return si.sagaRepo.SagaByOrderID(ctx, orderID)
+}
+
+func markReserved(saga *domain.Saga, products []events.Item) {
+	reserved := make(map[uuid.UUID]int, len(products))
+	for _, product := range products {
+		reserved[product.ProductID] += product.Quantity
+	}
+	for i := range saga.Items {
+		item := &saga.Items[i]
+		qty := reserved[item.ProductID]
+		if qty > item.Quantity {
+			qty = item.Quantity
+		}
+		item.ReservedQuantity = qty
+		reserved[item.ProductID] -= qty
+	}
+}
+
+func releaseCommandFor(saga *domain.Saga) events.ReleaseInventory {
+	sagaID, _ := uuid.Parse(saga.ID)
+	return events.ReleaseInventory{
+		OrderID: saga.OrderID,
+		SagaID:  sagaID,
+		Items:   saga.ReservedItems(),
+	}
+}
+
+func commitCommandFor(saga *domain.Saga) events.CommitInventory {
+	sagaID, _ := uuid.Parse(saga.ID)
+	return events.CommitInventory{
+		OrderID: saga.OrderID,
+		SagaID:  sagaID,
+	}
+}
+
+func clearPending(saga *domain.Saga) {
+	saga.PendingCommandType = ""
+	saga.PendingCommand = ""
+	saga.DeadlineAt = nil
+	saga.Attempts = 0
+}
+
+// transition moves the saga to next if the state machine allows it and records the step.
+func (si *SagaInteractor) transition(ctx context.Context, log *slog.Logger, saga *domain.Saga, next domain.SagaState, eventType string, payload interface{}) error {
+	current := saga.State()
+	log = log.With(
+		slog.String("from", string(current)),
+		slog.String("to", string(next)),
+		slog.String("event_type", eventType),
+	)
+	if !current.CanTransitionTo(next) {
+		log.Warn("illegal saga transition rejected")
+		return domain.ErrIllegalTransition
+	}
+	sagaID, err := uuid.Parse(saga.ID)
+	if err != nil {
+		log.Error("invalid saga id", sl.Err(err))
+		return err
+	}
+
+	step := newSagaStep(ctx, current, next, eventType, payload)
+	step.SagaID = sagaID
+	saga.UpdatedAt = time.Now()
+	if err := si.sagaRepo.TransitionSaga(ctx, saga, step); err != nil {
+		if errors.Is(err, domain.ErrIllegalTransition) {
+			log.Warn("saga state changed concurrently, transition rejected")
+			return err
+		}
+		log.Error("failed to save saga transition", sl.Err(err))
+		return err
+	}
+	saga.CurrentStep = string(next)
+	return nil
+}
+
+func newSagaStep(ctx context.Context, from, to domain.SagaState, eventType string, payload interface{}) *domain.SagaStep {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		body = []byte("null")
+	}
+	step := &domain.SagaStep{
+		FromState: string(from),
+		ToState:   string(to),
+		EventType: eventType,
+		Payload:   string(body),
+	}
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.HasTraceID() {
+		step.TraceID = spanCtx.TraceID().String()
+	}
+	return step
 }
@@ -0,0 +1,159 @@
+package saga
+
+import (
+	"context"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	"immxrtalbeast/order_microservices/saga-service/internal/domain"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestHandleInventoryHoldExpired(t *testing.T) {
+	tests := []struct {
+		name        string
+		step        domain.SagaState
+		captured    bool
+		wantStep    domain.SagaState
+		wantPending string
+		wantSent    []string
+	}{
+		{
+			name:        "reserved releases the stock",
+			step:        domain.StateInventoryReserved,
+			wantStep:    domain.StateInventoryReleasing,
+			wantPending: events.TypeReleaseInventory,
+			wantSent:    []string{events.TypeOrderStatusUpdate, events.TypeReleaseInventory},
+		},
+		{
+			name:        "authorized voids the payment",
+			step:        domain.StatePaymentAuthorized,
+			wantStep:    domain.StatePaymentReversing,
+			wantPending: events.TypeVoidPayment,
+			wantSent:    []string{events.TypeOrderStatusUpdate, events.TypeVoidPayment},
+		},
+		{
+			// Inventory rejected the commit of a paid order.
+			name:        "commit rejected refunds the payment",
+			step:        domain.StateInventoryCommitting,
+			captured:    true,
+			wantStep:    domain.StatePaymentReversing,
+			wantPending: events.TypeRefundPayment,
+			wantSent:    []string{events.TypeOrderStatusUpdate, events.TypeRefundPayment},
+		},
+		{name: "capture in flight waits for the commit", step: domain.StatePaymentCapturing},
+		{name: "completed", step: domain.StateCompleted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saga := domain.Saga{ID: uuid.NewString(), OrderID: uuid.New(), CurrentStep: string(tt.step), PaymentCaptured: tt.captured}
+			repo := &fakeSagaRepo{sagas: map[string]domain.Saga{saga.ID: saga}}
+			outbox := &fakeOutbox{}
+			si := newTestInteractor(repo, outbox)
+
+			event := events.InventoryHoldExpired{OrderID: saga.OrderID, SagaID: uuid.MustParse(saga.ID)}
+			if err := si.HandleInventoryHoldExpired(context.Background(), event); err != nil {
+				t.Fatalf("err = %v", err)
+			}
+
+			if !slices.Equal(outbox.sent, tt.wantSent) {
+				t.Errorf("enqueued %v, want %v", outbox.sent, tt.wantSent)
+			}
+			got := repo.sagas[saga.ID]
+			if tt.wantStep == "" {
+				if len(repo.transitions) != 0 || got.State() != tt.step {
+					t.Errorf("moved to %s, want the expiry ignored in %s", got.State(), tt.step)
+				}
+				return
+			}
+			if got.State() != tt.wantStep || got.PendingCommandType != tt.wantPending {
+				t.Errorf("got %s awaiting %q, want %s awaiting %q", got.State(), got.PendingCommandType, tt.wantStep, tt.wantPending)
+			}
+			if got.ErrorReason != "stock hold expired" {
+				t.Errorf("error reason = %q, want the expiry", got.ErrorReason)
+			}
+		})
+	}
+}
+
+// racingSagaRepo lets a reply move the saga right after it is read.
+type racingSagaRepo struct {
+	*fakeSagaRepo
+	reply domain.Saga
+}
+
+func (r *racingSagaRepo) Saga(ctx context.Context, sagaID uuid.UUID) (*domain.Saga, error) {
+	saga, err := r.fakeSagaRepo.Saga(ctx, sagaID)
+	r.sagas[r.reply.ID] = r.reply
+	return saga, err
+}
+
+func TestRecordFailureAfterReply(t *testing.T) {
+	saga := expiredSaga(domain.StateInventoryCommitting, events.TypeCommitInventory, 1)
+	committed := saga
+	committed.CurrentStep = string(domain.StateCompleted)
+	clearPending(&committed)
+	repo := &racingSagaRepo{fakeSagaRepo: &fakeSagaRepo{sagas: map[string]domain.Saga{saga.ID: saga}}, reply: committed}
+	outbox := &fakeOutbox{}
+	si := NewSagaInteractor(slog.New(slog.NewTextHandler(io.Discard, nil)), repo, outbox, fakeTransactor{}, testStepTimeout, testMaxRetries)
+
+	event := events.InventoryCommitFailed{OrderID: saga.OrderID, SagaID: uuid.MustParse(saga.ID), Reason: "db down"}
+	if err := si.HandleInventoryCommitFailed(context.Background(), event); err != nil {
+		t.Fatalf("err = %v, want the late failure dropped", err)
+	}
+	if got := repo.sagas[saga.ID]; got.State() != domain.StateCompleted || got.ErrorReason != "" {
+		t.Errorf("got %s with reason %q, want the reply kept", got.State(), got.ErrorReason)
+	}
+}
+
+func TestHandleProductsReservedAfterCancel(t *testing.T) {
+	tests := []struct {
+		name     string
+		released bool
+		wantStep domain.SagaState
+	}{
+		{name: "release still in flight", wantStep: domain.StateCancelling},
+		// The release found nothing reserved yet and the order is cancelled.
+		{name: "release already confirmed", released: true, wantStep: domain.StateCancelled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := uuid.New()
+			saga := domain.Saga{
+				ID:          uuid.NewString(),
+				OrderID:     uuid.New(),
+				CurrentStep: string(domain.StateOrderCreated),
+				Items:       []domain.SagaItem{{ProductID: product, Quantity: 2}},
+			}
+			sagaID := uuid.MustParse(saga.ID)
+			repo := &fakeSagaRepo{sagas: map[string]domain.Saga{saga.ID: saga}}
+			outbox := &fakeOutbox{}
+			si := newTestInteractor(repo, outbox)
+			ctx := context.Background()
+
+			if err := si.HandleCancelOrderCommand(ctx, events.CancelOrder{OrderID: saga.OrderID, SagaID: sagaID}); err != nil {
+				t.Fatalf("cancel: %v", err)
+			}
+			if tt.released {
+				if err := si.HandleInventoryReleased(ctx, events.InventoryReleased{OrderID: saga.OrderID, SagaID: sagaID}); err != nil {
+					t.Fatalf("released: %v", err)
+				}
+			}
+			reserved := events.InventoryReserved{OrderID: saga.OrderID, SagaID: sagaID, Items: []events.Item{{ProductID: product, Quantity: 2}}}
+			if err := si.HandleProductsReserved(ctx, reserved); err != nil {
+				t.Fatalf("reserved: %v", err)
+			}
+
+			release, ok := outbox.queued[len(outbox.queued)-1].(events.ReleaseInventory)
+			if !ok || len(release.Items) != 1 || release.Items[0].ProductID != product || release.Items[0].Quantity != 2 {
+				t.Errorf("last enqueued %v, want a release of %v", outbox.queued[len(outbox.queued)-1], reserved.Items)
+			}
+			if got := repo.sagas[saga.ID]; got.State() != tt.wantStep {
+				t.Errorf("step = %s, want %s", got.State(), tt.wantStep)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	"immxrtalbeast/order_microservices/saga-service/internal/domain"
+	"immxrtalbeast/order_microservices/saga-service/internal/lib/logger/sl"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+type stepTimeoutPayload struct {
+	Command  string `json:"command"`
+	Attempts int    `json:"attempts"`
+}
+
+// RunSweeper periodically looks for sagas stuck past their step deadline
+// until ctx is cancelled.
+func (si *SagaInteractor) RunSweeper(ctx context.Context, interval time.Duration, batchSize int) {
+	si.log.Info("saga sweeper started", slog.Duration("interval", interval))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			si.log.Info("saga sweeper stopped")
+			return
+		case <-ticker.C:
+			si.SweepExpiredSagas(ctx, batchSize)
+		}
+	}
+}
+
+func (si *SagaInteractor) SweepExpiredSagas(ctx context.Context, batchSize int) {
+	const op = "service.saga.sweep"
+	log := si.log.With(
+		slog.String("op", op),
+	)
+	sagas, err := si.sagaRepo.ClaimExpiredSagas(ctx, time.Now(), si.stepTimeout, batchSize)
+	if err != nil {
+		log.Error("failed to claim expired sagas", sl.Err(err))
+		return
+	}
+	if len(sagas) == 0 {
+		return
+	}
+	log.Info("expired sagas claimed", slog.Int("count", len(sagas)))
+	for i := range sagas {
+		si.handleExpiredSaga(ctx, &sagas[i])
+	}
+}
+
+func (si *SagaInteractor) handleExpiredSaga(ctx context.Context, saga *domain.Saga) {
+	const op = "service.saga.handleExpired"
+	log := si.log.With(
+		slog.String("op", op),
+		slog.String("sagaID", saga.ID),
+		slog.String("step", saga.CurrentStep),
+		slog.String("command", saga.PendingCommandType),
+		slog.Int("attempts", saga.Attempts),
+	)
+	tracer := otel.Tracer("saga-service")
+	ctx, span := tracer.Start(ctx, "SagaService.HandleExpiredSaga")
+	defer span.End()
+
+	if saga.Attempts < si.maxRetries {
+		deadline := time.Now().Add(si.stepTimeout)
+		saga.Attempts++
+		saga.DeadlineAt = &deadline
+		saga.UpdatedAt = time.Now()
+		err := si.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := si.sagaRepo.UpdateSaga(ctx, saga); err != nil {
+				return err
+			}
+			return si.ExecuteSaga(ctx, saga)
+		})
+		if errors.Is(err, domain.ErrIllegalTransition) {
+			log.Info("saga moved on since it was claimed, retry skipped")
+			return
+		}
+		if err != nil {
+			log.Error("failed to retry saga command", sl.Err(err))
+			span.RecordError(err)
+			return
+		}
+		log.Warn("saga step timed out, retrying command")
+		return
+	}
+
+	log.Warn("saga step retries exhausted, compensating")
+	sagaID, err := uuid.Parse(saga.ID)
+	if err != nil {
+		log.Error("invalid saga id", sl.Err(err))
+		span.RecordError(err)
+		return
+	}
+	claimed := saga
+	saga, err = si.sagaRepo.Saga(ctx, sagaID)
+	if err != nil {
+		log.Error("failed to load saga", sl.Err(err))
+		span.RecordError(err)
+		return
+	}
+	if saga.CurrentStep != claimed.CurrentStep || saga.PendingCommandType != claimed.PendingCommandType {
+		log.Info("saga moved on while being swept")
+		return
+	}
+
+	payload := stepTimeoutPayload{Command: saga.PendingCommandType, Attempts: saga.Attempts}
+	saga.ErrorReason = fmt.Sprintf("%s timed out after %d retries", saga.PendingCommandType, saga.Attempts)
+	if saga.Kind == domain.KindRefund {
+		if err := si.expireRefund(ctx, log, saga, payload); err != nil {
+			span.RecordError(err)
+			return
+		}
+		log.Error("refund saga step never confirmed, saga closed")
+		return
+	}
+	if saga.PendingCommandType == events.TypeReleaseInventory {
+		clearPending(saga)
+		if saga.State() == domain.StateCancelling {
+			// The customer asked to cancel, so the order is cancelled even
+			// though the stock has to be released by hand.
+			err = si.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+				if err := si.transition(ctx, log, saga, domain.StateCancelled, "SagaStepTimeout", payload); err != nil {
+					return err
+				}
+				return si.cancelOrder(ctx, saga, saga.ErrorReason)
+			})
+		} else {
+			err = si.transition(ctx, log, saga, domain.StateCompensated, "SagaStepTimeout", payload)
+		}
+		if err != nil {
+			span.RecordError(err)
+			return
+		}
+		log.Error("inventory release never confirmed, saga closed")
+		return
+	}
+
+	if saga.PendingCommandType == events.TypeVoidPayment || saga.PendingCommandType == events.TypeRefundPayment {
+		// The payment has to be reversed by hand; the stock is released
+		// anyway and the order was already failed or is being cancelled.
+		if err := si.awaitReply(saga, releaseCommandFor(saga)); err != nil {
+			log.Error("failed to prepare release command", sl.Err(err))
+			span.RecordError(err)
+			return
+		}
+		if err := si.advance(ctx, log, saga, releaseStateFor(saga), "SagaStepTimeout", payload); err != nil {
+			span.RecordError(err)
+			return
+		}
+		log.Error("payment reversal never confirmed, releasing stock")
+		return
+	}
+
+	// A payment may exist from the authorization step on and is reversed
+	// before the stock is released.
+	next, pending := domain.StateInventoryReleasing, events.Event(releaseCommandFor(saga))
+	if paymentStarted(saga.State()) {
+		next, pending = domain.StatePaymentReversing, reversalCommandFor(saga)
+	}
+	if err := si.awaitReply(saga, pending); err != nil {
+		log.Error("failed to prepare compensation command", sl.Err(err))
+		span.RecordError(err)
+		return
+	}
+	err = si.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := si.transition(ctx, log, saga, next, "SagaStepTimeout", payload); err != nil {
+			return err
+		}
+		if err := si.failOrder(ctx, saga); err != nil {
+			return err
+		}
+		return si.ExecuteSaga(ctx, saga)
+	})
+	if err != nil {
+		span.RecordError(err)
+		return
+	}
+	log.Info("timed out saga compensating")
+}
+
+// paymentStarted reports whether a saga in state may hold a payment. Sagas
+// started before the payment step commit without one; reversing it is then a
+// void of nothing.
+func paymentStarted(state domain.SagaState) bool {
+	return state == domain.StatePaymentProcessing || state == domain.StatePaymentCapturing || state == domain.StateInventoryCommitting
+}
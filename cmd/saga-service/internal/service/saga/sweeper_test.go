@@ -0,0 +1,241 @@
+package saga
+
+import (
+	"context"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	"immxrtalbeast/order_microservices/saga-service/internal/domain"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	testStepTimeout = 30 * time.Second
+	testMaxRetries  = 3
+)
+
+// fakeSagaRepo keeps the sweeper's writes in memory. Methods the sweeper
+// does not use are left to the embedded interface.
+type fakeSagaRepo struct {
+	domain.SagaRepository
+	sagas       map[string]domain.Saga
+	expired     []domain.Saga
+	claimNow    time.Time
+	claimLease  time.Duration
+	claimLimit  int
+	updated     []domain.Saga
+	transitions []domain.SagaStep
+}
+
+func (r *fakeSagaRepo) Saga(ctx context.Context, sagaID uuid.UUID) (*domain.Saga, error) {
+	saga, ok := r.sagas[sagaID.String()]
+	if !ok {
+		return nil, domain.ErrSagaNotFound
+	}
+	return &saga, nil
+}
+
+func (r *fakeSagaRepo) ClaimExpiredSagas(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.Saga, error) {
+	r.claimNow, r.claimLease, r.claimLimit = now, lease, limit
+	claimed := r.expired
+	r.expired = nil
+	return claimed, nil
+}
+
+// UpdateSaga is conditional like the real one: a saga that left the step or
+// command it was read with is not overwritten.
+func (r *fakeSagaRepo) UpdateSaga(ctx context.Context, saga *domain.Saga) error {
+	stored := r.sagas[saga.ID]
+	if stored.CurrentStep != saga.CurrentStep || stored.PendingCommandType != saga.PendingCommandType {
+		return domain.ErrIllegalTransition
+	}
+	r.updated = append(r.updated, *saga)
+	r.sagas[saga.ID] = *saga
+	return nil
+}
+
+func (r *fakeSagaRepo) TransitionSaga(ctx context.Context, saga *domain.Saga, step *domain.SagaStep) error {
+	r.transitions = append(r.transitions, *step)
+	stored := *saga
+	stored.CurrentStep = step.ToState
+	r.sagas[saga.ID] = stored
+	return nil
+}
+
+// fakeOutbox records the events enqueued and their types, in order.
+type fakeOutbox struct {
+	sent   []string
+	queued []events.Event
+}
+
+func (o *fakeOutbox) Enqueue(ctx context.Context, topic, aggregateID string, event events.Event) error {
+	o.sent = append(o.sent, event.EventType())
+	o.queued = append(o.queued, event)
+	return nil
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func newTestInteractor(repo domain.SagaRepository, outbox *fakeOutbox) *SagaInteractor {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewSagaInteractor(log, repo, outbox, fakeTransactor{}, testStepTimeout, testMaxRetries)
+}
+
+func expiredSaga(step domain.SagaState, command string, attempts int) domain.Saga {
+	deadline := time.Now().Add(-time.Second)
+	return domain.Saga{
+		ID:                 uuid.NewString(),
+		CurrentStep:        string(step),
+		OrderID:            uuid.New(),
+		PendingCommandType: command,
+		PendingCommand:     `{"order_id":"` + uuid.NewString() + `"}`,
+		DeadlineAt:         &deadline,
+		Attempts:           attempts,
+	}
+}
+
+func TestSweepExpiredSagasClaimsForOneStepTimeout(t *testing.T) {
+	saga := expiredSaga(domain.StateOrderCreated, "InventoryReserveItemsCommand", 0)
+	repo := &fakeSagaRepo{sagas: map[string]domain.Saga{saga.ID: saga}, expired: []domain.Saga{saga}}
+	si := newTestInteractor(repo, &fakeOutbox{})
+
+	before := time.Now()
+	si.SweepExpiredSagas(context.Background(), 10)
+
+	if repo.claimLease != testStepTimeout {
+		t.Errorf("claim lease = %s, want the step timeout %s", repo.claimLease, testStepTimeout)
+	}
+	if repo.claimLimit != 10 {
+		t.Errorf("claim limit = %d, want 10", repo.claimLimit)
+	}
+	if repo.claimNow.Before(before) {
+		t.Errorf("claimed as of %s, before the sweep started at %s", repo.claimNow, before)
+	}
+	if len(repo.updated) != 1 {
+		t.Fatalf("updated %d sagas, want the claimed one", len(repo.updated))
+	}
+}
+
+func TestHandleExpiredSaga(t *testing.T) {
+	tests := []struct {
+		name        string
+		step        domain.SagaState
+		command     string
+		attempts    int
+		moved       bool
+		wantRetry   bool
+		wantStep    domain.SagaState
+		wantPending string
+		wantReason  string
+		wantSent    []string
+	}{
+		{
+			name:      "first timeout retries",
+			step:      domain.StateOrderCreated,
+			command:   "InventoryReserveItemsCommand",
+			wantRetry: true,
+			wantSent:  []string{"InventoryReserveItemsCommand"},
+		},
+		{
+			name:      "last retry",
+			step:      domain.StateOrderCreated,
+			command:   "InventoryReserveItemsCommand",
+			attempts:  testMaxRetries - 1,
+			wantRetry: true,
+			wantSent:  []string{"InventoryReserveItemsCommand"},
+		},
+		{
+			name:        "retries exhausted releases the stock",
+			step:        domain.StateOrderCreated,
+			command:     "InventoryReserveItemsCommand",
+			attempts:    testMaxRetries,
+			wantStep:    domain.StateInventoryReleasing,
+			wantPending: "ReleaseInventoryCommand",
+			wantReason:  "InventoryReserveItemsCommand timed out after 3 retries",
+			wantSent:    []string{"OrderStatusUpdateCommand", "ReleaseInventoryCommand"},
+		},
+		{
+			name:       "release never confirmed closes the saga",
+			step:       domain.StateInventoryReleasing,
+			command:    "ReleaseInventoryCommand",
+			attempts:   testMaxRetries,
+			wantStep:   domain.StateCompensated,
+			wantReason: "ReleaseInventoryCommand timed out after 3 retries",
+		},
+		{name: "reply arrived before the retry", step: domain.StateOrderCreated, command: "InventoryReserveItemsCommand", moved: true},
+		{name: "reply arrived after the claim", step: domain.StateOrderCreated, command: "InventoryReserveItemsCommand", attempts: testMaxRetries, moved: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saga := expiredSaga(tt.step, tt.command, tt.attempts)
+			stored := saga
+			if tt.moved {
+				stored.CurrentStep = string(domain.StateInventoryReserved)
+				clearPending(&stored)
+			}
+			repo := &fakeSagaRepo{sagas: map[string]domain.Saga{saga.ID: stored}}
+			outbox := &fakeOutbox{}
+			si := newTestInteractor(repo, outbox)
+
+			before := time.Now()
+			si.handleExpiredSaga(context.Background(), &saga)
+
+			if !slices.Equal(outbox.sent, tt.wantSent) {
+				t.Errorf("enqueued %v, want %v", outbox.sent, tt.wantSent)
+			}
+
+			switch {
+			case tt.wantRetry:
+				if len(repo.updated) != 1 || len(repo.transitions) != 0 {
+					t.Fatalf("updates = %d, transitions = %d, want one update", len(repo.updated), len(repo.transitions))
+				}
+				got := repo.updated[0]
+				if got.State() != tt.step {
+					t.Errorf("step = %s, want %s", got.State(), tt.step)
+				}
+				if got.Attempts != tt.attempts+1 {
+					t.Errorf("attempts = %d, want %d", got.Attempts, tt.attempts+1)
+				}
+				if got.DeadlineAt == nil || got.DeadlineAt.Before(before.Add(testStepTimeout)) {
+					t.Errorf("deadline = %v, want a fresh step timeout from now", got.DeadlineAt)
+				}
+				if got.PendingCommandType != tt.command {
+					t.Errorf("pending command = %q, want %q kept for the retry", got.PendingCommandType, tt.command)
+				}
+			case tt.moved:
+				if len(repo.updated) != 0 || len(repo.transitions) != 0 {
+					t.Errorf("updates = %d, transitions = %d, want the saga left to its reply", len(repo.updated), len(repo.transitions))
+				}
+			default:
+				if len(repo.updated) != 0 || len(repo.transitions) != 1 {
+					t.Fatalf("updates = %d, transitions = %d, want one transition", len(repo.updated), len(repo.transitions))
+				}
+				got := repo.sagas[saga.ID]
+				step := repo.transitions[0]
+				if got.State() != tt.wantStep || step.FromState != string(tt.step) || step.EventType != "SagaStepTimeout" {
+					t.Errorf("step = %s -> %s on %s, want %s -> %s on SagaStepTimeout", step.FromState, got.State(), step.EventType, tt.step, tt.wantStep)
+				}
+				if got.ErrorReason != tt.wantReason {
+					t.Errorf("error reason = %q, want %q", got.ErrorReason, tt.wantReason)
+				}
+				if got.PendingCommandType != tt.wantPending {
+					t.Errorf("pending command = %q, want %q", got.PendingCommandType, tt.wantPending)
+				}
+				if tt.wantPending != "" && got.Attempts != 0 {
+					t.Errorf("attempts = %d, want a new step to start from 0", got.Attempts)
+				}
+				if tt.wantPending == "" && got.DeadlineAt != nil {
+					t.Errorf("deadline %v left on a closed saga", got.DeadlineAt)
+				}
+			}
+		})
+	}
+}
@@ -2,9 +2,12 @@ package psql
 
 import (
 	"context"
+	"errors"
 	"immxrtalbeast/order_microservices/saga-service/internal/domain"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 )
 
@@ -16,12 +19,27 @@ func NewSagaRepository(db *gorm.DB) *SagaRepository {
 	return &SagaRepository{db: db}
 }
 
-func (r *SagaRepository) SaveSaga(ctx context.Context, saga *domain.Saga) (uuid.UUID, error) {
-	err := r.db.WithContext(ctx).Create(&saga).Error
-	if err != nil {
-		return uuid.Nil, err
-	}
-	sagaID, err := uuid.Parse(saga.ID)
+func (r *SagaRepository) SaveSaga(ctx context.Context, saga *domain.Saga, step *domain.SagaStep) (uuid.UUID, error) {
+	var sagaID uuid.UUID
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&saga).Error; err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return domain.ErrSagaExists
+			}
+			return err
+		}
+		id, err := uuid.Parse(saga.ID)
+		if err != nil {
+			return err
+		}
+		step.SagaID = id
+		if err := tx.Create(step).Error; err != nil {
+			return err
+		}
+		sagaID = id
+		return nil
+	})
 	if err != nil {
 		return uuid.Nil, err
 	}
@@ -30,18 +48,116 @@ func (r *SagaRepository) SaveSaga(ctx context.Context, saga *domain.Saga) (uuid.
 
 func (r *SagaRepository) Saga(ctx context.Context, sagaID uuid.UUID) (*domain.Saga, error) {
 	var saga domain.Saga
-	err := r.db.WithContext(ctx).Where("id = ?", sagaID).First(&saga).Error
+	err := conn(ctx, r.db).Preload("Items").Where("id = ?", sagaID).First(&saga).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrSagaNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 	return &saga, nil
 }
 
+func (r *SagaRepository) SagaByOrderID(ctx context.Context, orderID uuid.UUID) (*domain.Saga, error) {
+	var saga domain.Saga
+	err := conn(ctx, r.db).Preload("Items").Where("order_id = ? AND kind = ?", orderID, domain.KindOrder).First(&saga).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrSagaNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &saga, nil
+}
+
+// UpdateSaga saves the saga as long as it is still in the step and awaiting
+// the command it was read with, so a reply that moved the saga meanwhile is
+// not overwritten.
 func (r *SagaRepository) UpdateSaga(ctx context.Context, saga *domain.Saga) error {
-	result := r.db.WithContext(ctx).Model(&domain.Saga{}).
-		Where("id = ?", saga.ID).
-		Omit("id").
+	result := conn(ctx, r.db).Model(&domain.Saga{}).
+		Where("id = ? AND current_step = ? AND pending_command_type = ?", saga.ID, saga.CurrentStep, saga.PendingCommandType).
+		Omit("id", "Items").
 		Updates(&saga)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrIllegalTransition
+	}
+	return nil
+}
+
+// TransitionSaga moves the saga to step.ToState and appends the step to the log.
+// The update only applies while the saga is still in step.FromState, so two
+// handlers racing on the same saga cannot both win.
+func (r *SagaRepository) TransitionSaga(ctx context.Context, saga *domain.Saga, step *domain.SagaStep) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Saga{}).
+			Where("id = ? AND current_step = ?", saga.ID, step.FromState).
+			Updates(map[string]interface{}{
+				"current_step":         step.ToState,
+				"error_reason":         saga.ErrorReason,
+				"pending_command_type": saga.PendingCommandType,
+				"pending_command":      gorm.Expr("NULLIF(?, '')::jsonb", saga.PendingCommand),
+				"deadline_at":          saga.DeadlineAt,
+				"attempts":             saga.Attempts,
+				"total_amount":         saga.Total.Amount,
+				"total_currency":       saga.Total.Currency,
+				"payment_id":           saga.PaymentID,
+				"payment_captured":     saga.PaymentCaptured,
+				"complete_requested":   saga.CompleteRequested,
+				"cancel_requested":     saga.CancelRequested,
+				"updated_at":           saga.UpdatedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrIllegalTransition
+		}
+		for _, item := range saga.Items {
+			if err := tx.Model(&domain.SagaItem{}).
+				Where("id = ?", item.ID).
+				Update("reserved_quantity", item.ReservedQuantity).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(step).Error
+	})
+}
+
+func (r *SagaRepository) RequestCompletion(ctx context.Context, saga *domain.Saga) error {
+	result := conn(ctx, r.db).Model(&domain.Saga{}).
+		Where("id = ? AND current_step = ?", saga.ID, saga.CurrentStep).
+		Updates(map[string]interface{}{
+			"complete_requested": true,
+			"updated_at":         time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrIllegalTransition
+	}
+	saga.CompleteRequested = true
+	return nil
+}
 
-	return result.Error
+// ClaimExpiredSagas picks sagas whose step deadline has passed and pushes their
+// deadline forward by lease, so concurrent sweepers in other replicas skip them.
+func (r *SagaRepository) ClaimExpiredSagas(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.Saga, error) {
+	var sagas []domain.Saga
+	err := conn(ctx, r.db).Raw(`
+		UPDATE sagas SET deadline_at = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM sagas
+			WHERE deadline_at IS NOT NULL AND deadline_at <= ?
+			ORDER BY deadline_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		now.Add(lease), now, now, limit,
+	).Scan(&sagas).Error
+	return sagas, err
 }
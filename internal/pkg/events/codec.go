@@ -0,0 +1,97 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"immxrtalbeast/order_microservices/internal/pkg/events/eventspb"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Content types of Kafka message values, carried in the Content-Type header.
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
+
+var ErrUnsupportedContentType = errors.New("unsupported content type")
+
+// Marshal encodes env as a Kafka message value of contentType.
+func Marshal(env Envelope, contentType string) ([]byte, error) {
+	switch contentType {
+	case ContentTypeJSON:
+		return json.Marshal(env)
+	case ContentTypeProtobuf:
+		return marshalProto(env)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedContentType, contentType)
+	}
+}
+
+// Unmarshal decodes a Kafka message value by its content type. Messages
+// published before the Content-Type header was introduced are JSON.
+func Unmarshal(data []byte, contentType, eventType string) (Envelope, error) {
+	switch contentType {
+	case "", ContentTypeJSON:
+		return Parse(data, eventType)
+	case ContentTypeProtobuf:
+		return unmarshalProto(data)
+	default:
+		return Envelope{}, fmt.Errorf("%w: %s", ErrUnsupportedContentType, contentType)
+	}
+}
+
+func marshalProto(env Envelope) ([]byte, error) {
+	s, ok := registry[env.Type]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownType, env.Type)
+	}
+	event, err := env.Decode()
+	if err != nil {
+		return nil, err
+	}
+	payload, err := proto.Marshal(s.toProto(event))
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s: %w", env.Type, err)
+	}
+	return proto.Marshal(&eventspb.Envelope{
+		MessageId:     env.MessageID,
+		Type:          env.Type,
+		SchemaVersion: int32(s.version),
+		OccurredAt:    timestamppb.New(env.OccurredAt),
+		Producer:      env.Producer,
+		CorrelationId: env.CorrelationID,
+		Payload:       payload,
+	})
+}
+
+// unmarshalProto converts the payload to JSON, so Decode and the upcasters
+// handle both content types the same way.
+func unmarshalProto(data []byte) (Envelope, error) {
+	var m eventspb.Envelope
+	if err := proto.Unmarshal(data, &m); err != nil {
+		return Envelope{}, fmt.Errorf("parse envelope: %w", err)
+	}
+	env := Envelope{
+		MessageID:     m.GetMessageId(),
+		Type:          m.GetType(),
+		SchemaVersion: int(m.GetSchemaVersion()),
+		OccurredAt:    m.GetOccurredAt().AsTime(),
+		Producer:      m.GetProducer(),
+		CorrelationID: m.GetCorrelationId(),
+	}
+	s, ok := registry[env.Type]
+	if !ok {
+		return env, nil
+	}
+	event, err := s.fromProto(m.GetPayload())
+	if err != nil {
+		return Envelope{}, fmt.Errorf("decode %s: %w", env.Type, err)
+	}
+	if env.Payload, err = json.Marshal(event); err != nil {
+		return Envelope{}, fmt.Errorf("decode %s: %w", env.Type, err)
+	}
+	return env, nil
+}
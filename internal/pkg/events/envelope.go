@@ -0,0 +1,76 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Envelope wraps every message published to Kafka.
+type Envelope struct {
+	MessageID     string          `json:"message_id"`
+	Type          string          `json:"type"`
+	SchemaVersion int             `json:"schema_version"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Producer      string          `json:"producer"`
+	CorrelationID string          `json:"correlation_id"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// NewEnvelope wraps event at its current schema version.
+func NewEnvelope(messageID, producer, correlationID string, event Event) (Envelope, error) {
+	version, err := SchemaVersion(event.EventType())
+	if err != nil {
+		return Envelope{}, err
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("marshal %s: %w", event.EventType(), err)
+	}
+	return Envelope{
+		MessageID:     messageID,
+		Type:          event.EventType(),
+		SchemaVersion: version,
+		OccurredAt:    time.Now().UTC(),
+		Producer:      producer,
+		CorrelationID: correlationID,
+		Payload:       payload,
+	}, nil
+}
+
+// Parse reads an envelope from a Kafka message value. Values published before
+// envelopes were introduced are bare payloads; they are treated as version 1
+// of eventType, taken from the Event-Type header.
+func Parse(data []byte, eventType string) (Envelope, error) {
+	var env Envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return Envelope{}, fmt.Errorf("parse envelope: %w", err)
+	}
+	if env.Type == "" || env.Payload == nil {
+		return Envelope{Type: eventType, SchemaVersion: 1, Payload: data}, nil
+	}
+	return env, nil
+}
+
+// Decode returns the payload as its registered Go type, upcasting older
+// schema versions.
+func (e Envelope) Decode() (Event, error) {
+	return DecodePayload(e.Type, e.SchemaVersion, e.Payload)
+}
+
+type correlationKey struct{}
+
+// WithCorrelationID stores id so events produced while handling a message
+// carry the correlation ID of that message.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
@@ -0,0 +1,11 @@
+module immxrtalbeast/order_microservices/internal/pkg/events
+
+go 1.24.5
+
+require (
+	github.com/google/uuid v1.6.0
+	google.golang.org/protobuf v1.36.9
+	immxrtalbeast/order_microservices/internal/pkg/money v0.0.0-00010101000000-000000000000
+)
+
+replace immxrtalbeast/order_microservices/internal/pkg/money => ../money
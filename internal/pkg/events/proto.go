@@ -0,0 +1,689 @@
+package events
+
+import (
+	"fmt"
+	"immxrtalbeast/order_microservices/internal/pkg/events/eventspb"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func itemsToProto(items []Item) []*eventspb.Item {
+	out := make([]*eventspb.Item, len(items))
+	for i, item := range items {
+		out[i] = &eventspb.Item{
+			ProductId: item.ProductID.String(),
+			Quantity:  int32(item.Quantity),
+			Name:      item.Name,
+			Volume:    int32(item.Volume),
+			Price:     moneyToProto(item.Price),
+		}
+		for _, modifier := range item.Modifiers {
+			out[i].Modifiers = append(out[i].Modifiers, &eventspb.ItemModifier{
+				ModifierId: modifier.ModifierID.String(),
+				Name:       modifier.Name,
+				PriceDelta: moneyToProto(modifier.PriceDelta),
+			})
+		}
+	}
+	return out
+}
+
+func itemsFromProto(items []*eventspb.Item) ([]Item, error) {
+	out := make([]Item, len(items))
+	for i, item := range items {
+		productID, err := parseID("product_id", item.GetProductId())
+		if err != nil {
+			return nil, err
+		}
+		out[i] = Item{
+			ProductID: productID,
+			Quantity:  int(item.GetQuantity()),
+			Name:      item.GetName(),
+			Volume:    int(item.GetVolume()),
+			Price:     moneyFromProto(item.GetPrice(), item.GetPriceUnits()),
+		}
+		for _, modifier := range item.GetModifiers() {
+			modifierID, err := parseID("modifier_id", modifier.GetModifierId())
+			if err != nil {
+				return nil, err
+			}
+			out[i].Modifiers = append(out[i].Modifiers, ItemModifier{
+				ModifierID: modifierID,
+				Name:       modifier.GetName(),
+				PriceDelta: moneyFromProto(modifier.GetPriceDelta(), 0),
+			})
+		}
+	}
+	return out, nil
+}
+
+func moneyToProto(m money.Money) *eventspb.Money {
+	if m == (money.Money{}) {
+		return nil
+	}
+	return &eventspb.Money{Amount: m.Amount, Currency: m.Currency}
+}
+
+// moneyFromProto reads m, falling back to an amount in whole units of the
+// default currency sent by producers that predate Money.
+func moneyFromProto(m *eventspb.Money, units int64) money.Money {
+	if m == nil {
+		if units == 0 {
+			return money.Money{}
+		}
+		return money.FromMajor(units, money.DefaultCurrency)
+	}
+	return money.New(m.GetAmount(), m.GetCurrency())
+}
+
+func parseID(field, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid %s: %w", field, err)
+	}
+	return id, nil
+}
+
+// sagaIDs parses the order and saga IDs most saga messages carry.
+func sagaIDs(orderID, sagaID string) (uuid.UUID, uuid.UUID, error) {
+	order, err := parseID("order_id", orderID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	saga, err := parseID("saga_id", sagaID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return order, saga, nil
+}
+
+func orderCreatedToProto(e OrderCreated) *eventspb.OrderCreated {
+	return &eventspb.OrderCreated{
+		OrderId: e.OrderID.String(),
+		UserId:  e.UserID.String(),
+		Items:   itemsToProto(e.Items),
+	}
+}
+
+func orderCreatedFromProto(m *eventspb.OrderCreated) (OrderCreated, error) {
+	orderID, err := parseID("order_id", m.GetOrderId())
+	if err != nil {
+		return OrderCreated{}, err
+	}
+	userID, err := parseID("user_id", m.GetUserId())
+	if err != nil {
+		return OrderCreated{}, err
+	}
+	items, err := itemsFromProto(m.GetItems())
+	if err != nil {
+		return OrderCreated{}, err
+	}
+	return OrderCreated{OrderID: orderID, UserID: userID, Items: items}, nil
+}
+
+func orderReadyToProto(e OrderReady) *eventspb.OrderReady {
+	return &eventspb.OrderReady{OrderId: e.OrderID.String()}
+}
+
+func orderReadyFromProto(m *eventspb.OrderReady) (OrderReady, error) {
+	orderID, err := parseID("order_id", m.GetOrderId())
+	if err != nil {
+		return OrderReady{}, err
+	}
+	return OrderReady{OrderID: orderID}, nil
+}
+
+func orderCompletedToProto(e OrderCompleted) *eventspb.OrderCompleted {
+	return &eventspb.OrderCompleted{OrderId: e.OrderID.String()}
+}
+
+func orderCompletedFromProto(m *eventspb.OrderCompleted) (OrderCompleted, error) {
+	orderID, err := parseID("order_id", m.GetOrderId())
+	if err != nil {
+		return OrderCompleted{}, err
+	}
+	return OrderCompleted{OrderID: orderID}, nil
+}
+
+func orderStatusChangedToProto(e OrderStatusChanged) *eventspb.OrderStatusChanged {
+	return &eventspb.OrderStatusChanged{
+		OrderId:   e.OrderID.String(),
+		From:      e.From,
+		To:        e.To,
+		ChangedAt: timestamppb.New(e.ChangedAt),
+	}
+}
+
+func orderStatusChangedFromProto(m *eventspb.OrderStatusChanged) (OrderStatusChanged, error) {
+	orderID, err := parseID("order_id", m.GetOrderId())
+	if err != nil {
+		return OrderStatusChanged{}, err
+	}
+	return OrderStatusChanged{
+		OrderID:   orderID,
+		From:      m.GetFrom(),
+		To:        m.GetTo(),
+		ChangedAt: m.GetChangedAt().AsTime(),
+	}, nil
+}
+
+func orderStatusUpdateToProto(e OrderStatusUpdate) *eventspb.OrderStatusUpdate {
+	return &eventspb.OrderStatusUpdate{OrderId: e.OrderID.String(), Status: e.Status, Reason: e.Reason}
+}
+
+func orderStatusUpdateFromProto(m *eventspb.OrderStatusUpdate) (OrderStatusUpdate, error) {
+	orderID, err := parseID("order_id", m.GetOrderId())
+	if err != nil {
+		return OrderStatusUpdate{}, err
+	}
+	return OrderStatusUpdate{OrderID: orderID, Status: m.GetStatus(), Reason: m.GetReason()}, nil
+}
+
+func reserveInventoryToProto(e ReserveInventory) *eventspb.ReserveInventory {
+	return &eventspb.ReserveInventory{
+		OrderId: e.OrderID.String(),
+		SagaId:  e.SagaID.String(),
+		Items:   itemsToProto(e.Items),
+	}
+}
+
+func reserveInventoryFromProto(m *eventspb.ReserveInventory) (ReserveInventory, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return ReserveInventory{}, err
+	}
+	items, err := itemsFromProto(m.GetItems())
+	if err != nil {
+		return ReserveInventory{}, err
+	}
+	return ReserveInventory{OrderID: orderID, SagaID: sagaID, Items: items}, nil
+}
+
+func inventoryReservedToProto(e InventoryReserved) *eventspb.InventoryReserved {
+	return &eventspb.InventoryReserved{
+		OrderId: e.OrderID.String(),
+		SagaId:  e.SagaID.String(),
+		Items:   itemsToProto(e.Items),
+		Total:   moneyToProto(e.Total),
+	}
+}
+
+func inventoryReservedFromProto(m *eventspb.InventoryReserved) (InventoryReserved, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return InventoryReserved{}, err
+	}
+	items, err := itemsFromProto(m.GetItems())
+	if err != nil {
+		return InventoryReserved{}, err
+	}
+	return InventoryReserved{
+		OrderID: orderID,
+		SagaID:  sagaID,
+		Items:   items,
+		Total:   moneyFromProto(m.GetTotal(), m.GetTotalSum()),
+	}, nil
+}
+
+func inventoryReserveFailedToProto(e InventoryReserveFailed) *eventspb.InventoryReserveFailed {
+	return &eventspb.InventoryReserveFailed{
+		OrderId: e.OrderID.String(),
+		SagaId:  e.SagaID.String(),
+		Items:   itemsToProto(e.Items),
+		Reason:  e.Reason,
+	}
+}
+
+func inventoryReserveFailedFromProto(m *eventspb.InventoryReserveFailed) (InventoryReserveFailed, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return InventoryReserveFailed{}, err
+	}
+	items, err := itemsFromProto(m.GetItems())
+	if err != nil {
+		return InventoryReserveFailed{}, err
+	}
+	return InventoryReserveFailed{OrderID: orderID, SagaID: sagaID, Items: items, Reason: m.GetReason()}, nil
+}
+
+func commitInventoryToProto(e CommitInventory) *eventspb.CommitInventory {
+	return &eventspb.CommitInventory{OrderId: e.OrderID.String(), SagaId: e.SagaID.String()}
+}
+
+func commitInventoryFromProto(m *eventspb.CommitInventory) (CommitInventory, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return CommitInventory{}, err
+	}
+	return CommitInventory{OrderID: orderID, SagaID: sagaID}, nil
+}
+
+func inventoryCommittedToProto(e InventoryCommitted) *eventspb.InventoryCommitted {
+	return &eventspb.InventoryCommitted{
+		OrderId: e.OrderID.String(),
+		SagaId:  e.SagaID.String(),
+		Items:   itemsToProto(e.Items),
+	}
+}
+
+func inventoryCommittedFromProto(m *eventspb.InventoryCommitted) (InventoryCommitted, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return InventoryCommitted{}, err
+	}
+	items, err := itemsFromProto(m.GetItems())
+	if err != nil {
+		return InventoryCommitted{}, err
+	}
+	return InventoryCommitted{OrderID: orderID, SagaID: sagaID, Items: items}, nil
+}
+
+func inventoryCommitFailedToProto(e InventoryCommitFailed) *eventspb.InventoryCommitFailed {
+	return &eventspb.InventoryCommitFailed{OrderId: e.OrderID.String(), SagaId: e.SagaID.String(), Reason: e.Reason}
+}
+
+func inventoryCommitFailedFromProto(m *eventspb.InventoryCommitFailed) (InventoryCommitFailed, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return InventoryCommitFailed{}, err
+	}
+	return InventoryCommitFailed{OrderID: orderID, SagaID: sagaID, Reason: m.GetReason()}, nil
+}
+
+func releaseInventoryToProto(e ReleaseInventory) *eventspb.ReleaseInventory {
+	return &eventspb.ReleaseInventory{
+		OrderId: e.OrderID.String(),
+		SagaId:  e.SagaID.String(),
+		Items:   itemsToProto(e.Items),
+	}
+}
+
+func releaseInventoryFromProto(m *eventspb.ReleaseInventory) (ReleaseInventory, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return ReleaseInventory{}, err
+	}
+	items, err := itemsFromProto(m.GetItems())
+	if err != nil {
+		return ReleaseInventory{}, err
+	}
+	return ReleaseInventory{OrderID: orderID, SagaID: sagaID, Items: items}, nil
+}
+
+func inventoryReleasedToProto(e InventoryReleased) *eventspb.InventoryReleased {
+	return &eventspb.InventoryReleased{
+		OrderId: e.OrderID.String(),
+		SagaId:  e.SagaID.String(),
+		Items:   itemsToProto(e.Items),
+	}
+}
+
+func inventoryReleasedFromProto(m *eventspb.InventoryReleased) (InventoryReleased, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return InventoryReleased{}, err
+	}
+	items, err := itemsFromProto(m.GetItems())
+	if err != nil {
+		return InventoryReleased{}, err
+	}
+	return InventoryReleased{OrderID: orderID, SagaID: sagaID, Items: items}, nil
+}
+
+func inventoryReleaseFailedToProto(e InventoryReleaseFailed) *eventspb.InventoryReleaseFailed {
+	return &eventspb.InventoryReleaseFailed{OrderId: e.OrderID.String(), SagaId: e.SagaID.String(), Reason: e.Reason}
+}
+
+func inventoryReleaseFailedFromProto(m *eventspb.InventoryReleaseFailed) (InventoryReleaseFailed, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return InventoryReleaseFailed{}, err
+	}
+	return InventoryReleaseFailed{OrderID: orderID, SagaID: sagaID, Reason: m.GetReason()}, nil
+}
+
+func inventoryHoldExpiredToProto(e InventoryHoldExpired) *eventspb.InventoryHoldExpired {
+	return &eventspb.InventoryHoldExpired{OrderId: e.OrderID.String(), SagaId: e.SagaID.String()}
+}
+
+func inventoryHoldExpiredFromProto(m *eventspb.InventoryHoldExpired) (InventoryHoldExpired, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return InventoryHoldExpired{}, err
+	}
+	return InventoryHoldExpired{OrderID: orderID, SagaID: sagaID}, nil
+}
+
+func cancelOrderToProto(e CancelOrder) *eventspb.CancelOrder {
+	return &eventspb.CancelOrder{OrderId: e.OrderID.String(), SagaId: e.SagaID.String()}
+}
+
+func cancelOrderFromProto(m *eventspb.CancelOrder) (CancelOrder, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return CancelOrder{}, err
+	}
+	return CancelOrder{OrderID: orderID, SagaID: sagaID}, nil
+}
+
+func compensateOrderToProto(e CompensateOrder) *eventspb.CompensateOrder {
+	return &eventspb.CompensateOrder{OrderId: e.OrderID.String(), SagaId: e.SagaID.String()}
+}
+
+func compensateOrderFromProto(m *eventspb.CompensateOrder) (CompensateOrder, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return CompensateOrder{}, err
+	}
+	return CompensateOrder{OrderID: orderID, SagaID: sagaID}, nil
+}
+
+func authorizePaymentToProto(e AuthorizePayment) *eventspb.AuthorizePayment {
+	return &eventspb.AuthorizePayment{
+		OrderId: e.OrderID.String(),
+		SagaId:  e.SagaID.String(),
+		UserId:  e.UserID.String(),
+		Amount:  moneyToProto(e.Amount),
+	}
+}
+
+func authorizePaymentFromProto(m *eventspb.AuthorizePayment) (AuthorizePayment, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return AuthorizePayment{}, err
+	}
+	userID, err := parseID("user_id", m.GetUserId())
+	if err != nil {
+		return AuthorizePayment{}, err
+	}
+	return AuthorizePayment{OrderID: orderID, SagaID: sagaID, UserID: userID, Amount: moneyFromProto(m.GetAmount(), 0)}, nil
+}
+
+func paymentAuthorizedToProto(e PaymentAuthorized) *eventspb.PaymentAuthorized {
+	return &eventspb.PaymentAuthorized{
+		OrderId:   e.OrderID.String(),
+		SagaId:    e.SagaID.String(),
+		PaymentId: e.PaymentID.String(),
+		Amount:    moneyToProto(e.Amount),
+	}
+}
+
+func paymentAuthorizedFromProto(m *eventspb.PaymentAuthorized) (PaymentAuthorized, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return PaymentAuthorized{}, err
+	}
+	paymentID, err := parseID("payment_id", m.GetPaymentId())
+	if err != nil {
+		return PaymentAuthorized{}, err
+	}
+	return PaymentAuthorized{OrderID: orderID, SagaID: sagaID, PaymentID: paymentID, Amount: moneyFromProto(m.GetAmount(), 0)}, nil
+}
+
+func paymentAuthFailedToProto(e PaymentAuthorizationFailed) *eventspb.PaymentAuthorizationFailed {
+	return &eventspb.PaymentAuthorizationFailed{OrderId: e.OrderID.String(), SagaId: e.SagaID.String(), Reason: e.Reason}
+}
+
+func paymentAuthFailedFromProto(m *eventspb.PaymentAuthorizationFailed) (PaymentAuthorizationFailed, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return PaymentAuthorizationFailed{}, err
+	}
+	return PaymentAuthorizationFailed{OrderID: orderID, SagaID: sagaID, Reason: m.GetReason()}, nil
+}
+
+func capturePaymentToProto(e CapturePayment) *eventspb.CapturePayment {
+	return &eventspb.CapturePayment{OrderId: e.OrderID.String(), SagaId: e.SagaID.String()}
+}
+
+func capturePaymentFromProto(m *eventspb.CapturePayment) (CapturePayment, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return CapturePayment{}, err
+	}
+	return CapturePayment{OrderID: orderID, SagaID: sagaID}, nil
+}
+
+func paymentCapturedToProto(e PaymentCaptured) *eventspb.PaymentCaptured {
+	return &eventspb.PaymentCaptured{
+		OrderId:   e.OrderID.String(),
+		SagaId:    e.SagaID.String(),
+		PaymentId: e.PaymentID.String(),
+		Amount:    moneyToProto(e.Amount),
+	}
+}
+
+func paymentCapturedFromProto(m *eventspb.PaymentCaptured) (PaymentCaptured, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return PaymentCaptured{}, err
+	}
+	paymentID, err := parseID("payment_id", m.GetPaymentId())
+	if err != nil {
+		return PaymentCaptured{}, err
+	}
+	return PaymentCaptured{OrderID: orderID, SagaID: sagaID, PaymentID: paymentID, Amount: moneyFromProto(m.GetAmount(), 0)}, nil
+}
+
+func paymentCaptureFailedToProto(e PaymentCaptureFailed) *eventspb.PaymentCaptureFailed {
+	return &eventspb.PaymentCaptureFailed{OrderId: e.OrderID.String(), SagaId: e.SagaID.String(), Reason: e.Reason}
+}
+
+func paymentCaptureFailedFromProto(m *eventspb.PaymentCaptureFailed) (PaymentCaptureFailed, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return PaymentCaptureFailed{}, err
+	}
+	return PaymentCaptureFailed{OrderID: orderID, SagaID: sagaID, Reason: m.GetReason()}, nil
+}
+
+func voidPaymentToProto(e VoidPayment) *eventspb.VoidPayment {
+	return &eventspb.VoidPayment{OrderId: e.OrderID.String(), SagaId: e.SagaID.String()}
+}
+
+func voidPaymentFromProto(m *eventspb.VoidPayment) (VoidPayment, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return VoidPayment{}, err
+	}
+	return VoidPayment{OrderID: orderID, SagaID: sagaID}, nil
+}
+
+func paymentVoidedToProto(e PaymentVoided) *eventspb.PaymentVoided {
+	return &eventspb.PaymentVoided{OrderId: e.OrderID.String(), SagaId: e.SagaID.String()}
+}
+
+func paymentVoidedFromProto(m *eventspb.PaymentVoided) (PaymentVoided, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return PaymentVoided{}, err
+	}
+	return PaymentVoided{OrderID: orderID, SagaID: sagaID}, nil
+}
+
+func paymentVoidFailedToProto(e PaymentVoidFailed) *eventspb.PaymentVoidFailed {
+	return &eventspb.PaymentVoidFailed{OrderId: e.OrderID.String(), SagaId: e.SagaID.String(), Reason: e.Reason}
+}
+
+func paymentVoidFailedFromProto(m *eventspb.PaymentVoidFailed) (PaymentVoidFailed, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return PaymentVoidFailed{}, err
+	}
+	return PaymentVoidFailed{OrderID: orderID, SagaID: sagaID, Reason: m.GetReason()}, nil
+}
+
+func refundPaymentToProto(e RefundPayment) *eventspb.RefundPayment {
+	return &eventspb.RefundPayment{
+		OrderId: e.OrderID.String(),
+		SagaId:  e.SagaID.String(),
+		Amount:  moneyToProto(e.Amount),
+	}
+}
+
+func refundPaymentFromProto(m *eventspb.RefundPayment) (RefundPayment, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return RefundPayment{}, err
+	}
+	return RefundPayment{OrderID: orderID, SagaID: sagaID, Amount: moneyFromProto(m.GetAmount(), 0)}, nil
+}
+
+func paymentRefundedToProto(e PaymentRefunded) *eventspb.PaymentRefunded {
+	return &eventspb.PaymentRefunded{
+		OrderId: e.OrderID.String(),
+		SagaId:  e.SagaID.String(),
+		Amount:  moneyToProto(e.Amount),
+	}
+}
+
+func paymentRefundedFromProto(m *eventspb.PaymentRefunded) (PaymentRefunded, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return PaymentRefunded{}, err
+	}
+	return PaymentRefunded{OrderID: orderID, SagaID: sagaID, Amount: moneyFromProto(m.GetAmount(), 0)}, nil
+}
+
+func paymentRefundFailedToProto(e PaymentRefundFailed) *eventspb.PaymentRefundFailed {
+	return &eventspb.PaymentRefundFailed{OrderId: e.OrderID.String(), SagaId: e.SagaID.String(), Reason: e.Reason}
+}
+
+func paymentRefundFailedFromProto(m *eventspb.PaymentRefundFailed) (PaymentRefundFailed, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return PaymentRefundFailed{}, err
+	}
+	return PaymentRefundFailed{OrderID: orderID, SagaID: sagaID, Reason: m.GetReason()}, nil
+}
+
+func refundOrderToProto(e RefundOrder) *eventspb.RefundOrder {
+	return &eventspb.RefundOrder{
+		OrderId:  e.OrderID.String(),
+		RefundId: e.RefundID.String(),
+		UserId:   e.UserID.String(),
+		Amount:   moneyToProto(e.Amount),
+		Items:    itemsToProto(e.Items),
+		Restock:  e.Restock,
+		Reason:   e.Reason,
+	}
+}
+
+func refundOrderFromProto(m *eventspb.RefundOrder) (RefundOrder, error) {
+	orderID, refundID, err := refundIDs(m.GetOrderId(), m.GetRefundId())
+	if err != nil {
+		return RefundOrder{}, err
+	}
+	userID, err := parseID("user_id", m.GetUserId())
+	if err != nil {
+		return RefundOrder{}, err
+	}
+	items, err := itemsFromProto(m.GetItems())
+	if err != nil {
+		return RefundOrder{}, err
+	}
+	return RefundOrder{
+		OrderID:  orderID,
+		RefundID: refundID,
+		UserID:   userID,
+		Amount:   moneyFromProto(m.GetAmount(), 0),
+		Items:    items,
+		Restock:  m.GetRestock(),
+		Reason:   m.GetReason(),
+	}, nil
+}
+
+func restockInventoryToProto(e RestockInventory) *eventspb.RestockInventory {
+	return &eventspb.RestockInventory{
+		OrderId: e.OrderID.String(),
+		SagaId:  e.SagaID.String(),
+		Items:   itemsToProto(e.Items),
+	}
+}
+
+func restockInventoryFromProto(m *eventspb.RestockInventory) (RestockInventory, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return RestockInventory{}, err
+	}
+	items, err := itemsFromProto(m.GetItems())
+	if err != nil {
+		return RestockInventory{}, err
+	}
+	return RestockInventory{OrderID: orderID, SagaID: sagaID, Items: items}, nil
+}
+
+func inventoryRestockedToProto(e InventoryRestocked) *eventspb.InventoryRestocked {
+	return &eventspb.InventoryRestocked{
+		OrderId: e.OrderID.String(),
+		SagaId:  e.SagaID.String(),
+		Items:   itemsToProto(e.Items),
+	}
+}
+
+func inventoryRestockedFromProto(m *eventspb.InventoryRestocked) (InventoryRestocked, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return InventoryRestocked{}, err
+	}
+	items, err := itemsFromProto(m.GetItems())
+	if err != nil {
+		return InventoryRestocked{}, err
+	}
+	return InventoryRestocked{OrderID: orderID, SagaID: sagaID, Items: items}, nil
+}
+
+func inventoryRestockFailedToProto(e InventoryRestockFailed) *eventspb.InventoryRestockFailed {
+	return &eventspb.InventoryRestockFailed{OrderId: e.OrderID.String(), SagaId: e.SagaID.String(), Reason: e.Reason}
+}
+
+func inventoryRestockFailedFromProto(m *eventspb.InventoryRestockFailed) (InventoryRestockFailed, error) {
+	orderID, sagaID, err := sagaIDs(m.GetOrderId(), m.GetSagaId())
+	if err != nil {
+		return InventoryRestockFailed{}, err
+	}
+	return InventoryRestockFailed{OrderID: orderID, SagaID: sagaID, Reason: m.GetReason()}, nil
+}
+
+func orderRefundCompletedToProto(e OrderRefundCompleted) *eventspb.OrderRefundCompleted {
+	return &eventspb.OrderRefundCompleted{
+		OrderId:  e.OrderID.String(),
+		RefundId: e.RefundID.String(),
+		Amount:   moneyToProto(e.Amount),
+	}
+}
+
+func orderRefundCompletedFromProto(m *eventspb.OrderRefundCompleted) (OrderRefundCompleted, error) {
+	orderID, refundID, err := refundIDs(m.GetOrderId(), m.GetRefundId())
+	if err != nil {
+		return OrderRefundCompleted{}, err
+	}
+	return OrderRefundCompleted{OrderID: orderID, RefundID: refundID, Amount: moneyFromProto(m.GetAmount(), 0)}, nil
+}
+
+func orderRefundFailedToProto(e OrderRefundFailed) *eventspb.OrderRefundFailed {
+	return &eventspb.OrderRefundFailed{OrderId: e.OrderID.String(), RefundId: e.RefundID.String(), Reason: e.Reason}
+}
+
+func orderRefundFailedFromProto(m *eventspb.OrderRefundFailed) (OrderRefundFailed, error) {
+	orderID, refundID, err := refundIDs(m.GetOrderId(), m.GetRefundId())
+	if err != nil {
+		return OrderRefundFailed{}, err
+	}
+	return OrderRefundFailed{OrderID: orderID, RefundID: refundID, Reason: m.GetReason()}, nil
+}
+
+// refundIDs parses the order and refund IDs of refund messages.
+func refundIDs(orderID, refundID string) (uuid.UUID, uuid.UUID, error) {
+	order, err := parseID("order_id", orderID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	refund, err := parseID("refund_id", refundID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return order, refund, nil
+}
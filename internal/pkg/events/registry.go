@@ -0,0 +1,190 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"immxrtalbeast/order_microservices/internal/pkg/money"
+
+	"google.golang.org/protobuf/proto"
+)
+
+var ErrUnknownType = errors.New("unknown event type")
+
+// Upcaster rewrites a payload of one schema version into the next one.
+type Upcaster func(payload json.RawMessage) (json.RawMessage, error)
+
+type schema struct {
+	version   int
+	decode    func(payload json.RawMessage) (Event, error)
+	upcasters map[int]Upcaster
+	toProto   func(event Event) proto.Message
+	fromProto func(payload []byte) (Event, error)
+}
+
+var registry = map[string]*schema{}
+
+// register adds T at its current schema version, together with the
+// conversions to and from its protobuf message P.
+func register[T Event, P proto.Message](version int, to func(T) P, from func(P) (T, error)) {
+	var zero T
+	registry[zero.EventType()] = &schema{
+		version: version,
+		decode: func(payload json.RawMessage) (Event, error) {
+			var v T
+			if err := json.Unmarshal(payload, &v); err != nil {
+				return nil, err
+			}
+			return v, nil
+		},
+		upcasters: map[int]Upcaster{},
+		toProto: func(event Event) proto.Message {
+			return to(event.(T))
+		},
+		fromProto: func(payload []byte) (Event, error) {
+			var empty P
+			msg := empty.ProtoReflect().Type().New().Interface().(P)
+			if err := proto.Unmarshal(payload, msg); err != nil {
+				return nil, err
+			}
+			return from(msg)
+		},
+	}
+}
+
+// upcaster registers fn to move eventType payloads from version from to from+1.
+func upcaster(eventType string, from int, fn Upcaster) {
+	registry[eventType].upcasters[from] = fn
+}
+
+func init() {
+	register(1, orderCreatedToProto, orderCreatedFromProto)
+	register(1, orderReadyToProto, orderReadyFromProto)
+	register(1, orderCompletedToProto, orderCompletedFromProto)
+	register(1, orderStatusChangedToProto, orderStatusChangedFromProto)
+	register(1, orderStatusUpdateToProto, orderStatusUpdateFromProto)
+	register(1, reserveInventoryToProto, reserveInventoryFromProto)
+	register(2, inventoryReservedToProto, inventoryReservedFromProto)
+	register(2, inventoryReserveFailedToProto, inventoryReserveFailedFromProto)
+	register(1, commitInventoryToProto, commitInventoryFromProto)
+	register(1, inventoryCommittedToProto, inventoryCommittedFromProto)
+	register(1, inventoryCommitFailedToProto, inventoryCommitFailedFromProto)
+	register(1, releaseInventoryToProto, releaseInventoryFromProto)
+	register(1, inventoryReleasedToProto, inventoryReleasedFromProto)
+	register(1, inventoryReleaseFailedToProto, inventoryReleaseFailedFromProto)
+	register(1, inventoryHoldExpiredToProto, inventoryHoldExpiredFromProto)
+	register(1, cancelOrderToProto, cancelOrderFromProto)
+	register(1, compensateOrderToProto, compensateOrderFromProto)
+	register(1, authorizePaymentToProto, authorizePaymentFromProto)
+	register(1, paymentAuthorizedToProto, paymentAuthorizedFromProto)
+	register(1, paymentAuthFailedToProto, paymentAuthFailedFromProto)
+	register(1, capturePaymentToProto, capturePaymentFromProto)
+	register(1, paymentCapturedToProto, paymentCapturedFromProto)
+	register(1, paymentCaptureFailedToProto, paymentCaptureFailedFromProto)
+	register(1, voidPaymentToProto, voidPaymentFromProto)
+	register(1, paymentVoidedToProto, paymentVoidedFromProto)
+	register(1, paymentVoidFailedToProto, paymentVoidFailedFromProto)
+	register(1, refundPaymentToProto, refundPaymentFromProto)
+	register(1, paymentRefundedToProto, paymentRefundedFromProto)
+	register(1, paymentRefundFailedToProto, paymentRefundFailedFromProto)
+	register(1, refundOrderToProto, refundOrderFromProto)
+	register(1, restockInventoryToProto, restockInventoryFromProto)
+	register(1, inventoryRestockedToProto, inventoryRestockedFromProto)
+	register(1, inventoryRestockFailedToProto, inventoryRestockFailedFromProto)
+	register(1, orderRefundCompletedToProto, orderRefundCompletedFromProto)
+	register(1, orderRefundFailedToProto, orderRefundFailedFromProto)
+
+	// Version 1 failures echoed the reserve command back without saying why.
+	upcaster(TypeInventoryReserveFailed, 1, func(payload json.RawMessage) (json.RawMessage, error) {
+		var v map[string]json.RawMessage
+		if err := json.Unmarshal(payload, &v); err != nil {
+			return nil, err
+		}
+		if _, ok := v["reason"]; !ok {
+			v["reason"] = json.RawMessage(`"reason not reported"`)
+		}
+		return json.Marshal(v)
+	})
+	// Version 1 reservations carried the total and item prices in whole rubles.
+	upcaster(TypeInventoryReserved, 1, func(payload json.RawMessage) (json.RawMessage, error) {
+		var v map[string]json.RawMessage
+		if err := json.Unmarshal(payload, &v); err != nil {
+			return nil, err
+		}
+		if _, ok := v["total"]; !ok {
+			var sum int64
+			if raw, ok := v["total_sum"]; ok {
+				if err := json.Unmarshal(raw, &sum); err != nil {
+					return nil, err
+				}
+			}
+			total, err := json.Marshal(money.FromMajor(sum, money.DefaultCurrency))
+			if err != nil {
+				return nil, err
+			}
+			v["total"] = total
+			delete(v, "total_sum")
+		}
+		var items []map[string]json.RawMessage
+		if raw, ok := v["products"]; ok {
+			if err := json.Unmarshal(raw, &items); err != nil {
+				return nil, err
+			}
+		}
+		for _, item := range items {
+			var units int64
+			if err := json.Unmarshal(item["price"], &units); err != nil {
+				continue // absent or already Money
+			}
+			price, err := json.Marshal(money.FromMajor(units, money.DefaultCurrency))
+			if err != nil {
+				return nil, err
+			}
+			item["price"] = price
+		}
+		if items != nil {
+			products, err := json.Marshal(items)
+			if err != nil {
+				return nil, err
+			}
+			v["products"] = products
+		}
+		return json.Marshal(v)
+	})
+}
+
+// SchemaVersion returns the version eventType is currently produced at.
+func SchemaVersion(eventType string) (int, error) {
+	s, ok := registry[eventType]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownType, eventType)
+	}
+	return s.version, nil
+}
+
+// DecodePayload decodes a payload of eventType at version, upcasting it to the
+// current version first.
+func DecodePayload(eventType string, version int, payload json.RawMessage) (Event, error) {
+	s, ok := registry[eventType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownType, eventType)
+	}
+	if version > s.version {
+		return nil, fmt.Errorf("%s version %d is newer than supported version %d", eventType, version, s.version)
+	}
+	for v := version; v < s.version; v++ {
+		up, ok := s.upcasters[v]
+		if !ok {
+			continue
+		}
+		var err error
+		if payload, err = up(payload); err != nil {
+			return nil, fmt.Errorf("upcast %s from version %d: %w", eventType, v, err)
+		}
+	}
+	event, err := s.decode(payload)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", eventType, err)
+	}
+	return event, nil
+}
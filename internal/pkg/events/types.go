@@ -0,0 +1,335 @@
+package events
+
+import (
+	"immxrtalbeast/order_microservices/internal/pkg/money"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event and command type names. They are also sent in the Event-Type header.
+const (
+	TypeOrderCreated           = "OrderCreatedEvent"
+	TypeOrderReady             = "OrderReadyEvent"
+	TypeOrderCompleted         = "OrderCompletedEvent"
+	TypeOrderStatusChanged     = "OrderStatusChangedEvent"
+	TypeOrderStatusUpdate      = "OrderStatusUpdateCommand"
+	TypeReserveInventory       = "InventoryReserveItemsCommand"
+	TypeInventoryReserved      = "InventoryReservedEvent"
+	TypeInventoryReserveFailed = "InventoryReservedEventFailed"
+	TypeCommitInventory        = "CommitInventoryCommand"
+	TypeInventoryCommitted     = "InventoryCommittedEvent"
+	TypeInventoryCommitFailed  = "InventoryCommitFailedEvent"
+	TypeReleaseInventory       = "ReleaseInventoryCommand"
+	TypeInventoryReleased      = "InventoryReleasedEvent"
+	TypeInventoryReleaseFailed = "InventoryReleaseFailedEvent"
+	TypeInventoryHoldExpired   = "InventoryHoldExpiredEvent"
+	TypeCancelOrder            = "CancelOrderCommand"
+	TypeCompensateOrder        = "CompensateOrderCommand"
+	TypeAuthorizePayment       = "AuthorizePaymentCommand"
+	TypePaymentAuthorized      = "PaymentAuthorizedEvent"
+	TypePaymentAuthFailed      = "PaymentAuthorizationFailedEvent"
+	TypeCapturePayment         = "CapturePaymentCommand"
+	TypePaymentCaptured        = "PaymentCapturedEvent"
+	TypePaymentCaptureFailed   = "PaymentCaptureFailedEvent"
+	TypeVoidPayment            = "VoidPaymentCommand"
+	TypePaymentVoided          = "PaymentVoidedEvent"
+	TypePaymentVoidFailed      = "PaymentVoidFailedEvent"
+	TypeRefundPayment          = "RefundPaymentCommand"
+	TypePaymentRefunded        = "PaymentRefundedEvent"
+	TypePaymentRefundFailed    = "PaymentRefundFailedEvent"
+	TypeRefundOrder            = "RefundOrderCommand"
+	TypeRestockInventory       = "RestockInventoryCommand"
+	TypeInventoryRestocked     = "InventoryRestockedEvent"
+	TypeInventoryRestockFailed = "InventoryRestockFailedEvent"
+	TypeOrderRefundCompleted   = "OrderRefundCompletedEvent"
+	TypeOrderRefundFailed      = "OrderRefundFailedEvent"
+)
+
+// Event is implemented by every payload that can travel in an Envelope.
+type Event interface {
+	EventType() string
+}
+
+// Item is a product line of an order. Name, Volume and Price are the product
+// as it was when inventory reserved it and are only set on InventoryReserved;
+// Price is then the price of one unit with its modifiers.
+type Item struct {
+	ProductID uuid.UUID      `json:"product_id"`
+	Quantity  int            `json:"quantity"`
+	Name      string         `json:"name,omitempty"`
+	Volume    int            `json:"volume,omitempty"`
+	Price     money.Money    `json:"price,omitzero"`
+	Modifiers []ItemModifier `json:"modifiers,omitempty"`
+}
+
+// ItemModifier is a modifier chosen for an order line. Name and PriceDelta
+// are only set on InventoryReserved, like the product fields of Item.
+type ItemModifier struct {
+	ModifierID uuid.UUID   `json:"modifier_id"`
+	Name       string      `json:"name,omitempty"`
+	PriceDelta money.Money `json:"price_delta,omitzero"`
+}
+
+type OrderCreated struct {
+	OrderID uuid.UUID `json:"order_id"`
+	UserID  uuid.UUID `json:"user_id"`
+	Items   []Item    `json:"products"`
+}
+
+// OrderReady is published by order-service when an order is ready to be
+// handed over; the saga captures its payment before it can be completed.
+type OrderReady struct {
+	OrderID uuid.UUID `json:"order_id"`
+}
+
+type OrderCompleted struct {
+	OrderID uuid.UUID `json:"order_id"`
+}
+
+// OrderStatusChanged is published by order-service on every status transition.
+type OrderStatusChanged struct {
+	OrderID   uuid.UUID `json:"order_id"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+type OrderStatusUpdate struct {
+	OrderID uuid.UUID `json:"order_id"`
+	Status  string    `json:"status"`
+	Reason  string    `json:"reason,omitempty"`
+}
+
+type ReserveInventory struct {
+	OrderID uuid.UUID `json:"order_id"`
+	SagaID  uuid.UUID `json:"saga_id"`
+	Items   []Item    `json:"products"`
+}
+
+// InventoryReserved is at schema version 2, which replaced the whole-ruble
+// total_sum with an exact Total.
+type InventoryReserved struct {
+	OrderID uuid.UUID   `json:"order_id"`
+	SagaID  uuid.UUID   `json:"saga_id"`
+	Items   []Item      `json:"products"`
+	Total   money.Money `json:"total"`
+}
+
+// InventoryReserveFailed is at schema version 2, which added Reason.
+type InventoryReserveFailed struct {
+	OrderID uuid.UUID `json:"order_id"`
+	SagaID  uuid.UUID `json:"saga_id"`
+	Items   []Item    `json:"products"`
+	Reason  string    `json:"reason"`
+}
+
+type CommitInventory struct {
+	OrderID uuid.UUID `json:"order_id"`
+	SagaID  uuid.UUID `json:"saga_id"`
+}
+
+type InventoryCommitted struct {
+	OrderID uuid.UUID `json:"order_id"`
+	SagaID  uuid.UUID `json:"saga_id"`
+	Items   []Item    `json:"products"`
+}
+
+type InventoryCommitFailed struct {
+	OrderID uuid.UUID `json:"order_id"`
+	SagaID  uuid.UUID `json:"saga_id"`
+	Reason  string    `json:"reason"`
+}
+
+type ReleaseInventory struct {
+	OrderID uuid.UUID `json:"order_id"`
+	SagaID  uuid.UUID `json:"saga_id"`
+	Items   []Item    `json:"products"`
+}
+
+type InventoryReleased struct {
+	OrderID uuid.UUID `json:"order_id"`
+	SagaID  uuid.UUID `json:"saga_id"`
+	Items   []Item    `json:"products"`
+}
+
+type InventoryReleaseFailed struct {
+	OrderID uuid.UUID `json:"order_id"`
+	SagaID  uuid.UUID `json:"saga_id"`
+	Reason  string    `json:"reason"`
+}
+
+// InventoryHoldExpired reports that the order's hold outlived its expiry and
+// no longer keeps its stock from other orders.
+type InventoryHoldExpired struct {
+	OrderID uuid.UUID `json:"order_id"`
+	SagaID  uuid.UUID `json:"saga_id"`
+}
+
+type CancelOrder struct {
+	OrderID uuid.UUID `json:"order_id"`
+	SagaID  uuid.UUID `json:"saga_id"`
+}
+
+type CompensateOrder struct {
+	OrderID uuid.UUID `json:"order_id"`
+	SagaID  uuid.UUID `json:"saga_id"`
+}
+
+// AuthorizePayment asks payment-service to hold Amount for the order.
+type AuthorizePayment struct {
+	OrderID uuid.UUID   `json:"order_id"`
+	SagaID  uuid.UUID   `json:"saga_id"`
+	UserID  uuid.UUID   `json:"user_id"`
+	Amount  money.Money `json:"amount"`
+}
+
+type PaymentAuthorized struct {
+	OrderID   uuid.UUID   `json:"order_id"`
+	SagaID    uuid.UUID   `json:"saga_id"`
+	PaymentID uuid.UUID   `json:"payment_id"`
+	Amount    money.Money `json:"amount"`
+}
+
+type PaymentAuthorizationFailed struct {
+	OrderID uuid.UUID `json:"order_id"`
+	SagaID  uuid.UUID `json:"saga_id"`
+	Reason  string    `json:"reason"`
+}
+
+type CapturePayment struct {
+	OrderID uuid.UUID `json:"order_id"`
+	SagaID  uuid.UUID `json:"saga_id"`
+}
+
+type PaymentCaptured struct {
+	OrderID   uuid.UUID   `json:"order_id"`
+	SagaID    uuid.UUID   `json:"saga_id"`
+	PaymentID uuid.UUID   `json:"payment_id"`
+	Amount    money.Money `json:"amount"`
+}
+
+type PaymentCaptureFailed struct {
+	OrderID uuid.UUID `json:"order_id"`
+	SagaID  uuid.UUID `json:"saga_id"`
+	Reason  string    `json:"reason"`
+}
+
+// VoidPayment drops the order's authorization. A payment that was captured
+// meanwhile is refunded instead and answered with PaymentRefunded.
+type VoidPayment struct {
+	OrderID uuid.UUID `json:"order_id"`
+	SagaID  uuid.UUID `json:"saga_id"`
+}
+
+type PaymentVoided struct {
+	OrderID uuid.UUID `json:"order_id"`
+	SagaID  uuid.UUID `json:"saga_id"`
+}
+
+type PaymentVoidFailed struct {
+	OrderID uuid.UUID `json:"order_id"`
+	SagaID  uuid.UUID `json:"saga_id"`
+	Reason  string    `json:"reason"`
+}
+
+// RefundPayment returns Amount of the captured payment, or all of it not
+// refunded yet when Amount is zero.
+type RefundPayment struct {
+	OrderID uuid.UUID   `json:"order_id"`
+	SagaID  uuid.UUID   `json:"saga_id"`
+	Amount  money.Money `json:"amount,omitzero"`
+}
+
+type PaymentRefunded struct {
+	OrderID uuid.UUID   `json:"order_id"`
+	SagaID  uuid.UUID   `json:"saga_id"`
+	Amount  money.Money `json:"amount"`
+}
+
+type PaymentRefundFailed struct {
+	OrderID uuid.UUID `json:"order_id"`
+	SagaID  uuid.UUID `json:"saga_id"`
+	Reason  string    `json:"reason"`
+}
+
+// RefundOrder asks saga-service to return Amount of a completed order's
+// payment, putting Items back in stock when Restock is set.
+type RefundOrder struct {
+	OrderID  uuid.UUID   `json:"order_id"`
+	RefundID uuid.UUID   `json:"refund_id"`
+	UserID   uuid.UUID   `json:"user_id"`
+	Amount   money.Money `json:"amount"`
+	Items    []Item      `json:"products"`
+	Restock  bool        `json:"restock"`
+	Reason   string      `json:"reason"`
+}
+
+// RestockInventory puts refunded items back in stock. It is applied once per
+// saga however often it is sent.
+type RestockInventory struct {
+	OrderID uuid.UUID `json:"order_id"`
+	SagaID  uuid.UUID `json:"saga_id"`
+	Items   []Item    `json:"products"`
+}
+
+type InventoryRestocked struct {
+	OrderID uuid.UUID `json:"order_id"`
+	SagaID  uuid.UUID `json:"saga_id"`
+	Items   []Item    `json:"products"`
+}
+
+type InventoryRestockFailed struct {
+	OrderID uuid.UUID `json:"order_id"`
+	SagaID  uuid.UUID `json:"saga_id"`
+	Reason  string    `json:"reason"`
+}
+
+// OrderRefundCompleted tells order-service the refund's money was returned.
+type OrderRefundCompleted struct {
+	OrderID  uuid.UUID   `json:"order_id"`
+	RefundID uuid.UUID   `json:"refund_id"`
+	Amount   money.Money `json:"amount"`
+}
+
+type OrderRefundFailed struct {
+	OrderID  uuid.UUID `json:"order_id"`
+	RefundID uuid.UUID `json:"refund_id"`
+	Reason   string    `json:"reason"`
+}
+
+func (OrderCreated) EventType() string               { return TypeOrderCreated }
+func (OrderReady) EventType() string                 { return TypeOrderReady }
+func (OrderCompleted) EventType() string             { return TypeOrderCompleted }
+func (OrderStatusChanged) EventType() string         { return TypeOrderStatusChanged }
+func (OrderStatusUpdate) EventType() string          { return TypeOrderStatusUpdate }
+func (ReserveInventory) EventType() string           { return TypeReserveInventory }
+func (InventoryReserved) EventType() string          { return TypeInventoryReserved }
+func (InventoryReserveFailed) EventType() string     { return TypeInventoryReserveFailed }
+func (CommitInventory) EventType() string            { return TypeCommitInventory }
+func (InventoryCommitted) EventType() string         { return TypeInventoryCommitted }
+func (InventoryCommitFailed) EventType() string      { return TypeInventoryCommitFailed }
+func (ReleaseInventory) EventType() string           { return TypeReleaseInventory }
+func (InventoryReleased) EventType() string          { return TypeInventoryReleased }
+func (InventoryReleaseFailed) EventType() string     { return TypeInventoryReleaseFailed }
+func (InventoryHoldExpired) EventType() string       { return TypeInventoryHoldExpired }
+func (CancelOrder) EventType() string                { return TypeCancelOrder }
+func (CompensateOrder) EventType() string            { return TypeCompensateOrder }
+func (AuthorizePayment) EventType() string           { return TypeAuthorizePayment }
+func (PaymentAuthorized) EventType() string          { return TypePaymentAuthorized }
+func (PaymentAuthorizationFailed) EventType() string { return TypePaymentAuthFailed }
+func (CapturePayment) EventType() string             { return TypeCapturePayment }
+func (PaymentCaptured) EventType() string            { return TypePaymentCaptured }
+func (PaymentCaptureFailed) EventType() string       { return TypePaymentCaptureFailed }
+func (VoidPayment) EventType() string                { return TypeVoidPayment }
+func (PaymentVoided) EventType() string              { return TypePaymentVoided }
+func (PaymentVoidFailed) EventType() string          { return TypePaymentVoidFailed }
+func (RefundPayment) EventType() string              { return TypeRefundPayment }
+func (PaymentRefunded) EventType() string            { return TypePaymentRefunded }
+func (PaymentRefundFailed) EventType() string        { return TypePaymentRefundFailed }
+func (RefundOrder) EventType() string                { return TypeRefundOrder }
+func (RestockInventory) EventType() string           { return TypeRestockInventory }
+func (InventoryRestocked) EventType() string         { return TypeInventoryRestocked }
+func (InventoryRestockFailed) EventType() string     { return TypeInventoryRestockFailed }
+func (OrderRefundCompleted) EventType() string       { return TypeOrderRefundCompleted }
+func (OrderRefundFailed) EventType() string          { return TypeOrderRefundFailed }
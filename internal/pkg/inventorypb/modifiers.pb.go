@@ -0,0 +1,745 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: inventory/v1/modifiers.proto
+
+package inventorypb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SelectionType int32
+
+const (
+	SelectionType_SELECTION_TYPE_UNSPECIFIED SelectionType = 0
+	// SINGLE groups allow at most one modifier, e.g. the milk type.
+	SelectionType_SELECTION_TYPE_SINGLE SelectionType = 1
+	// MULTIPLE groups allow up to max_selected modifiers, e.g. syrups.
+	SelectionType_SELECTION_TYPE_MULTIPLE SelectionType = 2
+)
+
+// Enum value maps for SelectionType.
+var (
+	SelectionType_name = map[int32]string{
+		0: "SELECTION_TYPE_UNSPECIFIED",
+		1: "SELECTION_TYPE_SINGLE",
+		2: "SELECTION_TYPE_MULTIPLE",
+	}
+	SelectionType_value = map[string]int32{
+		"SELECTION_TYPE_UNSPECIFIED": 0,
+		"SELECTION_TYPE_SINGLE":      1,
+		"SELECTION_TYPE_MULTIPLE":    2,
+	}
+)
+
+func (x SelectionType) Enum() *SelectionType {
+	p := new(SelectionType)
+	*p = x
+	return p
+}
+
+func (x SelectionType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SelectionType) Descriptor() protoreflect.EnumDescriptor {
+	return file_inventory_v1_modifiers_proto_enumTypes[0].Descriptor()
+}
+
+func (SelectionType) Type() protoreflect.EnumType {
+	return &file_inventory_v1_modifiers_proto_enumTypes[0]
+}
+
+func (x SelectionType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SelectionType.Descriptor instead.
+func (SelectionType) EnumDescriptor() ([]byte, []int) {
+	return file_inventory_v1_modifiers_proto_rawDescGZIP(), []int{0}
+}
+
+// Money is an amount in the minor units of an ISO 4217 currency, kopecks
+// for RUB.
+type Money struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Amount        int64                  `protobuf:"varint,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Money) Reset() {
+	*x = Money{}
+	mi := &file_inventory_v1_modifiers_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Money) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Money) ProtoMessage() {}
+
+func (x *Money) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_modifiers_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Money.ProtoReflect.Descriptor instead.
+func (*Money) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_modifiers_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Money) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Money) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+// Modifier is an option added to a good, priced on top of it.
+type Modifier struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name       string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	PriceDelta *Money                 `protobuf:"bytes,3,opt,name=price_delta,json=priceDelta,proto3" json:"price_delta,omitempty"`
+	// track_stock modifiers are reserved with the order like goods are;
+	// untracked ones are always available.
+	TrackStock        bool  `protobuf:"varint,4,opt,name=track_stock,json=trackStock,proto3" json:"track_stock,omitempty"`
+	QuantityInStock   int64 `protobuf:"varint,5,opt,name=quantity_in_stock,json=quantityInStock,proto3" json:"quantity_in_stock,omitempty"`
+	QuantityAvailable int64 `protobuf:"varint,6,opt,name=quantity_available,json=quantityAvailable,proto3" json:"quantity_available,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Modifier) Reset() {
+	*x = Modifier{}
+	mi := &file_inventory_v1_modifiers_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Modifier) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Modifier) ProtoMessage() {}
+
+func (x *Modifier) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_modifiers_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Modifier.ProtoReflect.Descriptor instead.
+func (*Modifier) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_modifiers_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Modifier) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Modifier) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Modifier) GetPriceDelta() *Money {
+	if x != nil {
+		return x.PriceDelta
+	}
+	return nil
+}
+
+func (x *Modifier) GetTrackStock() bool {
+	if x != nil {
+		return x.TrackStock
+	}
+	return false
+}
+
+func (x *Modifier) GetQuantityInStock() int64 {
+	if x != nil {
+		return x.QuantityInStock
+	}
+	return 0
+}
+
+func (x *Modifier) GetQuantityAvailable() int64 {
+	if x != nil {
+		return x.QuantityAvailable
+	}
+	return 0
+}
+
+// ModifierGroup is a set of modifiers a customer picks between
+// min_selected and max_selected of.
+type ModifierGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	SelectionType SelectionType          `protobuf:"varint,3,opt,name=selection_type,json=selectionType,proto3,enum=inventory.v1.SelectionType" json:"selection_type,omitempty"`
+	MinSelected   int32                  `protobuf:"varint,4,opt,name=min_selected,json=minSelected,proto3" json:"min_selected,omitempty"`
+	MaxSelected   int32                  `protobuf:"varint,5,opt,name=max_selected,json=maxSelected,proto3" json:"max_selected,omitempty"`
+	Modifiers     []*Modifier            `protobuf:"bytes,6,rep,name=modifiers,proto3" json:"modifiers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModifierGroup) Reset() {
+	*x = ModifierGroup{}
+	mi := &file_inventory_v1_modifiers_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModifierGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModifierGroup) ProtoMessage() {}
+
+func (x *ModifierGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_modifiers_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModifierGroup.ProtoReflect.Descriptor instead.
+func (*ModifierGroup) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_modifiers_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ModifierGroup) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ModifierGroup) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ModifierGroup) GetSelectionType() SelectionType {
+	if x != nil {
+		return x.SelectionType
+	}
+	return SelectionType_SELECTION_TYPE_UNSPECIFIED
+}
+
+func (x *ModifierGroup) GetMinSelected() int32 {
+	if x != nil {
+		return x.MinSelected
+	}
+	return 0
+}
+
+func (x *ModifierGroup) GetMaxSelected() int32 {
+	if x != nil {
+		return x.MaxSelected
+	}
+	return 0
+}
+
+func (x *ModifierGroup) GetModifiers() []*Modifier {
+	if x != nil {
+		return x.Modifiers
+	}
+	return nil
+}
+
+type GoodModifiers struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GoodId        string                 `protobuf:"bytes,1,opt,name=good_id,json=goodId,proto3" json:"good_id,omitempty"`
+	Groups        []*ModifierGroup       `protobuf:"bytes,2,rep,name=groups,proto3" json:"groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GoodModifiers) Reset() {
+	*x = GoodModifiers{}
+	mi := &file_inventory_v1_modifiers_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GoodModifiers) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GoodModifiers) ProtoMessage() {}
+
+func (x *GoodModifiers) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_modifiers_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GoodModifiers.ProtoReflect.Descriptor instead.
+func (*GoodModifiers) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_modifiers_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GoodModifiers) GetGoodId() string {
+	if x != nil {
+		return x.GoodId
+	}
+	return ""
+}
+
+func (x *GoodModifiers) GetGroups() []*ModifierGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+type ListModifierGroupsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListModifierGroupsRequest) Reset() {
+	*x = ListModifierGroupsRequest{}
+	mi := &file_inventory_v1_modifiers_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListModifierGroupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListModifierGroupsRequest) ProtoMessage() {}
+
+func (x *ListModifierGroupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_modifiers_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListModifierGroupsRequest.ProtoReflect.Descriptor instead.
+func (*ListModifierGroupsRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_modifiers_proto_rawDescGZIP(), []int{4}
+}
+
+type ListModifierGroupsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Groups        []*ModifierGroup       `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListModifierGroupsResponse) Reset() {
+	*x = ListModifierGroupsResponse{}
+	mi := &file_inventory_v1_modifiers_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListModifierGroupsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListModifierGroupsResponse) ProtoMessage() {}
+
+func (x *ListModifierGroupsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_modifiers_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListModifierGroupsResponse.ProtoReflect.Descriptor instead.
+func (*ListModifierGroupsResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_modifiers_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListModifierGroupsResponse) GetGroups() []*ModifierGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+type SaveModifierGroupRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// group with an empty id is created. Otherwise the group is replaced:
+	// modifiers keep their id when it is given and the ones left out are
+	// removed.
+	Group         *ModifierGroup `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SaveModifierGroupRequest) Reset() {
+	*x = SaveModifierGroupRequest{}
+	mi := &file_inventory_v1_modifiers_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SaveModifierGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveModifierGroupRequest) ProtoMessage() {}
+
+func (x *SaveModifierGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_modifiers_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveModifierGroupRequest.ProtoReflect.Descriptor instead.
+func (*SaveModifierGroupRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_modifiers_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SaveModifierGroupRequest) GetGroup() *ModifierGroup {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+type SetGoodModifierGroupsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	GoodId string                 `protobuf:"bytes,1,opt,name=good_id,json=goodId,proto3" json:"good_id,omitempty"`
+	// group_ids replaces the groups offered for the good, in order.
+	GroupIds      []string `protobuf:"bytes,2,rep,name=group_ids,json=groupIds,proto3" json:"group_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetGoodModifierGroupsRequest) Reset() {
+	*x = SetGoodModifierGroupsRequest{}
+	mi := &file_inventory_v1_modifiers_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetGoodModifierGroupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetGoodModifierGroupsRequest) ProtoMessage() {}
+
+func (x *SetGoodModifierGroupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_modifiers_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetGoodModifierGroupsRequest.ProtoReflect.Descriptor instead.
+func (*SetGoodModifierGroupsRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_modifiers_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SetGoodModifierGroupsRequest) GetGoodId() string {
+	if x != nil {
+		return x.GoodId
+	}
+	return ""
+}
+
+func (x *SetGoodModifierGroupsRequest) GetGroupIds() []string {
+	if x != nil {
+		return x.GroupIds
+	}
+	return nil
+}
+
+type ListGoodModifiersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// good_ids limits the answer to these goods; empty lists all goods.
+	GoodIds       []string `protobuf:"bytes,1,rep,name=good_ids,json=goodIds,proto3" json:"good_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGoodModifiersRequest) Reset() {
+	*x = ListGoodModifiersRequest{}
+	mi := &file_inventory_v1_modifiers_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGoodModifiersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGoodModifiersRequest) ProtoMessage() {}
+
+func (x *ListGoodModifiersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_modifiers_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGoodModifiersRequest.ProtoReflect.Descriptor instead.
+func (*ListGoodModifiersRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_modifiers_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListGoodModifiersRequest) GetGoodIds() []string {
+	if x != nil {
+		return x.GoodIds
+	}
+	return nil
+}
+
+type ListGoodModifiersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Goods         []*GoodModifiers       `protobuf:"bytes,1,rep,name=goods,proto3" json:"goods,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGoodModifiersResponse) Reset() {
+	*x = ListGoodModifiersResponse{}
+	mi := &file_inventory_v1_modifiers_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGoodModifiersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGoodModifiersResponse) ProtoMessage() {}
+
+func (x *ListGoodModifiersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_modifiers_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGoodModifiersResponse.ProtoReflect.Descriptor instead.
+func (*ListGoodModifiersResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_modifiers_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListGoodModifiersResponse) GetGoods() []*GoodModifiers {
+	if x != nil {
+		return x.Goods
+	}
+	return nil
+}
+
+var File_inventory_v1_modifiers_proto protoreflect.FileDescriptor
+
+const file_inventory_v1_modifiers_proto_rawDesc = "" +
+	"\n" +
+	"\x1cinventory/v1/modifiers.proto\x12\finventory.v1\";\n" +
+	"\x05Money\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\x03R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\"\xe0\x01\n" +
+	"\bModifier\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x124\n" +
+	"\vprice_delta\x18\x03 \x01(\v2\x13.inventory.v1.MoneyR\n" +
+	"priceDelta\x12\x1f\n" +
+	"\vtrack_stock\x18\x04 \x01(\bR\n" +
+	"trackStock\x12*\n" +
+	"\x11quantity_in_stock\x18\x05 \x01(\x03R\x0fquantityInStock\x12-\n" +
+	"\x12quantity_available\x18\x06 \x01(\x03R\x11quantityAvailable\"\xf3\x01\n" +
+	"\rModifierGroup\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12B\n" +
+	"\x0eselection_type\x18\x03 \x01(\x0e2\x1b.inventory.v1.SelectionTypeR\rselectionType\x12!\n" +
+	"\fmin_selected\x18\x04 \x01(\x05R\vminSelected\x12!\n" +
+	"\fmax_selected\x18\x05 \x01(\x05R\vmaxSelected\x124\n" +
+	"\tmodifiers\x18\x06 \x03(\v2\x16.inventory.v1.ModifierR\tmodifiers\"]\n" +
+	"\rGoodModifiers\x12\x17\n" +
+	"\agood_id\x18\x01 \x01(\tR\x06goodId\x123\n" +
+	"\x06groups\x18\x02 \x03(\v2\x1b.inventory.v1.ModifierGroupR\x06groups\"\x1b\n" +
+	"\x19ListModifierGroupsRequest\"Q\n" +
+	"\x1aListModifierGroupsResponse\x123\n" +
+	"\x06groups\x18\x01 \x03(\v2\x1b.inventory.v1.ModifierGroupR\x06groups\"M\n" +
+	"\x18SaveModifierGroupRequest\x121\n" +
+	"\x05group\x18\x01 \x01(\v2\x1b.inventory.v1.ModifierGroupR\x05group\"T\n" +
+	"\x1cSetGoodModifierGroupsRequest\x12\x17\n" +
+	"\agood_id\x18\x01 \x01(\tR\x06goodId\x12\x1b\n" +
+	"\tgroup_ids\x18\x02 \x03(\tR\bgroupIds\"5\n" +
+	"\x18ListGoodModifiersRequest\x12\x19\n" +
+	"\bgood_ids\x18\x01 \x03(\tR\agoodIds\"N\n" +
+	"\x19ListGoodModifiersResponse\x121\n" +
+	"\x05goods\x18\x01 \x03(\v2\x1b.inventory.v1.GoodModifiersR\x05goods*g\n" +
+	"\rSelectionType\x12\x1e\n" +
+	"\x1aSELECTION_TYPE_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15SELECTION_TYPE_SINGLE\x10\x01\x12\x1b\n" +
+	"\x17SELECTION_TYPE_MULTIPLE\x10\x022\x9c\x03\n" +
+	"\x0fModifierService\x12g\n" +
+	"\x12ListModifierGroups\x12'.inventory.v1.ListModifierGroupsRequest\x1a(.inventory.v1.ListModifierGroupsResponse\x12X\n" +
+	"\x11SaveModifierGroup\x12&.inventory.v1.SaveModifierGroupRequest\x1a\x1b.inventory.v1.ModifierGroup\x12`\n" +
+	"\x15SetGoodModifierGroups\x12*.inventory.v1.SetGoodModifierGroupsRequest\x1a\x1b.inventory.v1.GoodModifiers\x12d\n" +
+	"\x11ListGoodModifiers\x12&.inventory.v1.ListGoodModifiersRequest\x1a'.inventory.v1.ListGoodModifiersResponseBHZFimmxrtalbeast/order_microservices/internal/pkg/inventorypb;inventorypbb\x06proto3"
+
+var (
+	file_inventory_v1_modifiers_proto_rawDescOnce sync.Once
+	file_inventory_v1_modifiers_proto_rawDescData []byte
+)
+
+func file_inventory_v1_modifiers_proto_rawDescGZIP() []byte {
+	file_inventory_v1_modifiers_proto_rawDescOnce.Do(func() {
+		file_inventory_v1_modifiers_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_inventory_v1_modifiers_proto_rawDesc), len(file_inventory_v1_modifiers_proto_rawDesc)))
+	})
+	return file_inventory_v1_modifiers_proto_rawDescData
+}
+
+var file_inventory_v1_modifiers_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_inventory_v1_modifiers_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_inventory_v1_modifiers_proto_goTypes = []any{
+	(SelectionType)(0),                   // 0: inventory.v1.SelectionType
+	(*Money)(nil),                        // 1: inventory.v1.Money
+	(*Modifier)(nil),                     // 2: inventory.v1.Modifier
+	(*ModifierGroup)(nil),                // 3: inventory.v1.ModifierGroup
+	(*GoodModifiers)(nil),                // 4: inventory.v1.GoodModifiers
+	(*ListModifierGroupsRequest)(nil),    // 5: inventory.v1.ListModifierGroupsRequest
+	(*ListModifierGroupsResponse)(nil),   // 6: inventory.v1.ListModifierGroupsResponse
+	(*SaveModifierGroupRequest)(nil),     // 7: inventory.v1.SaveModifierGroupRequest
+	(*SetGoodModifierGroupsRequest)(nil), // 8: inventory.v1.SetGoodModifierGroupsRequest
+	(*ListGoodModifiersRequest)(nil),     // 9: inventory.v1.ListGoodModifiersRequest
+	(*ListGoodModifiersResponse)(nil),    // 10: inventory.v1.ListGoodModifiersResponse
+}
+var file_inventory_v1_modifiers_proto_depIdxs = []int32{
+	1,  // 0: inventory.v1.Modifier.price_delta:type_name -> inventory.v1.Money
+	0,  // 1: inventory.v1.ModifierGroup.selection_type:type_name -> inventory.v1.SelectionType
+	2,  // 2: inventory.v1.ModifierGroup.modifiers:type_name -> inventory.v1.Modifier
+	3,  // 3: inventory.v1.GoodModifiers.groups:type_name -> inventory.v1.ModifierGroup
+	3,  // 4: inventory.v1.ListModifierGroupsResponse.groups:type_name -> inventory.v1.ModifierGroup
+	3,  // 5: inventory.v1.SaveModifierGroupRequest.group:type_name -> inventory.v1.ModifierGroup
+	4,  // 6: inventory.v1.ListGoodModifiersResponse.goods:type_name -> inventory.v1.GoodModifiers
+	5,  // 7: inventory.v1.ModifierService.ListModifierGroups:input_type -> inventory.v1.ListModifierGroupsRequest
+	7,  // 8: inventory.v1.ModifierService.SaveModifierGroup:input_type -> inventory.v1.SaveModifierGroupRequest
+	8,  // 9: inventory.v1.ModifierService.SetGoodModifierGroups:input_type -> inventory.v1.SetGoodModifierGroupsRequest
+	9,  // 10: inventory.v1.ModifierService.ListGoodModifiers:input_type -> inventory.v1.ListGoodModifiersRequest
+	6,  // 11: inventory.v1.ModifierService.ListModifierGroups:output_type -> inventory.v1.ListModifierGroupsResponse
+	3,  // 12: inventory.v1.ModifierService.SaveModifierGroup:output_type -> inventory.v1.ModifierGroup
+	4,  // 13: inventory.v1.ModifierService.SetGoodModifierGroups:output_type -> inventory.v1.GoodModifiers
+	10, // 14: inventory.v1.ModifierService.ListGoodModifiers:output_type -> inventory.v1.ListGoodModifiersResponse
+	11, // [11:15] is the sub-list for method output_type
+	7,  // [7:11] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_inventory_v1_modifiers_proto_init() }
+func file_inventory_v1_modifiers_proto_init() {
+	if File_inventory_v1_modifiers_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_inventory_v1_modifiers_proto_rawDesc), len(file_inventory_v1_modifiers_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_inventory_v1_modifiers_proto_goTypes,
+		DependencyIndexes: file_inventory_v1_modifiers_proto_depIdxs,
+		EnumInfos:         file_inventory_v1_modifiers_proto_enumTypes,
+		MessageInfos:      file_inventory_v1_modifiers_proto_msgTypes,
+	}.Build()
+	File_inventory_v1_modifiers_proto = out.File
+	file_inventory_v1_modifiers_proto_goTypes = nil
+	file_inventory_v1_modifiers_proto_depIdxs = nil
+}
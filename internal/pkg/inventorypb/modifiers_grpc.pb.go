@@ -0,0 +1,243 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: inventory/v1/modifiers.proto
+
+package inventorypb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ModifierService_ListModifierGroups_FullMethodName    = "/inventory.v1.ModifierService/ListModifierGroups"
+	ModifierService_SaveModifierGroup_FullMethodName     = "/inventory.v1.ModifierService/SaveModifierGroup"
+	ModifierService_SetGoodModifierGroups_FullMethodName = "/inventory.v1.ModifierService/SetGoodModifierGroups"
+	ModifierService_ListGoodModifiers_FullMethodName     = "/inventory.v1.ModifierService/ListGoodModifiers"
+)
+
+// ModifierServiceClient is the client API for ModifierService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ModifierService manages the modifiers goods are sold with.
+type ModifierServiceClient interface {
+	ListModifierGroups(ctx context.Context, in *ListModifierGroupsRequest, opts ...grpc.CallOption) (*ListModifierGroupsResponse, error)
+	SaveModifierGroup(ctx context.Context, in *SaveModifierGroupRequest, opts ...grpc.CallOption) (*ModifierGroup, error)
+	SetGoodModifierGroups(ctx context.Context, in *SetGoodModifierGroupsRequest, opts ...grpc.CallOption) (*GoodModifiers, error)
+	// ListGoodModifiers answers the modifier groups of goods. Goods without
+	// groups are left out.
+	ListGoodModifiers(ctx context.Context, in *ListGoodModifiersRequest, opts ...grpc.CallOption) (*ListGoodModifiersResponse, error)
+}
+
+type modifierServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewModifierServiceClient(cc grpc.ClientConnInterface) ModifierServiceClient {
+	return &modifierServiceClient{cc}
+}
+
+func (c *modifierServiceClient) ListModifierGroups(ctx context.Context, in *ListModifierGroupsRequest, opts ...grpc.CallOption) (*ListModifierGroupsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListModifierGroupsResponse)
+	err := c.cc.Invoke(ctx, ModifierService_ListModifierGroups_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *modifierServiceClient) SaveModifierGroup(ctx context.Context, in *SaveModifierGroupRequest, opts ...grpc.CallOption) (*ModifierGroup, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ModifierGroup)
+	err := c.cc.Invoke(ctx, ModifierService_SaveModifierGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *modifierServiceClient) SetGoodModifierGroups(ctx context.Context, in *SetGoodModifierGroupsRequest, opts ...grpc.CallOption) (*GoodModifiers, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GoodModifiers)
+	err := c.cc.Invoke(ctx, ModifierService_SetGoodModifierGroups_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *modifierServiceClient) ListGoodModifiers(ctx context.Context, in *ListGoodModifiersRequest, opts ...grpc.CallOption) (*ListGoodModifiersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListGoodModifiersResponse)
+	err := c.cc.Invoke(ctx, ModifierService_ListGoodModifiers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ModifierServiceServer is the server API for ModifierService service.
+// All implementations must embed UnimplementedModifierServiceServer
+// for forward compatibility.
+//
+// ModifierService manages the modifiers goods are sold with.
+type ModifierServiceServer interface {
+	ListModifierGroups(context.Context, *ListModifierGroupsRequest) (*ListModifierGroupsResponse, error)
+	SaveModifierGroup(context.Context, *SaveModifierGroupRequest) (*ModifierGroup, error)
+	SetGoodModifierGroups(context.Context, *SetGoodModifierGroupsRequest) (*GoodModifiers, error)
+	// ListGoodModifiers answers the modifier groups of goods. Goods without
+	// groups are left out.
+	ListGoodModifiers(context.Context, *ListGoodModifiersRequest) (*ListGoodModifiersResponse, error)
+	mustEmbedUnimplementedModifierServiceServer()
+}
+
+// UnimplementedModifierServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedModifierServiceServer struct{}
+
+func (UnimplementedModifierServiceServer) ListModifierGroups(context.Context, *ListModifierGroupsRequest) (*ListModifierGroupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListModifierGroups not implemented")
+}
+func (UnimplementedModifierServiceServer) SaveModifierGroup(context.Context, *SaveModifierGroupRequest) (*ModifierGroup, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveModifierGroup not implemented")
+}
+func (UnimplementedModifierServiceServer) SetGoodModifierGroups(context.Context, *SetGoodModifierGroupsRequest) (*GoodModifiers, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetGoodModifierGroups not implemented")
+}
+func (UnimplementedModifierServiceServer) ListGoodModifiers(context.Context, *ListGoodModifiersRequest) (*ListGoodModifiersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGoodModifiers not implemented")
+}
+func (UnimplementedModifierServiceServer) mustEmbedUnimplementedModifierServiceServer() {}
+func (UnimplementedModifierServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeModifierServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ModifierServiceServer will
+// result in compilation errors.
+type UnsafeModifierServiceServer interface {
+	mustEmbedUnimplementedModifierServiceServer()
+}
+
+func RegisterModifierServiceServer(s grpc.ServiceRegistrar, srv ModifierServiceServer) {
+	// If the following call pancis, it indicates UnimplementedModifierServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ModifierService_ServiceDesc, srv)
+}
+
+func _ModifierService_ListModifierGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListModifierGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ModifierServiceServer).ListModifierGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ModifierService_ListModifierGroups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ModifierServiceServer).ListModifierGroups(ctx, req.(*ListModifierGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ModifierService_SaveModifierGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveModifierGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ModifierServiceServer).SaveModifierGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ModifierService_SaveModifierGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ModifierServiceServer).SaveModifierGroup(ctx, req.(*SaveModifierGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ModifierService_SetGoodModifierGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetGoodModifierGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ModifierServiceServer).SetGoodModifierGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ModifierService_SetGoodModifierGroups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ModifierServiceServer).SetGoodModifierGroups(ctx, req.(*SetGoodModifierGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ModifierService_ListGoodModifiers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGoodModifiersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ModifierServiceServer).ListGoodModifiers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ModifierService_ListGoodModifiers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ModifierServiceServer).ListGoodModifiers(ctx, req.(*ListGoodModifiersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ModifierService_ServiceDesc is the grpc.ServiceDesc for ModifierService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ModifierService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "inventory.v1.ModifierService",
+	HandlerType: (*ModifierServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListModifierGroups",
+			Handler:    _ModifierService_ListModifierGroups_Handler,
+		},
+		{
+			MethodName: "SaveModifierGroup",
+			Handler:    _ModifierService_SaveModifierGroup_Handler,
+		},
+		{
+			MethodName: "SetGoodModifierGroups",
+			Handler:    _ModifierService_SetGoodModifierGroups_Handler,
+		},
+		{
+			MethodName: "ListGoodModifiers",
+			Handler:    _ModifierService_ListGoodModifiers_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "inventory/v1/modifiers.proto",
+}
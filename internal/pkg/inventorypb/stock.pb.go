@@ -0,0 +1,252 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: inventory/v1/stock.proto
+
+package inventorypb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// GoodStock splits the stock of a good: quantity_in_stock is on hand,
+// quantity_held is under open reservations and quantity_available is what
+// is left to reserve.
+type GoodStock struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	GoodId            string                 `protobuf:"bytes,1,opt,name=good_id,json=goodId,proto3" json:"good_id,omitempty"`
+	QuantityInStock   int64                  `protobuf:"varint,2,opt,name=quantity_in_stock,json=quantityInStock,proto3" json:"quantity_in_stock,omitempty"`
+	QuantityHeld      int64                  `protobuf:"varint,3,opt,name=quantity_held,json=quantityHeld,proto3" json:"quantity_held,omitempty"`
+	QuantityAvailable int64                  `protobuf:"varint,4,opt,name=quantity_available,json=quantityAvailable,proto3" json:"quantity_available,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *GoodStock) Reset() {
+	*x = GoodStock{}
+	mi := &file_inventory_v1_stock_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GoodStock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GoodStock) ProtoMessage() {}
+
+func (x *GoodStock) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GoodStock.ProtoReflect.Descriptor instead.
+func (*GoodStock) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GoodStock) GetGoodId() string {
+	if x != nil {
+		return x.GoodId
+	}
+	return ""
+}
+
+func (x *GoodStock) GetQuantityInStock() int64 {
+	if x != nil {
+		return x.QuantityInStock
+	}
+	return 0
+}
+
+func (x *GoodStock) GetQuantityHeld() int64 {
+	if x != nil {
+		return x.QuantityHeld
+	}
+	return 0
+}
+
+func (x *GoodStock) GetQuantityAvailable() int64 {
+	if x != nil {
+		return x.QuantityAvailable
+	}
+	return 0
+}
+
+type ListStockRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// good_ids limits the answer to these goods; empty lists all goods.
+	GoodIds       []string `protobuf:"bytes,1,rep,name=good_ids,json=goodIds,proto3" json:"good_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStockRequest) Reset() {
+	*x = ListStockRequest{}
+	mi := &file_inventory_v1_stock_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStockRequest) ProtoMessage() {}
+
+func (x *ListStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStockRequest.ProtoReflect.Descriptor instead.
+func (*ListStockRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListStockRequest) GetGoodIds() []string {
+	if x != nil {
+		return x.GoodIds
+	}
+	return nil
+}
+
+type ListStockResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Goods         []*GoodStock           `protobuf:"bytes,1,rep,name=goods,proto3" json:"goods,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStockResponse) Reset() {
+	*x = ListStockResponse{}
+	mi := &file_inventory_v1_stock_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStockResponse) ProtoMessage() {}
+
+func (x *ListStockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStockResponse.ProtoReflect.Descriptor instead.
+func (*ListStockResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListStockResponse) GetGoods() []*GoodStock {
+	if x != nil {
+		return x.Goods
+	}
+	return nil
+}
+
+var File_inventory_v1_stock_proto protoreflect.FileDescriptor
+
+const file_inventory_v1_stock_proto_rawDesc = "" +
+	"\n" +
+	"\x18inventory/v1/stock.proto\x12\finventory.v1\"\xa4\x01\n" +
+	"\tGoodStock\x12\x17\n" +
+	"\agood_id\x18\x01 \x01(\tR\x06goodId\x12*\n" +
+	"\x11quantity_in_stock\x18\x02 \x01(\x03R\x0fquantityInStock\x12#\n" +
+	"\rquantity_held\x18\x03 \x01(\x03R\fquantityHeld\x12-\n" +
+	"\x12quantity_available\x18\x04 \x01(\x03R\x11quantityAvailable\"-\n" +
+	"\x10ListStockRequest\x12\x19\n" +
+	"\bgood_ids\x18\x01 \x03(\tR\agoodIds\"B\n" +
+	"\x11ListStockResponse\x12-\n" +
+	"\x05goods\x18\x01 \x03(\v2\x17.inventory.v1.GoodStockR\x05goods2\\\n" +
+	"\fStockService\x12L\n" +
+	"\tListStock\x12\x1e.inventory.v1.ListStockRequest\x1a\x1f.inventory.v1.ListStockResponseBHZFimmxrtalbeast/order_microservices/internal/pkg/inventorypb;inventorypbb\x06proto3"
+
+var (
+	file_inventory_v1_stock_proto_rawDescOnce sync.Once
+	file_inventory_v1_stock_proto_rawDescData []byte
+)
+
+func file_inventory_v1_stock_proto_rawDescGZIP() []byte {
+	file_inventory_v1_stock_proto_rawDescOnce.Do(func() {
+		file_inventory_v1_stock_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_inventory_v1_stock_proto_rawDesc), len(file_inventory_v1_stock_proto_rawDesc)))
+	})
+	return file_inventory_v1_stock_proto_rawDescData
+}
+
+var file_inventory_v1_stock_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_inventory_v1_stock_proto_goTypes = []any{
+	(*GoodStock)(nil),         // 0: inventory.v1.GoodStock
+	(*ListStockRequest)(nil),  // 1: inventory.v1.ListStockRequest
+	(*ListStockResponse)(nil), // 2: inventory.v1.ListStockResponse
+}
+var file_inventory_v1_stock_proto_depIdxs = []int32{
+	0, // 0: inventory.v1.ListStockResponse.goods:type_name -> inventory.v1.GoodStock
+	1, // 1: inventory.v1.StockService.ListStock:input_type -> inventory.v1.ListStockRequest
+	2, // 2: inventory.v1.StockService.ListStock:output_type -> inventory.v1.ListStockResponse
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_inventory_v1_stock_proto_init() }
+func file_inventory_v1_stock_proto_init() {
+	if File_inventory_v1_stock_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_inventory_v1_stock_proto_rawDesc), len(file_inventory_v1_stock_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_inventory_v1_stock_proto_goTypes,
+		DependencyIndexes: file_inventory_v1_stock_proto_depIdxs,
+		MessageInfos:      file_inventory_v1_stock_proto_msgTypes,
+	}.Build()
+	File_inventory_v1_stock_proto = out.File
+	file_inventory_v1_stock_proto_goTypes = nil
+	file_inventory_v1_stock_proto_depIdxs = nil
+}
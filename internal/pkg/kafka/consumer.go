@@ -0,0 +1,70 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type Consumer struct {
+	reader *kafka.Reader
+}
+
+func NewConsumer(brokers []string, topic, groupID string) *Consumer {
+	return &Consumer{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers: brokers,
+			Topic:   topic,
+			GroupID: groupID,
+		}),
+	}
+}
+
+// NewBroadcastConsumer reads topic from its newest message on. Each instance
+// should pass a groupID of its own so that every instance sees every message;
+// offsets are not meant to be committed.
+func NewBroadcastConsumer(brokers []string, topic, groupID string) *Consumer {
+	return &Consumer{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers:     brokers,
+			Topic:       topic,
+			GroupID:     groupID,
+			StartOffset: kafka.LastOffset,
+		}),
+	}
+}
+
+func (c *Consumer) ReadEvent(ctx context.Context, v interface{}) (kafka.Message, error) {
+	msg, err := c.reader.ReadMessage(ctx)
+	if err != nil {
+		return msg, err
+	}
+
+	if err := json.Unmarshal(msg.Value, v); err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
+
+func (c *Consumer) ReadRawMessage(ctx context.Context) (kafka.Message, error) {
+	msg, err := c.reader.ReadMessage(ctx)
+	if err != nil {
+		return msg, err
+	}
+	return msg, nil
+}
+
+// FetchMessage reads the next message without committing its offset.
+func (c *Consumer) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	return c.reader.FetchMessage(ctx)
+}
+
+func (c *Consumer) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	return c.reader.CommitMessages(ctx, msgs...)
+}
+
+func (c *Consumer) Close() error {
+	return c.reader.Close()
+}
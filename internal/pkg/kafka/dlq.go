@@ -0,0 +1,217 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	HeaderDLQError             = "Dlq-Error"
+	HeaderDLQAttempts          = "Dlq-Attempts"
+	HeaderDLQFailedAt          = "Dlq-Failed-At"
+	HeaderDLQOriginalTopic     = "Dlq-Original-Topic"
+	HeaderDLQOriginalPartition = "Dlq-Original-Partition"
+	HeaderDLQOriginalOffset    = "Dlq-Original-Offset"
+
+	dlqSuffix = ".dlq"
+)
+
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+func DeadLetterTopic(topic string) string {
+	return topic + dlqSuffix
+}
+
+// DeadLetter is a message that exhausted its retries.
+type DeadLetter struct {
+	Topic             string    `json:"topic"`
+	Partition         int       `json:"partition"`
+	Offset            int64     `json:"offset"`
+	MessageID         string    `json:"message_id"`
+	EventType         string    `json:"event_type"`
+	ContentType       string    `json:"content_type"`
+	Key               string    `json:"key"`
+	Payload           string    `json:"payload"`
+	Error             string    `json:"error"`
+	Attempts          int       `json:"attempts"`
+	FailedAt          time.Time `json:"failed_at"`
+	OriginalPartition int       `json:"original_partition"`
+	OriginalOffset    int64     `json:"original_offset"`
+}
+
+// DeadLetterQueue publishes failed messages to <topic>.dlq and lets them be
+// listed and re-driven into their original topic.
+type DeadLetterQueue struct {
+	brokers []string
+	writer  *kafka.Writer
+}
+
+func NewDeadLetterQueue(brokers []string) *DeadLetterQueue {
+	return &DeadLetterQueue{
+		brokers: brokers,
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(brokers...),
+			Balancer:               &kafka.Hash{},
+			AllowAutoTopicCreation: true,
+		},
+	}
+}
+
+func (q *DeadLetterQueue) Publish(ctx context.Context, msg kafka.Message, cause error, attempts int) error {
+	headers := withoutDLQHeaders(msg.Headers)
+	headers = append(headers,
+		kafka.Header{Key: HeaderDLQError, Value: []byte(cause.Error())},
+		kafka.Header{Key: HeaderDLQAttempts, Value: []byte(strconv.Itoa(attempts))},
+		kafka.Header{Key: HeaderDLQFailedAt, Value: []byte(time.Now().UTC().Format(time.RFC3339Nano))},
+		kafka.Header{Key: HeaderDLQOriginalTopic, Value: []byte(msg.Topic)},
+		kafka.Header{Key: HeaderDLQOriginalPartition, Value: []byte(strconv.Itoa(msg.Partition))},
+		kafka.Header{Key: HeaderDLQOriginalOffset, Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+	)
+	return q.writer.WriteMessages(ctx, kafka.Message{
+		Topic:   DeadLetterTopic(msg.Topic),
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+	})
+}
+
+// List returns up to limit dead letters of topic, oldest first per partition.
+func (q *DeadLetterQueue) List(ctx context.Context, topic string, limit int) ([]DeadLetter, error) {
+	dlqTopic := DeadLetterTopic(topic)
+	conn, err := kafka.DialContext(ctx, "tcp", q.brokers[0])
+	if err != nil {
+		return nil, fmt.Errorf("dial kafka: %w", err)
+	}
+	partitions, err := conn.ReadPartitions(dlqTopic)
+	conn.Close()
+	if err != nil {
+		if errors.Is(err, kafka.UnknownTopicOrPartition) {
+			return []DeadLetter{}, nil
+		}
+		return nil, fmt.Errorf("read partitions of %s: %w", dlqTopic, err)
+	}
+
+	letters := make([]DeadLetter, 0)
+	for _, p := range partitions {
+		if len(letters) >= limit {
+			break
+		}
+		msgs, err := q.readPartition(ctx, dlqTopic, p.ID, -1, limit-len(letters))
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range msgs {
+			letters = append(letters, toDeadLetter(msg))
+		}
+	}
+	return letters, nil
+}
+
+// Redrive publishes the dead letter at partition/offset of <topic>.dlq back
+// to topic under its original message ID, so consumers that already handled
+// it still skip it.
+func (q *DeadLetterQueue) Redrive(ctx context.Context, topic string, partition int, offset int64) error {
+	msgs, err := q.readPartition(ctx, DeadLetterTopic(topic), partition, offset, 1)
+	if err != nil {
+		return err
+	}
+	if len(msgs) == 0 || msgs[0].Offset != offset {
+		return ErrDeadLetterNotFound
+	}
+	msg := msgs[0]
+	return q.writer.WriteMessages(ctx, kafka.Message{
+		Topic:   topic,
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: withoutDLQHeaders(msg.Headers),
+	})
+}
+
+func (q *DeadLetterQueue) Close() error {
+	return q.writer.Close()
+}
+
+// readPartition reads up to limit messages starting at from, or at the first
+// retained offset when from is negative.
+func (q *DeadLetterQueue) readPartition(ctx context.Context, topic string, partition int, from int64, limit int) ([]kafka.Message, error) {
+	leader, err := kafka.DialLeader(ctx, "tcp", q.brokers[0], topic, partition)
+	if err != nil {
+		if errors.Is(err, kafka.UnknownTopicOrPartition) {
+			return nil, ErrDeadLetterNotFound
+		}
+		return nil, fmt.Errorf("dial leader of %s/%d: %w", topic, partition, err)
+	}
+	first, last, err := leader.ReadOffsets()
+	leader.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read offsets of %s/%d: %w", topic, partition, err)
+	}
+	if from < 0 {
+		from = first
+	}
+	if from < first || from >= last {
+		return nil, nil
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   q.brokers,
+		Topic:     topic,
+		Partition: partition,
+	})
+	defer reader.Close()
+	if err := reader.SetOffset(from); err != nil {
+		return nil, fmt.Errorf("seek %s/%d to %d: %w", topic, partition, from, err)
+	}
+
+	msgs := make([]kafka.Message, 0)
+	for len(msgs) < limit {
+		msg, err := reader.ReadMessage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("read %s/%d: %w", topic, partition, err)
+		}
+		msgs = append(msgs, msg)
+		if msg.Offset >= last-1 {
+			break
+		}
+	}
+	return msgs, nil
+}
+
+func toDeadLetter(msg kafka.Message) DeadLetter {
+	attempts, _ := strconv.Atoi(Header(msg, HeaderDLQAttempts))
+	failedAt, _ := time.Parse(time.RFC3339Nano, Header(msg, HeaderDLQFailedAt))
+	partition, _ := strconv.Atoi(Header(msg, HeaderDLQOriginalPartition))
+	offset, _ := strconv.ParseInt(Header(msg, HeaderDLQOriginalOffset), 10, 64)
+	return DeadLetter{
+		Topic:             msg.Topic,
+		Partition:         msg.Partition,
+		Offset:            msg.Offset,
+		MessageID:         Header(msg, HeaderMessageID),
+		EventType:         Header(msg, HeaderEventType),
+		ContentType:       Header(msg, HeaderContentType),
+		Key:               string(msg.Key),
+		Payload:           string(msg.Value),
+		Error:             Header(msg, HeaderDLQError),
+		Attempts:          attempts,
+		FailedAt:          failedAt,
+		OriginalPartition: partition,
+		OriginalOffset:    offset,
+	}
+}
+
+func withoutDLQHeaders(headers []kafka.Header) []kafka.Header {
+	out := make([]kafka.Header, 0, len(headers))
+	for _, h := range headers {
+		if strings.HasPrefix(h.Key, "Dlq-") {
+			continue
+		}
+		out = append(out, h)
+	}
+	return out
+}
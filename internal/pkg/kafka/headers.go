@@ -0,0 +1,21 @@
+package kafka
+
+import "github.com/segmentio/kafka-go"
+
+const (
+	HeaderEventType   = "Event-Type"
+	HeaderMessageID   = "Message-Id"
+	HeaderContentType = "Content-Type"
+
+	contentTypeJSON = "application/json"
+)
+
+// Header returns the value of the first header named key, or "" if msg has none.
+func Header(msg kafka.Message, key string) string {
+	for _, h := range msg.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
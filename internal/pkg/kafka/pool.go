@@ -0,0 +1,267 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Handler processes a single message. The offset of msg is committed once
+// Handler returns nil or its retries run out, after the message has been
+// moved to the dead-letter topic. Errors are retried unless wrapped with
+// Permanent.
+type Handler func(ctx context.Context, msg kafka.Message) error
+
+// PoolConfig configures a Pool. Retry applies to every event type that has no
+// entry in EventRetry. Without DeadLetters, or when publishing to it fails, a
+// message that exhausts its retries is only logged, so it cannot hold back the
+// offsets after it.
+type PoolConfig struct {
+	Workers        int
+	QueueSize      int
+	HandlerTimeout time.Duration
+	Retry          RetryPolicy
+	EventRetry     map[string]RetryPolicy
+	DeadLetters    *DeadLetterQueue
+}
+
+// Pool consumes messages with a fixed number of workers. Messages with the
+// same key always go to the same worker, so they are handled in order.
+type Pool struct {
+	consumer messageSource
+	handler  Handler
+	log      *slog.Logger
+	cfg      PoolConfig
+	queues   []chan *trackedMessage
+	offsets  *offsetTracker
+
+	// handlerCtx outlives Run so in-flight handlers can finish while draining.
+	handlerCtx    context.Context
+	cancelHandler context.CancelFunc
+	workers       sync.WaitGroup
+	done          chan struct{}
+}
+
+// messageSource is the part of Consumer a Pool uses.
+type messageSource interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+func NewPool(consumer *Consumer, handler Handler, log *slog.Logger, cfg PoolConfig) *Pool {
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+	cfg.Retry = cfg.Retry.orDefault(DefaultRetryPolicy)
+	handlerCtx, cancel := context.WithCancel(context.Background())
+	p := &Pool{
+		consumer:      consumer,
+		handler:       handler,
+		log:           log,
+		cfg:           cfg,
+		queues:        make([]chan *trackedMessage, cfg.Workers),
+		offsets:       newOffsetTracker(),
+		handlerCtx:    handlerCtx,
+		cancelHandler: cancel,
+		done:          make(chan struct{}),
+	}
+	for i := range p.queues {
+		p.queues[i] = make(chan *trackedMessage, cfg.QueueSize)
+	}
+	return p
+}
+
+// Run fetches messages until ctx is cancelled, then stops the workers once
+// their queues are drained. Use Shutdown to wait for that.
+func (p *Pool) Run(ctx context.Context) {
+	p.log.Info("listening kafka", slog.Int("workers", len(p.queues)))
+	for _, queue := range p.queues {
+		p.workers.Add(1)
+		go p.work(queue)
+	}
+	defer func() {
+		for _, queue := range p.queues {
+			close(queue)
+		}
+		p.workers.Wait()
+		close(p.done)
+	}()
+
+	for {
+		msg, err := p.consumer.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				p.log.Info("kafka consumer stopped")
+				return
+			}
+			p.log.Error("failed to fetch message", slog.String("error", err.Error()))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		tracked := p.offsets.track(msg)
+		select {
+		case p.queues[p.worker(msg)] <- tracked:
+		case <-ctx.Done():
+			p.log.Info("kafka consumer stopped")
+			return
+		}
+	}
+}
+
+// Shutdown waits for in-flight messages to be handled. When ctx expires first
+// the running handlers are cancelled and their offsets stay uncommitted.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	select {
+	case <-p.done:
+		p.cancelHandler()
+		return nil
+	case <-ctx.Done():
+		p.cancelHandler()
+		<-p.done
+		return ctx.Err()
+	}
+}
+
+func (p *Pool) work(queue <-chan *trackedMessage) {
+	defer p.workers.Done()
+	for tracked := range queue {
+		if p.handlerCtx.Err() != nil {
+			continue
+		}
+		if p.process(tracked.msg) {
+			p.commit(tracked)
+		}
+	}
+}
+
+// process handles msg under its retry policy and reports whether its offset
+// may be committed, which is always the case unless the pool is shutting down
+// and msg has to be redelivered.
+func (p *Pool) process(msg kafka.Message) bool {
+	eventType := Header(msg, HeaderEventType)
+	policy := p.cfg.EventRetry[eventType].orDefault(p.cfg.Retry)
+	log := p.log.With(
+		slog.String("topic", msg.Topic),
+		slog.Int("partition", msg.Partition),
+		slog.Int64("offset", msg.Offset),
+		slog.String("event_type", eventType),
+	)
+
+	var err error
+	attempt := 1
+	for ; ; attempt++ {
+		ctx, cancel := context.WithTimeout(p.handlerCtx, p.cfg.HandlerTimeout)
+		err = p.handler(ctx, msg)
+		cancel()
+		if err == nil {
+			return true
+		}
+		if isPermanent(err) || attempt >= policy.MaxAttempts {
+			break
+		}
+		delay := policy.backoff(attempt)
+		log.Warn("handler failed, retrying",
+			slog.Int("attempt", attempt),
+			slog.Duration("backoff", delay),
+			slog.String("error", err.Error()),
+		)
+		select {
+		case <-p.handlerCtx.Done():
+			return false
+		case <-time.After(delay):
+		}
+	}
+
+	log.Error("failed to handle message", slog.Int("attempts", attempt), slog.String("error", err.Error()))
+	if p.cfg.DeadLetters == nil {
+		log.Error("message dropped, no dead-letter topic configured")
+		return true
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := p.cfg.DeadLetters.Publish(ctx, msg, err, attempt); err != nil {
+		log.Error("failed to publish to dead-letter topic, message dropped", slog.String("error", err.Error()))
+		return true
+	}
+	log.Warn("message moved to dead-letter topic", slog.String("dlq_topic", DeadLetterTopic(msg.Topic)))
+	return true
+}
+
+func (p *Pool) commit(tracked *trackedMessage) {
+	p.offsets.mu.Lock()
+	defer p.offsets.mu.Unlock()
+	msg, ok := p.offsets.complete(tracked)
+	if !ok {
+		return
+	}
+	// The consumer may already be closing, so commits get their own deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := p.consumer.CommitMessages(ctx, msg); err != nil && !errors.Is(err, context.Canceled) {
+		p.log.Error("failed to commit offset",
+			slog.String("topic", msg.Topic),
+			slog.Int("partition", msg.Partition),
+			slog.Int64("offset", msg.Offset),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+func (p *Pool) worker(msg kafka.Message) int {
+	key := msg.Key
+	if len(key) == 0 {
+		key = []byte(strconv.Itoa(msg.Partition))
+	}
+	h := fnv.New32a()
+	h.Write(key)
+	return int(h.Sum32() % uint32(len(p.queues)))
+}
+
+type trackedMessage struct {
+	msg  kafka.Message
+	done bool
+}
+
+// offsetTracker finds, per partition, the highest offset below which every
+// message has been handled. Only that offset is safe to commit.
+type offsetTracker struct {
+	mu         sync.Mutex
+	partitions map[int][]*trackedMessage
+}
+
+func newOffsetTracker() *offsetTracker {
+	return &offsetTracker{partitions: make(map[int][]*trackedMessage)}
+}
+
+func (t *offsetTracker) track(msg kafka.Message) *trackedMessage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tracked := &trackedMessage{msg: msg}
+	t.partitions[msg.Partition] = append(t.partitions[msg.Partition], tracked)
+	return tracked
+}
+
+// complete must be called with t.mu held.
+func (t *offsetTracker) complete(tracked *trackedMessage) (kafka.Message, bool) {
+	tracked.done = true
+	pending := t.partitions[tracked.msg.Partition]
+	var last kafka.Message
+	advanced := false
+	for len(pending) > 0 && pending[0].done {
+		last = pending[0].msg
+		advanced = true
+		pending = pending[1:]
+	}
+	t.partitions[tracked.msg.Partition] = pending
+	return last, advanced
+}
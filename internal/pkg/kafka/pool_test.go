@@ -0,0 +1,167 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type position struct {
+	partition int
+	offset    int64
+}
+
+func TestOffsetTrackerCommitsContiguousPrefix(t *testing.T) {
+	tests := []struct {
+		name    string
+		fetched []position
+		handled []position
+		// want is the offset committed after each handled message, -1 for none.
+		want []int64
+	}{
+		{
+			name:    "in order",
+			fetched: []position{{0, 10}, {0, 11}, {0, 12}},
+			handled: []position{{0, 10}, {0, 11}, {0, 12}},
+			want:    []int64{10, 11, 12},
+		},
+		{
+			name:    "later offset waits for the earlier one",
+			fetched: []position{{0, 10}, {0, 11}, {0, 12}},
+			handled: []position{{0, 12}, {0, 11}, {0, 10}},
+			want:    []int64{-1, -1, 12},
+		},
+		{
+			name:    "gap closes in the middle",
+			fetched: []position{{0, 10}, {0, 11}, {0, 12}, {0, 13}},
+			handled: []position{{0, 11}, {0, 10}, {0, 13}, {0, 12}},
+			want:    []int64{-1, 11, -1, 13},
+		},
+		{
+			name:    "partitions do not wait for each other",
+			fetched: []position{{0, 10}, {1, 50}, {0, 11}, {1, 51}},
+			handled: []position{{1, 50}, {0, 11}, {1, 51}, {0, 10}},
+			want:    []int64{50, -1, 51, 11},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := newOffsetTracker()
+			tracked := make(map[position]*trackedMessage)
+			for _, pos := range tt.fetched {
+				tracked[pos] = tracker.track(kafka.Message{Partition: pos.partition, Offset: pos.offset})
+			}
+
+			var got []int64
+			for _, pos := range tt.handled {
+				msg, ok := tracker.complete(tracked[pos])
+				switch {
+				case !ok:
+					got = append(got, -1)
+				case msg.Partition != pos.partition:
+					t.Fatalf("handling partition %d committed partition %d", pos.partition, msg.Partition)
+				default:
+					got = append(got, msg.Offset)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("commits = %v, want %v", got, tt.want)
+			}
+			for partition, pending := range tracker.partitions {
+				if len(pending) != 0 {
+					t.Errorf("partition %d still tracks %d messages", partition, len(pending))
+				}
+			}
+		})
+	}
+}
+
+func TestPoolWorkerKeepsKeyOnOneWorker(t *testing.T) {
+	p := &Pool{queues: make([]chan *trackedMessage, 8)}
+	for _, key := range []string{"order-1", "order-2", "order-3"} {
+		want := p.worker(kafka.Message{Key: []byte(key), Partition: 0})
+		for partition := 1; partition < 4; partition++ {
+			if got := p.worker(kafka.Message{Key: []byte(key), Partition: partition}); got != want {
+				t.Errorf("key %s went to worker %d on partition %d, %d on partition 0", key, got, partition, want)
+			}
+		}
+	}
+}
+
+// fakeSource hands out messages, then blocks until the pool stops. fetched is
+// closed once every message has been queued.
+type fakeSource struct {
+	messages  []kafka.Message
+	fetched   chan struct{}
+	mu        sync.Mutex
+	committed []int64
+}
+
+func (s *fakeSource) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	if len(s.messages) == 0 {
+		close(s.fetched)
+		<-ctx.Done()
+		return kafka.Message{}, ctx.Err()
+	}
+	msg := s.messages[0]
+	s.messages = s.messages[1:]
+	return msg, nil
+}
+
+func (s *fakeSource) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, msg := range msgs {
+		s.committed = append(s.committed, msg.Offset)
+	}
+	return nil
+}
+
+func TestPoolCommitsPastFailedMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "permanent error", err: Permanent(errors.New("bad payload"))},
+		{name: "retries exhausted", err: errors.New("db down")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &fakeSource{
+				messages: []kafka.Message{{Offset: 10}, {Offset: 11}, {Offset: 12}},
+				fetched:  make(chan struct{}),
+			}
+			handler := func(ctx context.Context, msg kafka.Message) error {
+				if msg.Offset == 10 {
+					return tt.err
+				}
+				return nil
+			}
+			p := NewPool(nil, handler, slog.New(slog.NewTextHandler(io.Discard, nil)), PoolConfig{
+				Workers:        1,
+				HandlerTimeout: time.Second,
+				Retry:          RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+			})
+			p.consumer = source
+
+			ctx, cancel := context.WithCancel(context.Background())
+			go p.Run(ctx)
+			<-source.fetched
+			cancel()
+			if err := p.Shutdown(context.Background()); err != nil {
+				t.Fatalf("shutdown: %v", err)
+			}
+
+			if len(source.committed) == 0 || source.committed[len(source.committed)-1] != 12 {
+				t.Errorf("committed %v, want up to 12 past the failed 10", source.committed)
+			}
+		})
+	}
+}
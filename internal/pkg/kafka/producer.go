@@ -0,0 +1,98 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+type Producer struct {
+	writer *kafka.Writer
+}
+
+func NewProducer(brokers []string, topic string) *Producer {
+	return &Producer{
+		writer: &kafka.Writer{
+			Addr:     kafka.TCP(brokers...),
+			Topic:    topic,
+			Balancer: &kafka.Hash{},
+		},
+	}
+}
+
+func (p *Producer) PublishEvent(ctx context.Context, key string, event interface{}) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	headers := traceHeaders(ctx)
+	headers = append(headers,
+		kafka.Header{Key: HeaderMessageID, Value: []byte(uuid.NewString())},
+		kafka.Header{Key: HeaderContentType, Value: []byte(contentTypeJSON)},
+	)
+
+	return p.writer.WriteMessages(ctx, kafka.Message{
+		Key:     []byte(key),
+		Value:   payload,
+		Headers: headers,
+	})
+}
+
+// PublishEventWithEventType publishes event under a freshly generated message ID.
+func (p *Producer) PublishEventWithEventType(ctx context.Context, key string, event interface{}, eventType string) error {
+	return p.PublishEventWithID(ctx, uuid.NewString(), key, event, eventType)
+}
+
+// PublishEventWithID publishes event as JSON under messageID, which consumers
+// use to drop redelivered copies. Re-publishing the same event must reuse its ID.
+func (p *Producer) PublishEventWithID(ctx context.Context, messageID, key string, event interface{}, eventType string) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	return p.PublishMessage(ctx, messageID, key, payload, eventType, contentTypeJSON)
+}
+
+// PublishMessage publishes an already encoded value, announcing its encoding
+// in the Content-Type header so consumers know how to decode it.
+func (p *Producer) PublishMessage(ctx context.Context, messageID, key string, value []byte, eventType, contentType string) error {
+	tracer := otel.Tracer("kafka-producer")
+	ctx, span := tracer.Start(ctx, "KafkaProducer.PublishMessage")
+	defer span.End()
+
+	headers := traceHeaders(ctx)
+	headers = append(headers,
+		kafka.Header{Key: HeaderEventType, Value: []byte(eventType)},
+		kafka.Header{Key: HeaderMessageID, Value: []byte(messageID)},
+		kafka.Header{Key: HeaderContentType, Value: []byte(contentType)},
+	)
+	return p.writer.WriteMessages(ctx, kafka.Message{
+		Key:     []byte(key),
+		Value:   value,
+		Headers: headers,
+	})
+}
+
+func (p *Producer) Close() error {
+	return p.writer.Close()
+}
+
+func traceHeaders(ctx context.Context) []kafka.Header {
+	headers := make([]kafka.Header, 0)
+
+	propagator := propagation.TraceContext{}
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	for k, v := range carrier {
+		headers = append(headers, kafka.Header{
+			Key:   k,
+			Value: []byte(v),
+		})
+	}
+	return headers
+}
@@ -0,0 +1,87 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// TopicSpec describes a topic a service relies on.
+type TopicSpec struct {
+	Name              string
+	Partitions        int
+	ReplicationFactor int
+}
+
+// EnsureTopics creates missing topics and grows existing ones that have
+// fewer partitions than requested. Partitions are never removed. Growing a
+// topic remaps keys to partitions, so ordering holds only for messages
+// produced after the change.
+func EnsureTopics(ctx context.Context, brokers []string, topics ...TopicSpec) error {
+	conn, err := kafka.DialContext(ctx, "tcp", brokers[0])
+	if err != nil {
+		return fmt.Errorf("dial kafka: %w", err)
+	}
+	defer conn.Close()
+	controller, err := conn.Controller()
+	if err != nil {
+		return fmt.Errorf("find controller: %w", err)
+	}
+	controllerAddr := net.JoinHostPort(controller.Host, strconv.Itoa(controller.Port))
+	controllerConn, err := kafka.DialContext(ctx, "tcp", controllerAddr)
+	if err != nil {
+		return fmt.Errorf("dial controller: %w", err)
+	}
+	defer controllerConn.Close()
+
+	client := &kafka.Client{Addr: kafka.TCP(controllerAddr)}
+	for _, topic := range topics {
+		partitions, err := conn.ReadPartitions(topic.Name)
+		if err != nil && !errors.Is(err, kafka.UnknownTopicOrPartition) {
+			return fmt.Errorf("read partitions of %s: %w", topic.Name, err)
+		}
+		if len(partitions) == 0 {
+			err := controllerConn.CreateTopics(kafka.TopicConfig{
+				Topic:             topic.Name,
+				NumPartitions:     topic.Partitions,
+				ReplicationFactor: topic.ReplicationFactor,
+			})
+			if err != nil && !errors.Is(err, kafka.TopicAlreadyExists) {
+				return fmt.Errorf("create topic %s: %w", topic.Name, err)
+			}
+			continue
+		}
+		if len(partitions) >= topic.Partitions {
+			continue
+		}
+		resp, err := client.CreatePartitions(ctx, &kafka.CreatePartitionsRequest{
+			Topics: []kafka.TopicPartitionsConfig{{
+				Name:  topic.Name,
+				Count: int32(topic.Partitions),
+			}},
+		})
+		if err != nil {
+			return fmt.Errorf("add partitions to %s: %w", topic.Name, err)
+		}
+		if err := resp.Errors[topic.Name]; err != nil {
+			return fmt.Errorf("add partitions to %s: %w", topic.Name, err)
+		}
+	}
+	return nil
+}
+
+// TopicsWithDeadLetters returns specs for names and their dead-letter topics.
+func TopicsWithDeadLetters(partitions, replicationFactor int, names ...string) []TopicSpec {
+	specs := make([]TopicSpec, 0, 2*len(names))
+	for _, name := range names {
+		specs = append(specs,
+			TopicSpec{Name: name, Partitions: partitions, ReplicationFactor: replicationFactor},
+			TopicSpec{Name: DeadLetterTopic(name), Partitions: partitions, ReplicationFactor: replicationFactor},
+		)
+	}
+	return specs
+}
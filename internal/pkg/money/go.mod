@@ -0,0 +1,3 @@
+module immxrtalbeast/order_microservices/internal/pkg/money
+
+go 1.24.5
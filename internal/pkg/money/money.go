@@ -0,0 +1,162 @@
+// Package money represents amounts exactly, as an integer number of minor
+// units (kopecks for RUB) and an ISO 4217 currency code.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is used where an amount arrives without a currency.
+const DefaultCurrency = "RUB"
+
+var (
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrInvalidAmount    = errors.New("invalid amount")
+)
+
+// Money is an amount in the minor units of Currency. Its zero value is an
+// empty amount in no currency, which adds to any amount.
+type Money struct {
+	Amount   int64  `json:"amount" gorm:"not null;default:0"`
+	Currency string `json:"currency" gorm:"type:char(3);not null;default:'RUB'"`
+}
+
+// exponents lists currencies whose minor unit is not a hundredth.
+var exponents = map[string]int{
+	"JPY": 0,
+	"KRW": 0,
+	"BHD": 3,
+	"KWD": 3,
+}
+
+func exponent(currency string) int {
+	if e, ok := exponents[currency]; ok {
+		return e
+	}
+	return 2
+}
+
+func scale(currency string) int64 {
+	return int64(math.Pow10(exponent(currency)))
+}
+
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// FromMajor converts whole units, such as rubles, to Money.
+func FromMajor(units int64, currency string) Money {
+	return Money{Amount: units * scale(currency), Currency: currency}
+}
+
+// FromFloat converts an amount in major units, rounding half away from zero to
+// the nearest minor unit. Use it only at boundaries that carry floats.
+func FromFloat(v float64, currency string) Money {
+	return Money{Amount: int64(math.Round(v * float64(scale(currency)))), Currency: currency}
+}
+
+// Parse reads a decimal amount in major units such as "1299.90". Digits beyond
+// the currency's minor unit are rounded half away from zero.
+func Parse(s, currency string) (Money, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	digits := exponent(currency)
+	var round bool
+	if len(frac) > digits {
+		round = frac[digits] >= '5'
+		if strings.Trim(frac[digits:], "0123456789") != "" {
+			return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+		}
+		frac = frac[:digits]
+	}
+	frac += strings.Repeat("0", digits-len(frac))
+	if whole == "" {
+		whole = "0"
+	}
+	amount, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	if round {
+		amount++
+	}
+	if negative {
+		amount = -amount
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Add returns m + o. Amounts in different currencies cannot be added.
+func (m Money) Add(o Money) (Money, error) {
+	switch {
+	case m.Currency == "":
+		m.Currency = o.Currency
+	case o.Currency != "" && o.Currency != m.Currency:
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	m.Amount += o.Amount
+	return m, nil
+}
+
+// Sub returns m - o. Amounts in different currencies cannot be subtracted.
+func (m Money) Sub(o Money) (Money, error) {
+	o.Amount = -o.Amount
+	return m.Add(o)
+}
+
+func (m Money) Mul(n int) Money {
+	m.Amount *= int64(n)
+	return m
+}
+
+// Sum adds amounts, which must all be in the same currency.
+func Sum(amounts ...Money) (Money, error) {
+	var total Money
+	for _, a := range amounts {
+		var err error
+		if total, err = total.Add(a); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// Float returns the amount in major units for APIs that still carry floats.
+func (m Money) Float() float64 {
+	return float64(m.Amount) / float64(scale(m.Currency))
+}
+
+// Decimal formats the amount in major units, e.g. "1299.90".
+func (m Money) Decimal() string {
+	digits := exponent(m.Currency)
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	s := strconv.FormatInt(amount, 10)
+	if digits == 0 {
+		return sign + s
+	}
+	if len(s) <= digits {
+		s = strings.Repeat("0", digits-len(s)+1) + s
+	}
+	return sign + s[:len(s)-digits] + "." + s[len(s)-digits:]
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
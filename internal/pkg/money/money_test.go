@@ -0,0 +1,106 @@
+package money
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in       string
+		currency string
+		want     int64
+		wantErr  error
+	}{
+		{in: "1299.90", currency: "RUB", want: 129990},
+		{in: "1299.9", currency: "RUB", want: 129990},
+		{in: "1299", currency: "RUB", want: 129900},
+		{in: ".5", currency: "RUB", want: 50},
+		// 1.005 is 1.00499... as a float; parsed as a decimal it rounds up.
+		{in: "1.005", currency: "RUB", want: 101},
+		{in: "1.004", currency: "RUB", want: 100},
+		{in: "-1.005", currency: "RUB", want: -101},
+		{in: "0.999", currency: "RUB", want: 100},
+		{in: "1500", currency: "JPY", want: 1500},
+		{in: "1500.5", currency: "JPY", want: 1501},
+		{in: "1.2345", currency: "KWD", want: 1235},
+		{in: "", currency: "RUB", wantErr: ErrInvalidAmount},
+		{in: ".", currency: "RUB", wantErr: ErrInvalidAmount},
+		{in: "12,50", currency: "RUB", wantErr: ErrInvalidAmount},
+		{in: "1.2.3", currency: "RUB", wantErr: ErrInvalidAmount},
+		{in: "1.00x", currency: "RUB", wantErr: ErrInvalidAmount},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in, tt.currency)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("Parse(%q, %s) err = %v, want %v", tt.in, tt.currency, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (got.Amount != tt.want || got.Currency != tt.currency) {
+			t.Errorf("Parse(%q, %s) = %d %s, want %d %s", tt.in, tt.currency, got.Amount, got.Currency, tt.want, tt.currency)
+		}
+	}
+}
+
+// Summing item prices as floats drifts by a kopeck; summing Money does not.
+func TestSumIsExactInMinorUnits(t *testing.T) {
+	price := FromFloat(0.1, "RUB")
+	total, err := Sum(price.Mul(3), FromFloat(0.2, "RUB"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total.Amount != 50 || total.Decimal() != "0.50" {
+		t.Errorf("total = %d (%s), want 50 (0.50)", total.Amount, total.Decimal())
+	}
+}
+
+func TestAddCurrencies(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    Money
+		want    Money
+		wantErr error
+	}{
+		{name: "same currency", a: New(100, "RUB"), b: New(250, "RUB"), want: New(350, "RUB")},
+		{name: "zero value takes the other currency", a: Money{}, b: New(250, "USD"), want: New(250, "USD")},
+		{name: "amount without currency", a: New(100, "RUB"), b: Money{Amount: 5}, want: New(105, "RUB")},
+		{name: "mixed currencies", a: New(100, "RUB"), b: New(100, "USD"), wantErr: ErrCurrencyMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.a.Add(tt.b)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if _, err := Sum(New(1, "RUB"), New(1, "RUB"), New(1, "EUR")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Sum over mixed currencies err = %v, want %v", err, ErrCurrencyMismatch)
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	tests := []struct {
+		m    Money
+		want string
+	}{
+		{New(129990, "RUB"), "1299.90"},
+		{New(5, "RUB"), "0.05"},
+		{New(0, "RUB"), "0.00"},
+		{New(-5, "RUB"), "-0.05"},
+		{New(-129990, "RUB"), "-1299.90"},
+		{New(1500, "JPY"), "1500"},
+		{New(1235, "KWD"), "1.235"},
+	}
+	for _, tt := range tests {
+		if got := tt.m.Decimal(); got != tt.want {
+			t.Errorf("%d %s Decimal() = %q, want %q", tt.m.Amount, tt.m.Currency, got, tt.want)
+		}
+		if back, err := Parse(tt.want, tt.m.Currency); err != nil || back != tt.m {
+			t.Errorf("Parse(%q) = %v, %v, want %v back", tt.want, back, err, tt.m)
+		}
+	}
+}
@@ -0,0 +1,972 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: order/v1/cart.proto
+
+package orderpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CartItemAvailability int32
+
+const (
+	CartItemAvailability_CART_ITEM_AVAILABILITY_UNSPECIFIED CartItemAvailability = 0
+	CartItemAvailability_CART_ITEM_AVAILABILITY_IN_STOCK    CartItemAvailability = 1
+	// INSUFFICIENT_STOCK lines ask for more than is available right now.
+	CartItemAvailability_CART_ITEM_AVAILABILITY_INSUFFICIENT_STOCK CartItemAvailability = 2
+	// UNAVAILABLE lines are for products no longer sold.
+	CartItemAvailability_CART_ITEM_AVAILABILITY_UNAVAILABLE CartItemAvailability = 3
+)
+
+// Enum value maps for CartItemAvailability.
+var (
+	CartItemAvailability_name = map[int32]string{
+		0: "CART_ITEM_AVAILABILITY_UNSPECIFIED",
+		1: "CART_ITEM_AVAILABILITY_IN_STOCK",
+		2: "CART_ITEM_AVAILABILITY_INSUFFICIENT_STOCK",
+		3: "CART_ITEM_AVAILABILITY_UNAVAILABLE",
+	}
+	CartItemAvailability_value = map[string]int32{
+		"CART_ITEM_AVAILABILITY_UNSPECIFIED":        0,
+		"CART_ITEM_AVAILABILITY_IN_STOCK":           1,
+		"CART_ITEM_AVAILABILITY_INSUFFICIENT_STOCK": 2,
+		"CART_ITEM_AVAILABILITY_UNAVAILABLE":        3,
+	}
+)
+
+func (x CartItemAvailability) Enum() *CartItemAvailability {
+	p := new(CartItemAvailability)
+	*p = x
+	return p
+}
+
+func (x CartItemAvailability) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CartItemAvailability) Descriptor() protoreflect.EnumDescriptor {
+	return file_order_v1_cart_proto_enumTypes[0].Descriptor()
+}
+
+func (CartItemAvailability) Type() protoreflect.EnumType {
+	return &file_order_v1_cart_proto_enumTypes[0]
+}
+
+func (x CartItemAvailability) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CartItemAvailability.Descriptor instead.
+func (CartItemAvailability) EnumDescriptor() ([]byte, []int) {
+	return file_order_v1_cart_proto_rawDescGZIP(), []int{0}
+}
+
+// CartOwner picks a cart: the user's cart when user_id is set, otherwise the
+// anonymous cart cart_id.
+type CartOwner struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CartId        string                 `protobuf:"bytes,2,opt,name=cart_id,json=cartId,proto3" json:"cart_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CartOwner) Reset() {
+	*x = CartOwner{}
+	mi := &file_order_v1_cart_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CartOwner) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CartOwner) ProtoMessage() {}
+
+func (x *CartOwner) ProtoReflect() protoreflect.Message {
+	mi := &file_order_v1_cart_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CartOwner.ProtoReflect.Descriptor instead.
+func (*CartOwner) Descriptor() ([]byte, []int) {
+	return file_order_v1_cart_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CartOwner) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CartOwner) GetCartId() string {
+	if x != nil {
+		return x.CartId
+	}
+	return ""
+}
+
+// CartItem is a cart line priced with the current price of its product and
+// modifiers. Lines of the same product with different modifiers are kept
+// apart.
+type CartItem struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProductId string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity  int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Name      string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Volume    int32                  `protobuf:"varint,4,opt,name=volume,proto3" json:"volume,omitempty"`
+	UnitPrice *Money                 `protobuf:"bytes,5,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
+	LineTotal *Money                 `protobuf:"bytes,6,opt,name=line_total,json=lineTotal,proto3" json:"line_total,omitempty"`
+	// available_quantity is the least of what is left of the product and of
+	// its modifiers that track stock.
+	AvailableQuantity int64                `protobuf:"varint,7,opt,name=available_quantity,json=availableQuantity,proto3" json:"available_quantity,omitempty"`
+	Availability      CartItemAvailability `protobuf:"varint,8,opt,name=availability,proto3,enum=order.v1.CartItemAvailability" json:"availability,omitempty"`
+	Modifiers         []*CartItemModifier  `protobuf:"bytes,9,rep,name=modifiers,proto3" json:"modifiers,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CartItem) Reset() {
+	*x = CartItem{}
+	mi := &file_order_v1_cart_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CartItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CartItem) ProtoMessage() {}
+
+func (x *CartItem) ProtoReflect() protoreflect.Message {
+	mi := &file_order_v1_cart_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CartItem.ProtoReflect.Descriptor instead.
+func (*CartItem) Descriptor() ([]byte, []int) {
+	return file_order_v1_cart_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CartItem) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *CartItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *CartItem) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CartItem) GetVolume() int32 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+func (x *CartItem) GetUnitPrice() *Money {
+	if x != nil {
+		return x.UnitPrice
+	}
+	return nil
+}
+
+func (x *CartItem) GetLineTotal() *Money {
+	if x != nil {
+		return x.LineTotal
+	}
+	return nil
+}
+
+func (x *CartItem) GetAvailableQuantity() int64 {
+	if x != nil {
+		return x.AvailableQuantity
+	}
+	return 0
+}
+
+func (x *CartItem) GetAvailability() CartItemAvailability {
+	if x != nil {
+		return x.Availability
+	}
+	return CartItemAvailability_CART_ITEM_AVAILABILITY_UNSPECIFIED
+}
+
+func (x *CartItem) GetModifiers() []*CartItemModifier {
+	if x != nil {
+		return x.Modifiers
+	}
+	return nil
+}
+
+type CartItemModifier struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ModifierId    string                 `protobuf:"bytes,1,opt,name=modifier_id,json=modifierId,proto3" json:"modifier_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	PriceDelta    *Money                 `protobuf:"bytes,3,opt,name=price_delta,json=priceDelta,proto3" json:"price_delta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CartItemModifier) Reset() {
+	*x = CartItemModifier{}
+	mi := &file_order_v1_cart_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CartItemModifier) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CartItemModifier) ProtoMessage() {}
+
+func (x *CartItemModifier) ProtoReflect() protoreflect.Message {
+	mi := &file_order_v1_cart_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CartItemModifier.ProtoReflect.Descriptor instead.
+func (*CartItemModifier) Descriptor() ([]byte, []int) {
+	return file_order_v1_cart_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CartItemModifier) GetModifierId() string {
+	if x != nil {
+		return x.ModifierId
+	}
+	return ""
+}
+
+func (x *CartItemModifier) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CartItemModifier) GetPriceDelta() *Money {
+	if x != nil {
+		return x.PriceDelta
+	}
+	return nil
+}
+
+type Cart struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// id is empty for a cart nothing was added to yet.
+	Id     string      `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId string      `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Items  []*CartItem `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	// subtotal sums the lines that can be ordered.
+	Subtotal *Money `protobuf:"bytes,4,opt,name=subtotal,proto3" json:"subtotal,omitempty"`
+	// checkout_ready is set when the cart has items and all are in stock.
+	CheckoutReady bool                   `protobuf:"varint,5,opt,name=checkout_ready,json=checkoutReady,proto3" json:"checkout_ready,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Cart) Reset() {
+	*x = Cart{}
+	mi := &file_order_v1_cart_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Cart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cart) ProtoMessage() {}
+
+func (x *Cart) ProtoReflect() protoreflect.Message {
+	mi := &file_order_v1_cart_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cart.ProtoReflect.Descriptor instead.
+func (*Cart) Descriptor() ([]byte, []int) {
+	return file_order_v1_cart_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Cart) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Cart) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Cart) GetItems() []*CartItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Cart) GetSubtotal() *Money {
+	if x != nil {
+		return x.Subtotal
+	}
+	return nil
+}
+
+func (x *Cart) GetCheckoutReady() bool {
+	if x != nil {
+		return x.CheckoutReady
+	}
+	return false
+}
+
+func (x *Cart) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Cart) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type GetCartRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Owner         *CartOwner             `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCartRequest) Reset() {
+	*x = GetCartRequest{}
+	mi := &file_order_v1_cart_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCartRequest) ProtoMessage() {}
+
+func (x *GetCartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_v1_cart_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCartRequest.ProtoReflect.Descriptor instead.
+func (*GetCartRequest) Descriptor() ([]byte, []int) {
+	return file_order_v1_cart_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetCartRequest) GetOwner() *CartOwner {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
+type AddCartItemRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// owner without user_id and cart_id starts a new anonymous cart.
+	Owner     *CartOwner `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	ProductId string     `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity  int32      `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// modifier_ids must be a valid choice from the product's modifier groups.
+	ModifierIds   []string `protobuf:"bytes,4,rep,name=modifier_ids,json=modifierIds,proto3" json:"modifier_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddCartItemRequest) Reset() {
+	*x = AddCartItemRequest{}
+	mi := &file_order_v1_cart_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddCartItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddCartItemRequest) ProtoMessage() {}
+
+func (x *AddCartItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_v1_cart_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddCartItemRequest.ProtoReflect.Descriptor instead.
+func (*AddCartItemRequest) Descriptor() ([]byte, []int) {
+	return file_order_v1_cart_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AddCartItemRequest) GetOwner() *CartOwner {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
+func (x *AddCartItemRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *AddCartItemRequest) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *AddCartItemRequest) GetModifierIds() []string {
+	if x != nil {
+		return x.ModifierIds
+	}
+	return nil
+}
+
+// UpdateCartItemRequest and RemoveCartItemRequest pick the line by product_id
+// and modifier_ids, in any order.
+type UpdateCartItemRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Owner     *CartOwner             `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	ProductId string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	// quantity 0 removes the line.
+	Quantity      int32    `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	ModifierIds   []string `protobuf:"bytes,4,rep,name=modifier_ids,json=modifierIds,proto3" json:"modifier_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCartItemRequest) Reset() {
+	*x = UpdateCartItemRequest{}
+	mi := &file_order_v1_cart_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCartItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCartItemRequest) ProtoMessage() {}
+
+func (x *UpdateCartItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_v1_cart_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCartItemRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCartItemRequest) Descriptor() ([]byte, []int) {
+	return file_order_v1_cart_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateCartItemRequest) GetOwner() *CartOwner {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
+func (x *UpdateCartItemRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *UpdateCartItemRequest) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *UpdateCartItemRequest) GetModifierIds() []string {
+	if x != nil {
+		return x.ModifierIds
+	}
+	return nil
+}
+
+type RemoveCartItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Owner         *CartOwner             `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	ModifierIds   []string               `protobuf:"bytes,3,rep,name=modifier_ids,json=modifierIds,proto3" json:"modifier_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveCartItemRequest) Reset() {
+	*x = RemoveCartItemRequest{}
+	mi := &file_order_v1_cart_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveCartItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveCartItemRequest) ProtoMessage() {}
+
+func (x *RemoveCartItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_v1_cart_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveCartItemRequest.ProtoReflect.Descriptor instead.
+func (*RemoveCartItemRequest) Descriptor() ([]byte, []int) {
+	return file_order_v1_cart_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RemoveCartItemRequest) GetOwner() *CartOwner {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
+func (x *RemoveCartItemRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *RemoveCartItemRequest) GetModifierIds() []string {
+	if x != nil {
+		return x.ModifierIds
+	}
+	return nil
+}
+
+type MergeCartsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// cart_id is the anonymous cart moved into the cart of user_id.
+	CartId        string `protobuf:"bytes,1,opt,name=cart_id,json=cartId,proto3" json:"cart_id,omitempty"`
+	UserId        string `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MergeCartsRequest) Reset() {
+	*x = MergeCartsRequest{}
+	mi := &file_order_v1_cart_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeCartsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeCartsRequest) ProtoMessage() {}
+
+func (x *MergeCartsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_v1_cart_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeCartsRequest.ProtoReflect.Descriptor instead.
+func (*MergeCartsRequest) Descriptor() ([]byte, []int) {
+	return file_order_v1_cart_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *MergeCartsRequest) GetCartId() string {
+	if x != nil {
+		return x.CartId
+	}
+	return ""
+}
+
+func (x *MergeCartsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type CheckoutCartRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserId         string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	IdempotencyKey string                 `protobuf:"bytes,2,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CheckoutCartRequest) Reset() {
+	*x = CheckoutCartRequest{}
+	mi := &file_order_v1_cart_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckoutCartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckoutCartRequest) ProtoMessage() {}
+
+func (x *CheckoutCartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_v1_cart_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckoutCartRequest.ProtoReflect.Descriptor instead.
+func (*CheckoutCartRequest) Descriptor() ([]byte, []int) {
+	return file_order_v1_cart_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CheckoutCartRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CheckoutCartRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type CheckoutCartResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Status        OrderStatus            `protobuf:"varint,2,opt,name=status,proto3,enum=order.v1.OrderStatus" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckoutCartResponse) Reset() {
+	*x = CheckoutCartResponse{}
+	mi := &file_order_v1_cart_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckoutCartResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckoutCartResponse) ProtoMessage() {}
+
+func (x *CheckoutCartResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_v1_cart_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckoutCartResponse.ProtoReflect.Descriptor instead.
+func (*CheckoutCartResponse) Descriptor() ([]byte, []int) {
+	return file_order_v1_cart_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CheckoutCartResponse) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *CheckoutCartResponse) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+var File_order_v1_cart_proto protoreflect.FileDescriptor
+
+const file_order_v1_cart_proto_rawDesc = "" +
+	"\n" +
+	"\x13order/v1/cart.proto\x12\border.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x14order/v1/money.proto\x1a\x1border/v1/order_status.proto\"=\n" +
+	"\tCartOwner\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x17\n" +
+	"\acart_id\x18\x02 \x01(\tR\x06cartId\"\xfe\x02\n" +
+	"\bCartItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x16\n" +
+	"\x06volume\x18\x04 \x01(\x05R\x06volume\x12.\n" +
+	"\n" +
+	"unit_price\x18\x05 \x01(\v2\x0f.order.v1.MoneyR\tunitPrice\x12.\n" +
+	"\n" +
+	"line_total\x18\x06 \x01(\v2\x0f.order.v1.MoneyR\tlineTotal\x12-\n" +
+	"\x12available_quantity\x18\a \x01(\x03R\x11availableQuantity\x12B\n" +
+	"\favailability\x18\b \x01(\x0e2\x1e.order.v1.CartItemAvailabilityR\favailability\x128\n" +
+	"\tmodifiers\x18\t \x03(\v2\x1a.order.v1.CartItemModifierR\tmodifiers\"y\n" +
+	"\x10CartItemModifier\x12\x1f\n" +
+	"\vmodifier_id\x18\x01 \x01(\tR\n" +
+	"modifierId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x120\n" +
+	"\vprice_delta\x18\x03 \x01(\v2\x0f.order.v1.MoneyR\n" +
+	"priceDelta\"\xa3\x02\n" +
+	"\x04Cart\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12(\n" +
+	"\x05items\x18\x03 \x03(\v2\x12.order.v1.CartItemR\x05items\x12+\n" +
+	"\bsubtotal\x18\x04 \x01(\v2\x0f.order.v1.MoneyR\bsubtotal\x12%\n" +
+	"\x0echeckout_ready\x18\x05 \x01(\bR\rcheckoutReady\x129\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\";\n" +
+	"\x0eGetCartRequest\x12)\n" +
+	"\x05owner\x18\x01 \x01(\v2\x13.order.v1.CartOwnerR\x05owner\"\x9d\x01\n" +
+	"\x12AddCartItemRequest\x12)\n" +
+	"\x05owner\x18\x01 \x01(\v2\x13.order.v1.CartOwnerR\x05owner\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12!\n" +
+	"\fmodifier_ids\x18\x04 \x03(\tR\vmodifierIds\"\xa0\x01\n" +
+	"\x15UpdateCartItemRequest\x12)\n" +
+	"\x05owner\x18\x01 \x01(\v2\x13.order.v1.CartOwnerR\x05owner\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12!\n" +
+	"\fmodifier_ids\x18\x04 \x03(\tR\vmodifierIds\"\x84\x01\n" +
+	"\x15RemoveCartItemRequest\x12)\n" +
+	"\x05owner\x18\x01 \x01(\v2\x13.order.v1.CartOwnerR\x05owner\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12!\n" +
+	"\fmodifier_ids\x18\x03 \x03(\tR\vmodifierIds\"E\n" +
+	"\x11MergeCartsRequest\x12\x17\n" +
+	"\acart_id\x18\x01 \x01(\tR\x06cartId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"W\n" +
+	"\x13CheckoutCartRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12'\n" +
+	"\x0fidempotency_key\x18\x02 \x01(\tR\x0eidempotencyKey\"`\n" +
+	"\x14CheckoutCartResponse\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12-\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x15.order.v1.OrderStatusR\x06status*\xba\x01\n" +
+	"\x14CartItemAvailability\x12&\n" +
+	"\"CART_ITEM_AVAILABILITY_UNSPECIFIED\x10\x00\x12#\n" +
+	"\x1fCART_ITEM_AVAILABILITY_IN_STOCK\x10\x01\x12-\n" +
+	")CART_ITEM_AVAILABILITY_INSUFFICIENT_STOCK\x10\x02\x12&\n" +
+	"\"CART_ITEM_AVAILABILITY_UNAVAILABLE\x10\x032\x8f\x03\n" +
+	"\vCartService\x123\n" +
+	"\aGetCart\x12\x18.order.v1.GetCartRequest\x1a\x0e.order.v1.Cart\x12;\n" +
+	"\vAddCartItem\x12\x1c.order.v1.AddCartItemRequest\x1a\x0e.order.v1.Cart\x12A\n" +
+	"\x0eUpdateCartItem\x12\x1f.order.v1.UpdateCartItemRequest\x1a\x0e.order.v1.Cart\x12A\n" +
+	"\x0eRemoveCartItem\x12\x1f.order.v1.RemoveCartItemRequest\x1a\x0e.order.v1.Cart\x129\n" +
+	"\n" +
+	"MergeCarts\x12\x1b.order.v1.MergeCartsRequest\x1a\x0e.order.v1.Cart\x12M\n" +
+	"\fCheckoutCart\x12\x1d.order.v1.CheckoutCartRequest\x1a\x1e.order.v1.CheckoutCartResponseB@Z>immxrtalbeast/order_microservices/internal/pkg/orderpb;orderpbb\x06proto3"
+
+var (
+	file_order_v1_cart_proto_rawDescOnce sync.Once
+	file_order_v1_cart_proto_rawDescData []byte
+)
+
+func file_order_v1_cart_proto_rawDescGZIP() []byte {
+	file_order_v1_cart_proto_rawDescOnce.Do(func() {
+		file_order_v1_cart_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_order_v1_cart_proto_rawDesc), len(file_order_v1_cart_proto_rawDesc)))
+	})
+	return file_order_v1_cart_proto_rawDescData
+}
+
+var file_order_v1_cart_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_order_v1_cart_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_order_v1_cart_proto_goTypes = []any{
+	(CartItemAvailability)(0),     // 0: order.v1.CartItemAvailability
+	(*CartOwner)(nil),             // 1: order.v1.CartOwner
+	(*CartItem)(nil),              // 2: order.v1.CartItem
+	(*CartItemModifier)(nil),      // 3: order.v1.CartItemModifier
+	(*Cart)(nil),                  // 4: order.v1.Cart
+	(*GetCartRequest)(nil),        // 5: order.v1.GetCartRequest
+	(*AddCartItemRequest)(nil),    // 6: order.v1.AddCartItemRequest
+	(*UpdateCartItemRequest)(nil), // 7: order.v1.UpdateCartItemRequest
+	(*RemoveCartItemRequest)(nil), // 8: order.v1.RemoveCartItemRequest
+	(*MergeCartsRequest)(nil),     // 9: order.v1.MergeCartsRequest
+	(*CheckoutCartRequest)(nil),   // 10: order.v1.CheckoutCartRequest
+	(*CheckoutCartResponse)(nil),  // 11: order.v1.CheckoutCartResponse
+	(*Money)(nil),                 // 12: order.v1.Money
+	(*timestamppb.Timestamp)(nil), // 13: google.protobuf.Timestamp
+	(OrderStatus)(0),              // 14: order.v1.OrderStatus
+}
+var file_order_v1_cart_proto_depIdxs = []int32{
+	12, // 0: order.v1.CartItem.unit_price:type_name -> order.v1.Money
+	12, // 1: order.v1.CartItem.line_total:type_name -> order.v1.Money
+	0,  // 2: order.v1.CartItem.availability:type_name -> order.v1.CartItemAvailability
+	3,  // 3: order.v1.CartItem.modifiers:type_name -> order.v1.CartItemModifier
+	12, // 4: order.v1.CartItemModifier.price_delta:type_name -> order.v1.Money
+	2,  // 5: order.v1.Cart.items:type_name -> order.v1.CartItem
+	12, // 6: order.v1.Cart.subtotal:type_name -> order.v1.Money
+	13, // 7: order.v1.Cart.updated_at:type_name -> google.protobuf.Timestamp
+	13, // 8: order.v1.Cart.expires_at:type_name -> google.protobuf.Timestamp
+	1,  // 9: order.v1.GetCartRequest.owner:type_name -> order.v1.CartOwner
+	1,  // 10: order.v1.AddCartItemRequest.owner:type_name -> order.v1.CartOwner
+	1,  // 11: order.v1.UpdateCartItemRequest.owner:type_name -> order.v1.CartOwner
+	1,  // 12: order.v1.RemoveCartItemRequest.owner:type_name -> order.v1.CartOwner
+	14, // 13: order.v1.CheckoutCartResponse.status:type_name -> order.v1.OrderStatus
+	5,  // 14: order.v1.CartService.GetCart:input_type -> order.v1.GetCartRequest
+	6,  // 15: order.v1.CartService.AddCartItem:input_type -> order.v1.AddCartItemRequest
+	7,  // 16: order.v1.CartService.UpdateCartItem:input_type -> order.v1.UpdateCartItemRequest
+	8,  // 17: order.v1.CartService.RemoveCartItem:input_type -> order.v1.RemoveCartItemRequest
+	9,  // 18: order.v1.CartService.MergeCarts:input_type -> order.v1.MergeCartsRequest
+	10, // 19: order.v1.CartService.CheckoutCart:input_type -> order.v1.CheckoutCartRequest
+	4,  // 20: order.v1.CartService.GetCart:output_type -> order.v1.Cart
+	4,  // 21: order.v1.CartService.AddCartItem:output_type -> order.v1.Cart
+	4,  // 22: order.v1.CartService.UpdateCartItem:output_type -> order.v1.Cart
+	4,  // 23: order.v1.CartService.RemoveCartItem:output_type -> order.v1.Cart
+	4,  // 24: order.v1.CartService.MergeCarts:output_type -> order.v1.Cart
+	11, // 25: order.v1.CartService.CheckoutCart:output_type -> order.v1.CheckoutCartResponse
+	20, // [20:26] is the sub-list for method output_type
+	14, // [14:20] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_order_v1_cart_proto_init() }
+func file_order_v1_cart_proto_init() {
+	if File_order_v1_cart_proto != nil {
+		return
+	}
+	file_order_v1_money_proto_init()
+	file_order_v1_order_status_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_order_v1_cart_proto_rawDesc), len(file_order_v1_cart_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_order_v1_cart_proto_goTypes,
+		DependencyIndexes: file_order_v1_cart_proto_depIdxs,
+		EnumInfos:         file_order_v1_cart_proto_enumTypes,
+		MessageInfos:      file_order_v1_cart_proto_msgTypes,
+	}.Build()
+	File_order_v1_cart_proto = out.File
+	file_order_v1_cart_proto_goTypes = nil
+	file_order_v1_cart_proto_depIdxs = nil
+}
@@ -0,0 +1,586 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: order/v1/order_status.proto
+
+package orderpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// OrderStatus is the lifecycle of an order. Orders move forward through
+// CREATED, RESERVED, PREPARING, READY and COMPLETED, and may be FAILED at any
+// point before completion. Until preparing starts an order may be cancelled:
+// it is CANCELLING while its stock is released and CANCELLED after.
+type OrderStatus int32
+
+const (
+	OrderStatus_ORDER_STATUS_UNSPECIFIED OrderStatus = 0
+	OrderStatus_ORDER_STATUS_CREATED     OrderStatus = 1
+	OrderStatus_ORDER_STATUS_RESERVED    OrderStatus = 2
+	OrderStatus_ORDER_STATUS_PREPARING   OrderStatus = 3
+	OrderStatus_ORDER_STATUS_READY       OrderStatus = 4
+	OrderStatus_ORDER_STATUS_COMPLETED   OrderStatus = 5
+	OrderStatus_ORDER_STATUS_CANCELLED   OrderStatus = 6
+	OrderStatus_ORDER_STATUS_FAILED      OrderStatus = 7
+	OrderStatus_ORDER_STATUS_CANCELLING  OrderStatus = 8
+)
+
+// Enum value maps for OrderStatus.
+var (
+	OrderStatus_name = map[int32]string{
+		0: "ORDER_STATUS_UNSPECIFIED",
+		1: "ORDER_STATUS_CREATED",
+		2: "ORDER_STATUS_RESERVED",
+		3: "ORDER_STATUS_PREPARING",
+		4: "ORDER_STATUS_READY",
+		5: "ORDER_STATUS_COMPLETED",
+		6: "ORDER_STATUS_CANCELLED",
+		7: "ORDER_STATUS_FAILED",
+		8: "ORDER_STATUS_CANCELLING",
+	}
+	OrderStatus_value = map[string]int32{
+		"ORDER_STATUS_UNSPECIFIED": 0,
+		"ORDER_STATUS_CREATED":     1,
+		"ORDER_STATUS_RESERVED":    2,
+		"ORDER_STATUS_PREPARING":   3,
+		"ORDER_STATUS_READY":       4,
+		"ORDER_STATUS_COMPLETED":   5,
+		"ORDER_STATUS_CANCELLED":   6,
+		"ORDER_STATUS_FAILED":      7,
+		"ORDER_STATUS_CANCELLING":  8,
+	}
+)
+
+func (x OrderStatus) Enum() *OrderStatus {
+	p := new(OrderStatus)
+	*p = x
+	return p
+}
+
+func (x OrderStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OrderStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_order_v1_order_status_proto_enumTypes[0].Descriptor()
+}
+
+func (OrderStatus) Type() protoreflect.EnumType {
+	return &file_order_v1_order_status_proto_enumTypes[0]
+}
+
+func (x OrderStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OrderStatus.Descriptor instead.
+func (OrderStatus) EnumDescriptor() ([]byte, []int) {
+	return file_order_v1_order_status_proto_rawDescGZIP(), []int{0}
+}
+
+// StatusActor is who changed an order status.
+type StatusActor int32
+
+const (
+	StatusActor_STATUS_ACTOR_UNSPECIFIED StatusActor = 0
+	StatusActor_STATUS_ACTOR_USER        StatusActor = 1
+	StatusActor_STATUS_ACTOR_ADMIN       StatusActor = 2
+	StatusActor_STATUS_ACTOR_SAGA        StatusActor = 3
+)
+
+// Enum value maps for StatusActor.
+var (
+	StatusActor_name = map[int32]string{
+		0: "STATUS_ACTOR_UNSPECIFIED",
+		1: "STATUS_ACTOR_USER",
+		2: "STATUS_ACTOR_ADMIN",
+		3: "STATUS_ACTOR_SAGA",
+	}
+	StatusActor_value = map[string]int32{
+		"STATUS_ACTOR_UNSPECIFIED": 0,
+		"STATUS_ACTOR_USER":        1,
+		"STATUS_ACTOR_ADMIN":       2,
+		"STATUS_ACTOR_SAGA":        3,
+	}
+)
+
+func (x StatusActor) Enum() *StatusActor {
+	p := new(StatusActor)
+	*p = x
+	return p
+}
+
+func (x StatusActor) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StatusActor) Descriptor() protoreflect.EnumDescriptor {
+	return file_order_v1_order_status_proto_enumTypes[1].Descriptor()
+}
+
+func (StatusActor) Type() protoreflect.EnumType {
+	return &file_order_v1_order_status_proto_enumTypes[1]
+}
+
+func (x StatusActor) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StatusActor.Descriptor instead.
+func (StatusActor) EnumDescriptor() ([]byte, []int) {
+	return file_order_v1_order_status_proto_rawDescGZIP(), []int{1}
+}
+
+type UpdateOrderStatusRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	OrderId string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Status  OrderStatus            `protobuf:"varint,2,opt,name=status,proto3,enum=order.v1.OrderStatus" json:"status,omitempty"`
+	Actor   StatusActor            `protobuf:"varint,3,opt,name=actor,proto3,enum=order.v1.StatusActor" json:"actor,omitempty"`
+	// actor_id is the ID of the user or admin making the change.
+	ActorId       string `protobuf:"bytes,4,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	Reason        string `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateOrderStatusRequest) Reset() {
+	*x = UpdateOrderStatusRequest{}
+	mi := &file_order_v1_order_status_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateOrderStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOrderStatusRequest) ProtoMessage() {}
+
+func (x *UpdateOrderStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_v1_order_status_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOrderStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateOrderStatusRequest) Descriptor() ([]byte, []int) {
+	return file_order_v1_order_status_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *UpdateOrderStatusRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *UpdateOrderStatusRequest) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+func (x *UpdateOrderStatusRequest) GetActor() StatusActor {
+	if x != nil {
+		return x.Actor
+	}
+	return StatusActor_STATUS_ACTOR_UNSPECIFIED
+}
+
+func (x *UpdateOrderStatusRequest) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *UpdateOrderStatusRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type UpdateOrderStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Status        OrderStatus            `protobuf:"varint,2,opt,name=status,proto3,enum=order.v1.OrderStatus" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateOrderStatusResponse) Reset() {
+	*x = UpdateOrderStatusResponse{}
+	mi := &file_order_v1_order_status_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateOrderStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOrderStatusResponse) ProtoMessage() {}
+
+func (x *UpdateOrderStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_v1_order_status_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOrderStatusResponse.ProtoReflect.Descriptor instead.
+func (*UpdateOrderStatusResponse) Descriptor() ([]byte, []int) {
+	return file_order_v1_order_status_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UpdateOrderStatusResponse) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *UpdateOrderStatusResponse) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+type OrderStatusHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderStatusHistoryRequest) Reset() {
+	*x = OrderStatusHistoryRequest{}
+	mi := &file_order_v1_order_status_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderStatusHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderStatusHistoryRequest) ProtoMessage() {}
+
+func (x *OrderStatusHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_v1_order_status_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderStatusHistoryRequest.ProtoReflect.Descriptor instead.
+func (*OrderStatusHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_order_v1_order_status_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *OrderStatusHistoryRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type OrderStatusHistoryEntry struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// from is ORDER_STATUS_UNSPECIFIED for the entry of the order's creation.
+	From      OrderStatus            `protobuf:"varint,1,opt,name=from,proto3,enum=order.v1.OrderStatus" json:"from,omitempty"`
+	To        OrderStatus            `protobuf:"varint,2,opt,name=to,proto3,enum=order.v1.OrderStatus" json:"to,omitempty"`
+	Actor     StatusActor            `protobuf:"varint,3,opt,name=actor,proto3,enum=order.v1.StatusActor" json:"actor,omitempty"`
+	ActorId   string                 `protobuf:"bytes,4,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	Reason    string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	ChangedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	// refund_id and refund_amount are set on the lines of completed refunds,
+	// which leave the status as it is.
+	RefundId      string `protobuf:"bytes,7,opt,name=refund_id,json=refundId,proto3" json:"refund_id,omitempty"`
+	RefundAmount  *Money `protobuf:"bytes,8,opt,name=refund_amount,json=refundAmount,proto3" json:"refund_amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderStatusHistoryEntry) Reset() {
+	*x = OrderStatusHistoryEntry{}
+	mi := &file_order_v1_order_status_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderStatusHistoryEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderStatusHistoryEntry) ProtoMessage() {}
+
+func (x *OrderStatusHistoryEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_order_v1_order_status_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderStatusHistoryEntry.ProtoReflect.Descriptor instead.
+func (*OrderStatusHistoryEntry) Descriptor() ([]byte, []int) {
+	return file_order_v1_order_status_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *OrderStatusHistoryEntry) GetFrom() OrderStatus {
+	if x != nil {
+		return x.From
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+func (x *OrderStatusHistoryEntry) GetTo() OrderStatus {
+	if x != nil {
+		return x.To
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+func (x *OrderStatusHistoryEntry) GetActor() StatusActor {
+	if x != nil {
+		return x.Actor
+	}
+	return StatusActor_STATUS_ACTOR_UNSPECIFIED
+}
+
+func (x *OrderStatusHistoryEntry) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *OrderStatusHistoryEntry) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *OrderStatusHistoryEntry) GetChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return nil
+}
+
+func (x *OrderStatusHistoryEntry) GetRefundId() string {
+	if x != nil {
+		return x.RefundId
+	}
+	return ""
+}
+
+func (x *OrderStatusHistoryEntry) GetRefundAmount() *Money {
+	if x != nil {
+		return x.RefundAmount
+	}
+	return nil
+}
+
+type OrderStatusHistoryResponse struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Entries       []*OrderStatusHistoryEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderStatusHistoryResponse) Reset() {
+	*x = OrderStatusHistoryResponse{}
+	mi := &file_order_v1_order_status_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderStatusHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderStatusHistoryResponse) ProtoMessage() {}
+
+func (x *OrderStatusHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_v1_order_status_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderStatusHistoryResponse.ProtoReflect.Descriptor instead.
+func (*OrderStatusHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_order_v1_order_status_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *OrderStatusHistoryResponse) GetEntries() []*OrderStatusHistoryEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+var File_order_v1_order_status_proto protoreflect.FileDescriptor
+
+const file_order_v1_order_status_proto_rawDesc = "" +
+	"\n" +
+	"\x1border/v1/order_status.proto\x12\border.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x14order/v1/money.proto\"\xc4\x01\n" +
+	"\x18UpdateOrderStatusRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12-\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x15.order.v1.OrderStatusR\x06status\x12+\n" +
+	"\x05actor\x18\x03 \x01(\x0e2\x15.order.v1.StatusActorR\x05actor\x12\x19\n" +
+	"\bactor_id\x18\x04 \x01(\tR\aactorId\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\"e\n" +
+	"\x19UpdateOrderStatusResponse\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12-\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x15.order.v1.OrderStatusR\x06status\"6\n" +
+	"\x19OrderStatusHistoryRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"\xd9\x02\n" +
+	"\x17OrderStatusHistoryEntry\x12)\n" +
+	"\x04from\x18\x01 \x01(\x0e2\x15.order.v1.OrderStatusR\x04from\x12%\n" +
+	"\x02to\x18\x02 \x01(\x0e2\x15.order.v1.OrderStatusR\x02to\x12+\n" +
+	"\x05actor\x18\x03 \x01(\x0e2\x15.order.v1.StatusActorR\x05actor\x12\x19\n" +
+	"\bactor_id\x18\x04 \x01(\tR\aactorId\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x129\n" +
+	"\n" +
+	"changed_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tchangedAt\x12\x1b\n" +
+	"\trefund_id\x18\a \x01(\tR\brefundId\x124\n" +
+	"\rrefund_amount\x18\b \x01(\v2\x0f.order.v1.MoneyR\frefundAmount\"Y\n" +
+	"\x1aOrderStatusHistoryResponse\x12;\n" +
+	"\aentries\x18\x01 \x03(\v2!.order.v1.OrderStatusHistoryEntryR\aentries*\x82\x02\n" +
+	"\vOrderStatus\x12\x1c\n" +
+	"\x18ORDER_STATUS_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14ORDER_STATUS_CREATED\x10\x01\x12\x19\n" +
+	"\x15ORDER_STATUS_RESERVED\x10\x02\x12\x1a\n" +
+	"\x16ORDER_STATUS_PREPARING\x10\x03\x12\x16\n" +
+	"\x12ORDER_STATUS_READY\x10\x04\x12\x1a\n" +
+	"\x16ORDER_STATUS_COMPLETED\x10\x05\x12\x1a\n" +
+	"\x16ORDER_STATUS_CANCELLED\x10\x06\x12\x17\n" +
+	"\x13ORDER_STATUS_FAILED\x10\a\x12\x1b\n" +
+	"\x17ORDER_STATUS_CANCELLING\x10\b*q\n" +
+	"\vStatusActor\x12\x1c\n" +
+	"\x18STATUS_ACTOR_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11STATUS_ACTOR_USER\x10\x01\x12\x16\n" +
+	"\x12STATUS_ACTOR_ADMIN\x10\x02\x12\x15\n" +
+	"\x11STATUS_ACTOR_SAGA\x10\x032\xd3\x01\n" +
+	"\x12OrderStatusService\x12\\\n" +
+	"\x11UpdateOrderStatus\x12\".order.v1.UpdateOrderStatusRequest\x1a#.order.v1.UpdateOrderStatusResponse\x12_\n" +
+	"\x12OrderStatusHistory\x12#.order.v1.OrderStatusHistoryRequest\x1a$.order.v1.OrderStatusHistoryResponseB@Z>immxrtalbeast/order_microservices/internal/pkg/orderpb;orderpbb\x06proto3"
+
+var (
+	file_order_v1_order_status_proto_rawDescOnce sync.Once
+	file_order_v1_order_status_proto_rawDescData []byte
+)
+
+func file_order_v1_order_status_proto_rawDescGZIP() []byte {
+	file_order_v1_order_status_proto_rawDescOnce.Do(func() {
+		file_order_v1_order_status_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_order_v1_order_status_proto_rawDesc), len(file_order_v1_order_status_proto_rawDesc)))
+	})
+	return file_order_v1_order_status_proto_rawDescData
+}
+
+var file_order_v1_order_status_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_order_v1_order_status_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_order_v1_order_status_proto_goTypes = []any{
+	(OrderStatus)(0),                   // 0: order.v1.OrderStatus
+	(StatusActor)(0),                   // 1: order.v1.StatusActor
+	(*UpdateOrderStatusRequest)(nil),   // 2: order.v1.UpdateOrderStatusRequest
+	(*UpdateOrderStatusResponse)(nil),  // 3: order.v1.UpdateOrderStatusResponse
+	(*OrderStatusHistoryRequest)(nil),  // 4: order.v1.OrderStatusHistoryRequest
+	(*OrderStatusHistoryEntry)(nil),    // 5: order.v1.OrderStatusHistoryEntry
+	(*OrderStatusHistoryResponse)(nil), // 6: order.v1.OrderStatusHistoryResponse
+	(*timestamppb.Timestamp)(nil),      // 7: google.protobuf.Timestamp
+	(*Money)(nil),                      // 8: order.v1.Money
+}
+var file_order_v1_order_status_proto_depIdxs = []int32{
+	0,  // 0: order.v1.UpdateOrderStatusRequest.status:type_name -> order.v1.OrderStatus
+	1,  // 1: order.v1.UpdateOrderStatusRequest.actor:type_name -> order.v1.StatusActor
+	0,  // 2: order.v1.UpdateOrderStatusResponse.status:type_name -> order.v1.OrderStatus
+	0,  // 3: order.v1.OrderStatusHistoryEntry.from:type_name -> order.v1.OrderStatus
+	0,  // 4: order.v1.OrderStatusHistoryEntry.to:type_name -> order.v1.OrderStatus
+	1,  // 5: order.v1.OrderStatusHistoryEntry.actor:type_name -> order.v1.StatusActor
+	7,  // 6: order.v1.OrderStatusHistoryEntry.changed_at:type_name -> google.protobuf.Timestamp
+	8,  // 7: order.v1.OrderStatusHistoryEntry.refund_amount:type_name -> order.v1.Money
+	5,  // 8: order.v1.OrderStatusHistoryResponse.entries:type_name -> order.v1.OrderStatusHistoryEntry
+	2,  // 9: order.v1.OrderStatusService.UpdateOrderStatus:input_type -> order.v1.UpdateOrderStatusRequest
+	4,  // 10: order.v1.OrderStatusService.OrderStatusHistory:input_type -> order.v1.OrderStatusHistoryRequest
+	3,  // 11: order.v1.OrderStatusService.UpdateOrderStatus:output_type -> order.v1.UpdateOrderStatusResponse
+	6,  // 12: order.v1.OrderStatusService.OrderStatusHistory:output_type -> order.v1.OrderStatusHistoryResponse
+	11, // [11:13] is the sub-list for method output_type
+	9,  // [9:11] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_order_v1_order_status_proto_init() }
+func file_order_v1_order_status_proto_init() {
+	if File_order_v1_order_status_proto != nil {
+		return
+	}
+	file_order_v1_money_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_order_v1_order_status_proto_rawDesc), len(file_order_v1_order_status_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_order_v1_order_status_proto_goTypes,
+		DependencyIndexes: file_order_v1_order_status_proto_depIdxs,
+		EnumInfos:         file_order_v1_order_status_proto_enumTypes,
+		MessageInfos:      file_order_v1_order_status_proto_msgTypes,
+	}.Build()
+	File_order_v1_order_status_proto = out.File
+	file_order_v1_order_status_proto_goTypes = nil
+	file_order_v1_order_status_proto_depIdxs = nil
+}
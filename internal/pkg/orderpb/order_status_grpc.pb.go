@@ -0,0 +1,171 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: order/v1/order_status.proto
+
+package orderpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	OrderStatusService_UpdateOrderStatus_FullMethodName  = "/order.v1.OrderStatusService/UpdateOrderStatus"
+	OrderStatusService_OrderStatusHistory_FullMethodName = "/order.v1.OrderStatusService/OrderStatusHistory"
+)
+
+// OrderStatusServiceClient is the client API for OrderStatusService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// OrderStatusService manages order status transitions.
+type OrderStatusServiceClient interface {
+	// UpdateOrderStatus moves an order to status. Transitions the order status
+	// machine does not allow fail with FAILED_PRECONDITION. CANCELLED asked by a
+	// user or admin starts the cancellation and answers CANCELLING.
+	UpdateOrderStatus(ctx context.Context, in *UpdateOrderStatusRequest, opts ...grpc.CallOption) (*UpdateOrderStatusResponse, error)
+	// OrderStatusHistory lists the status changes of an order, oldest first.
+	OrderStatusHistory(ctx context.Context, in *OrderStatusHistoryRequest, opts ...grpc.CallOption) (*OrderStatusHistoryResponse, error)
+}
+
+type orderStatusServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOrderStatusServiceClient(cc grpc.ClientConnInterface) OrderStatusServiceClient {
+	return &orderStatusServiceClient{cc}
+}
+
+func (c *orderStatusServiceClient) UpdateOrderStatus(ctx context.Context, in *UpdateOrderStatusRequest, opts ...grpc.CallOption) (*UpdateOrderStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateOrderStatusResponse)
+	err := c.cc.Invoke(ctx, OrderStatusService_UpdateOrderStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderStatusServiceClient) OrderStatusHistory(ctx context.Context, in *OrderStatusHistoryRequest, opts ...grpc.CallOption) (*OrderStatusHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderStatusHistoryResponse)
+	err := c.cc.Invoke(ctx, OrderStatusService_OrderStatusHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OrderStatusServiceServer is the server API for OrderStatusService service.
+// All implementations must embed UnimplementedOrderStatusServiceServer
+// for forward compatibility.
+//
+// OrderStatusService manages order status transitions.
+type OrderStatusServiceServer interface {
+	// UpdateOrderStatus moves an order to status. Transitions the order status
+	// machine does not allow fail with FAILED_PRECONDITION. CANCELLED asked by a
+	// user or admin starts the cancellation and answers CANCELLING.
+	UpdateOrderStatus(context.Context, *UpdateOrderStatusRequest) (*UpdateOrderStatusResponse, error)
+	// OrderStatusHistory lists the status changes of an order, oldest first.
+	OrderStatusHistory(context.Context, *OrderStatusHistoryRequest) (*OrderStatusHistoryResponse, error)
+	mustEmbedUnimplementedOrderStatusServiceServer()
+}
+
+// UnimplementedOrderStatusServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedOrderStatusServiceServer struct{}
+
+func (UnimplementedOrderStatusServiceServer) UpdateOrderStatus(context.Context, *UpdateOrderStatusRequest) (*UpdateOrderStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateOrderStatus not implemented")
+}
+func (UnimplementedOrderStatusServiceServer) OrderStatusHistory(context.Context, *OrderStatusHistoryRequest) (*OrderStatusHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OrderStatusHistory not implemented")
+}
+func (UnimplementedOrderStatusServiceServer) mustEmbedUnimplementedOrderStatusServiceServer() {}
+func (UnimplementedOrderStatusServiceServer) testEmbeddedByValue()                            {}
+
+// UnsafeOrderStatusServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OrderStatusServiceServer will
+// result in compilation errors.
+type UnsafeOrderStatusServiceServer interface {
+	mustEmbedUnimplementedOrderStatusServiceServer()
+}
+
+func RegisterOrderStatusServiceServer(s grpc.ServiceRegistrar, srv OrderStatusServiceServer) {
+	// If the following call pancis, it indicates UnimplementedOrderStatusServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&OrderStatusService_ServiceDesc, srv)
+}
+
+func _OrderStatusService_UpdateOrderStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateOrderStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderStatusServiceServer).UpdateOrderStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderStatusService_UpdateOrderStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderStatusServiceServer).UpdateOrderStatus(ctx, req.(*UpdateOrderStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderStatusService_OrderStatusHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderStatusHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderStatusServiceServer).OrderStatusHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderStatusService_OrderStatusHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderStatusServiceServer).OrderStatusHistory(ctx, req.(*OrderStatusHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// OrderStatusService_ServiceDesc is the grpc.ServiceDesc for OrderStatusService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OrderStatusService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "order.v1.OrderStatusService",
+	HandlerType: (*OrderStatusServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "UpdateOrderStatus",
+			Handler:    _OrderStatusService_UpdateOrderStatus_Handler,
+		},
+		{
+			MethodName: "OrderStatusHistory",
+			Handler:    _OrderStatusService_OrderStatusHistory_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "order/v1/order_status.proto",
+}
@@ -0,0 +1,36 @@
+module immxrtalbeast/order_microservices/internal/pkg/outbox
+
+go 1.24.5
+
+require (
+	github.com/google/uuid v1.6.0
+	go.opentelemetry.io/otel v1.38.0
+	gorm.io/gorm v1.31.0
+	immxrtalbeast/order_microservices/internal/pkg/events v0.0.0-00010101000000-000000000000
+	immxrtalbeast/order_microservices/internal/pkg/kafka v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
+	github.com/go-logr/logr v1.4.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+	github.com/klauspost/compress v1.15.9 // indirect
+	github.com/pierrec/lz4/v4 v4.1.15 // indirect
+	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
+	github.com/segmentio/kafka-go v0.4.51 // indirect
+	go.opentelemetry.io/auto/sdk v1.1.0 // indirect
+	go.opentelemetry.io/otel/metric v1.38.0 // indirect
+	go.opentelemetry.io/otel/trace v1.38.0 // indirect
+	golang.org/x/net v0.44.0 // indirect
+	golang.org/x/text v0.29.0 // indirect
+	google.golang.org/protobuf v1.36.9 // indirect
+	immxrtalbeast/order_microservices/internal/pkg/money v0.0.0-00010101000000-000000000000 // indirect
+)
+
+replace immxrtalbeast/order_microservices/internal/pkg/kafka => ../kafka
+
+replace immxrtalbeast/order_microservices/internal/pkg/events => ../events
+
+replace immxrtalbeast/order_microservices/internal/pkg/money => ../money
@@ -0,0 +1,69 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Message is an event written in the same transaction as the change it
+// describes and published to Kafka afterwards by the relay. The table is
+// shared by all services, Producer tells whose relay owns the row.
+type Message struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement"`
+	MessageID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex"`
+	Producer    string    `gorm:"not null"`
+	Topic       string    `gorm:"not null"`
+	AggregateID string    `gorm:"not null"`
+	Key         string
+	EventType   string `gorm:"not null"`
+	Payload     string `gorm:"type:jsonb;not null"`
+	Headers     string `gorm:"type:jsonb"`
+	CreatedAt   time.Time
+	SentAt      *time.Time
+}
+
+func (Message) TableName() string {
+	return "outbox"
+}
+
+// newMessage wraps event in an envelope stamped with producer and builds its
+// outbox message, keeping the trace context of ctx so the published event
+// stays in the caller's trace. The aggregate ID is the Kafka key, so all events
+// of one aggregate share a partition, and the correlation ID unless ctx
+// already carries one.
+func newMessage(ctx context.Context, producer, topic, aggregateID string, event events.Event) (*Message, error) {
+	messageID := uuid.New()
+	correlationID := events.CorrelationID(ctx)
+	if correlationID == "" {
+		correlationID = aggregateID
+	}
+	envelope, err := events.NewEnvelope(messageID.String(), producer, correlationID, event)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, err
+	}
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	headers, err := json.Marshal(carrier)
+	if err != nil {
+		return nil, err
+	}
+	return &Message{
+		MessageID:   messageID,
+		Producer:    producer,
+		Topic:       topic,
+		AggregateID: aggregateID,
+		Key:         aggregateID,
+		EventType:   event.EventType(),
+		Payload:     string(payload),
+		Headers:     string(headers),
+	}, nil
+}
@@ -0,0 +1,112 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	kafka "immxrtalbeast/order_microservices/internal/pkg/kafka"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Relay publishes messages written to the outbox to Kafka.
+// Messages are stored as JSON envelopes and encoded as contentType on publish.
+type Relay struct {
+	log         *slog.Logger
+	repo        *Repository
+	producers   map[string]*kafka.Producer
+	contentType string
+}
+
+func NewRelay(log *slog.Logger, repo *Repository, producers map[string]*kafka.Producer, contentType string) *Relay {
+	return &Relay{log: log, repo: repo, producers: producers, contentType: contentType}
+}
+
+// Run polls the outbox until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context, interval time.Duration, batchSize int) {
+	r.log.Info("outbox relay started", slog.Duration("interval", interval))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			r.log.Info("outbox relay stopped")
+			return
+		case <-ticker.C:
+			r.drain(ctx, batchSize)
+		}
+	}
+}
+
+func (r *Relay) drain(ctx context.Context, batchSize int) {
+	const op = "outbox.drain"
+	log := r.log.With(
+		slog.String("op", op),
+	)
+	for {
+		sent, err := r.repo.Dispatch(ctx, batchSize, r.publish)
+		if err != nil {
+			log.Error("failed to dispatch outbox", slog.String("error", err.Error()))
+			return
+		}
+		if sent < batchSize {
+			return
+		}
+	}
+}
+
+// publish sends msgs in order and returns the ids that made it to Kafka. Once a
+// message fails, later messages of the same aggregate are held back so they are
+// never published ahead of it.
+func (r *Relay) publish(ctx context.Context, msgs []Message) []int64 {
+	propagator := propagation.TraceContext{}
+	blocked := make(map[string]bool)
+	sent := make([]int64, 0, len(msgs))
+	for _, msg := range msgs {
+		if blocked[msg.AggregateID] {
+			continue
+		}
+		log := r.log.With(
+			slog.Int64("outbox_id", msg.ID),
+			slog.String("topic", msg.Topic),
+			slog.String("event_type", msg.EventType),
+			slog.String("aggregate_id", msg.AggregateID),
+		)
+		producer, ok := r.producers[msg.Topic]
+		if !ok {
+			log.Error("no producer for outbox topic")
+			blocked[msg.AggregateID] = true
+			continue
+		}
+		carrier := propagation.MapCarrier{}
+		if msg.Headers != "" {
+			if err := json.Unmarshal([]byte(msg.Headers), &carrier); err != nil {
+				log.Warn("failed to read outbox headers", slog.String("error", err.Error()))
+			}
+		}
+		value, err := r.encode(msg)
+		if err != nil {
+			log.Error("failed to encode outbox message", slog.String("error", err.Error()))
+			blocked[msg.AggregateID] = true
+			continue
+		}
+		msgCtx := propagator.Extract(ctx, carrier)
+		if err := producer.PublishMessage(msgCtx, msg.MessageID.String(), msg.Key, value, msg.EventType, r.contentType); err != nil {
+			log.Error("failed to publish outbox message", slog.String("error", err.Error()))
+			blocked[msg.AggregateID] = true
+			continue
+		}
+		sent = append(sent, msg.ID)
+	}
+	return sent
+}
+
+func (r *Relay) encode(msg Message) ([]byte, error) {
+	envelope, err := events.Parse([]byte(msg.Payload), msg.EventType)
+	if err != nil {
+		return nil, err
+	}
+	return events.Marshal(envelope, r.contentType)
+}
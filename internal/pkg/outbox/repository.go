@@ -0,0 +1,66 @@
+package outbox
+
+import (
+	"context"
+	"immxrtalbeast/order_microservices/internal/pkg/events"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Repository stores the outbox messages of one producer.
+type Repository struct {
+	db       *gorm.DB
+	producer string
+}
+
+func NewRepository(db *gorm.DB, producer string) *Repository {
+	return &Repository{db: db, producer: producer}
+}
+
+// Enqueue writes event to the outbox for topic, in the transaction carried by
+// ctx if there is one.
+func (r *Repository) Enqueue(ctx context.Context, topic, aggregateID string, event events.Event) error {
+	msg, err := newMessage(ctx, r.producer, topic, aggregateID, event)
+	if err != nil {
+		return err
+	}
+	return Conn(ctx, r.db).Create(msg).Error
+}
+
+// Dispatch hands up to limit pending messages to publish in insertion order and
+// marks the ids it returns as sent. A transaction-scoped advisory lock keeps
+// replicas of the same service from relaying concurrently and reordering events.
+func (r *Repository) Dispatch(ctx context.Context, limit int, publish func(ctx context.Context, msgs []Message) []int64) (int, error) {
+	var sent int
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(hashtext(?))", "outbox:"+r.producer).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+
+		var msgs []Message
+		if err := tx.Where("producer = ? AND sent_at IS NULL", r.producer).
+			Order("id").
+			Limit(limit).
+			Find(&msgs).Error; err != nil {
+			return err
+		}
+		if len(msgs) == 0 {
+			return nil
+		}
+
+		ids := publish(ctx, msgs)
+		if len(ids) == 0 {
+			return nil
+		}
+		sent = len(ids)
+		return tx.Model(&Message{}).
+			Where("id IN ?", ids).
+			Update("sent_at", time.Now()).Error
+	})
+	return sent, err
+}
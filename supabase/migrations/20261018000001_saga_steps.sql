@@ -0,0 +1,12 @@
+-- Saga step log: one row per saga state transition
+create table if not exists saga_steps (
+    id          uuid primary key default uuid_generate_v4(),
+    saga_id     uuid not null references sagas(id) on delete cascade,
+    from_state  text,
+    to_state    text not null,
+    event_type  text not null,
+    payload     jsonb,
+    trace_id    text,
+    created_at  timestamptz default now()
+);
+create index if not exists idx_saga_steps_saga_id on saga_steps(saga_id);
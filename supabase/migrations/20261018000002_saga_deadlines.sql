@@ -0,0 +1,6 @@
+-- Pending command and step deadline, used by the saga sweeper
+alter table sagas add column if not exists pending_command_type text;
+alter table sagas add column if not exists pending_command jsonb;
+alter table sagas add column if not exists deadline_at timestamptz;
+alter table sagas add column if not exists attempts integer not null default 0;
+create index if not exists idx_sagas_deadline_at on sagas(deadline_at) where deadline_at is not null;
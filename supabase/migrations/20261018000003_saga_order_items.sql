@@ -0,0 +1,12 @@
+-- Order id and line items on the saga, so compensation needs no other service
+alter table sagas add column if not exists order_id uuid;
+create unique index if not exists idx_sagas_order_id on sagas(order_id);
+
+create table if not exists saga_items (
+    id uuid primary key default uuid_generate_v4(),
+    saga_id uuid not null references sagas(id) on delete cascade,
+    product_id uuid not null,
+    quantity integer not null,
+    reserved_quantity integer not null default 0
+);
+create index if not exists idx_saga_items_saga_id on saga_items(saga_id);
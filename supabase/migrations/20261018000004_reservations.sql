@@ -0,0 +1,11 @@
+-- Stock reserved per order, so a cancelled order can return it exactly once
+create table if not exists reservations (
+    id uuid primary key default uuid_generate_v4(),
+    order_id uuid not null,
+    saga_id uuid not null,
+    good_id uuid not null,
+    quantity integer not null,
+    released_at timestamptz,
+    created_at timestamptz not null default now()
+);
+create index if not exists idx_reservations_order_id on reservations(order_id);
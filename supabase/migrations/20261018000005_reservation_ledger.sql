@@ -0,0 +1,13 @@
+-- Reservations become a ledger of holds: HELD counts against available stock,
+-- COMMITTED has been taken off hand, RELEASED no longer counts.
+alter table reservations add column if not exists state varchar(20) not null default 'HELD';
+alter table reservations add column if not exists expires_at timestamptz not null default now();
+alter table reservations add column if not exists updated_at timestamptz not null default now();
+
+-- Reservations made before the ledger already reduced quantity_in_stock.
+update reservations set state = 'RELEASED', updated_at = released_at where released_at is not null;
+update reservations set state = 'COMMITTED' where released_at is null;
+alter table reservations drop column if exists released_at;
+
+create unique index if not exists idx_reservations_order_good on reservations(order_id, good_id);
+create index if not exists idx_reservations_held_expires_at on reservations(expires_at) where state = 'HELD';
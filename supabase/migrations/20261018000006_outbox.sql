@@ -0,0 +1,14 @@
+-- Transactional outbox shared by all services; producer is the owning service
+create table if not exists outbox (
+    id bigserial primary key,
+    producer text not null,
+    topic text not null,
+    aggregate_id text not null,
+    key text,
+    event_type text not null,
+    payload jsonb not null,
+    headers jsonb,
+    created_at timestamptz not null default now(),
+    sent_at timestamptz
+);
+create index if not exists idx_outbox_pending on outbox(producer, id) where sent_at is null;
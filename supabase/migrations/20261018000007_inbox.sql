@@ -0,0 +1,12 @@
+-- Stable message id carried in the Message-Id header; relays reuse it on republish
+alter table outbox add column if not exists message_id uuid not null default uuid_generate_v4();
+create unique index if not exists idx_outbox_message_id on outbox(message_id);
+
+-- Messages already handled by each consumer, used to drop redeliveries
+create table if not exists inbox (
+    consumer text not null,
+    message_id text not null,
+    event_type text,
+    processed_at timestamptz not null default now(),
+    primary key (consumer, message_id)
+);
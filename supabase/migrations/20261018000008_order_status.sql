@@ -0,0 +1,9 @@
+-- Map legacy statuses onto the order state machine: orders still waiting for
+-- their reservation go back to CREATED, reserved ones become RESERVED
+update orders set status = 'CREATED' where status = 'PENDING' or (status = 'PROCESSING' and total = 0);
+update orders set status = 'RESERVED' where status = 'PROCESSING';
+
+alter table orders drop constraint if exists orders_status_check;
+alter table orders add constraint orders_status_check check (
+    status in ('CREATED', 'RESERVED', 'PREPARING', 'READY', 'COMPLETED', 'CANCELLED', 'FAILED')
+);
@@ -0,0 +1,13 @@
+-- Every status change of an order: who made it (user, admin or saga) and why.
+-- from_status is empty for the entry written when the order is created
+create table if not exists order_status_history (
+    id          bigserial primary key,
+    order_id    uuid not null references orders(id) on delete cascade,
+    from_status varchar(20) not null default '',
+    to_status   varchar(20) not null,
+    actor       varchar(20) not null check (actor in ('user', 'admin', 'saga')),
+    actor_id    text,
+    reason      text,
+    changed_at  timestamptz not null default now()
+);
+create index if not exists idx_order_status_history_order_id on order_status_history(order_id, changed_at);
@@ -0,0 +1,16 @@
+-- Product name, volume and price as reserved, so later price changes do not
+-- alter past orders
+alter table order_items add column if not exists name text;
+alter table order_items add column if not exists volume integer;
+alter table order_items add column if not exists unit_price decimal(10,2) not null default 0;
+alter table order_items add column if not exists line_total decimal(10,2) not null default 0;
+
+-- Existing orders never stored their prices; the current ones are the best
+-- available approximation
+update order_items oi
+set name = g.name,
+    volume = g.volume,
+    unit_price = g.price,
+    line_total = g.price * oi.quantity
+from goods g
+where g.id = oi.product_id and oi.unit_price = 0;
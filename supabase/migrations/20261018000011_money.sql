@@ -0,0 +1,21 @@
+-- Money is stored as an integer amount of minor units (kopecks for RUB) plus
+-- an ISO 4217 currency code, so totals and prices are exact
+alter table goods add column if not exists price_amount bigint not null default 0;
+alter table goods add column if not exists price_currency char(3) not null default 'RUB';
+update goods set price_amount = price::bigint * 100;
+alter table goods drop column if exists price;
+
+alter table orders add column if not exists total_amount bigint not null default 0;
+alter table orders add column if not exists total_currency char(3) not null default 'RUB';
+update orders set total_amount = round(total * 100)::bigint;
+alter table orders drop column if exists total;
+
+alter table order_items add column if not exists unit_price_amount bigint not null default 0;
+alter table order_items add column if not exists unit_price_currency char(3) not null default 'RUB';
+alter table order_items add column if not exists line_total_amount bigint not null default 0;
+alter table order_items add column if not exists line_total_currency char(3) not null default 'RUB';
+update order_items
+set unit_price_amount = round(unit_price * 100)::bigint,
+    line_total_amount = round(line_total * 100)::bigint;
+alter table order_items drop column if exists unit_price;
+alter table order_items drop column if exists line_total;
@@ -0,0 +1,13 @@
+-- Idempotency-Key of create-order requests with the order they created, so a
+-- retry returns the same order. Keys are scoped to the user and expire
+create table if not exists idempotency_keys (
+    user_id      uuid not null,
+    key          text not null,
+    request_hash text not null,
+    order_id     uuid not null,
+    status       varchar(20) not null,
+    created_at   timestamptz not null default now(),
+    expires_at   timestamptz not null,
+    primary key (user_id, key)
+);
+create index if not exists idx_idempotency_keys_expires_at on idempotency_keys(expires_at);
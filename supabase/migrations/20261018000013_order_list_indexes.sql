@@ -0,0 +1,6 @@
+-- Keyset pagination of order lists walks (sort column, id); product filters
+-- probe order_items by product
+create index if not exists idx_orders_created_at_id on orders(created_at, id);
+create index if not exists idx_orders_total_amount_id on orders(total_amount, id);
+create index if not exists idx_orders_user_id_created_at_id on orders(user_id, created_at, id);
+create index if not exists idx_order_items_product_id on order_items(product_id);
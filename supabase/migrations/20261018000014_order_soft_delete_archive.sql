@@ -0,0 +1,17 @@
+-- Deleting an order only marks it; queries skip orders with deleted_at set
+alter table orders add column if not exists deleted_at timestamptz;
+create index if not exists idx_orders_deleted_at on orders(deleted_at);
+
+-- Old completed orders are moved here by the archive job. payload keeps the
+-- order with its items and status history so it can be restored unchanged
+create table if not exists order_archive (
+    order_id       uuid primary key,
+    user_id        uuid not null,
+    status         varchar(20) not null,
+    total_amount   bigint not null default 0,
+    total_currency char(3) not null default 'RUB',
+    created_at     timestamptz not null,
+    archived_at    timestamptz not null default now(),
+    payload        jsonb not null
+);
+create index if not exists idx_order_archive_user_id on order_archive(user_id);
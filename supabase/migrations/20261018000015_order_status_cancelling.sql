@@ -0,0 +1,5 @@
+-- CANCELLING holds an order while its cancel saga releases the reservation
+alter table orders drop constraint if exists orders_status_check;
+alter table orders add constraint orders_status_check check (
+    status in ('CREATED', 'RESERVED', 'PREPARING', 'READY', 'COMPLETED', 'CANCELLING', 'CANCELLED', 'FAILED')
+);
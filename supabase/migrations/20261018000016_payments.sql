@@ -0,0 +1,38 @@
+-- One payment per order, held by payment-service. The refs are the IDs the
+-- payment provider knows the authorization and capture by
+create table if not exists payments (
+    id                uuid primary key default uuid_generate_v4(),
+    order_id          uuid not null unique,
+    saga_id           uuid not null,
+    user_id           uuid not null,
+    amount_amount     bigint not null,
+    amount_currency   char(3) not null default 'RUB',
+    refunded_amount   bigint not null default 0,
+    refunded_currency char(3) not null default 'RUB',
+    status            varchar(20) not null,
+    provider          text not null,
+    authorization_ref text,
+    capture_ref       text,
+    failure_reason    text,
+    created_at        timestamptz not null default now(),
+    updated_at        timestamptz not null default now()
+);
+create index if not exists idx_payments_user_id on payments(user_id);
+
+create table if not exists payment_refunds (
+    id              uuid primary key default uuid_generate_v4(),
+    payment_id      uuid not null references payments(id) on delete cascade,
+    amount_amount   bigint not null,
+    amount_currency char(3) not null default 'RUB',
+    provider_ref    text not null,
+    created_at      timestamptz not null default now()
+);
+create index if not exists idx_payment_refunds_payment_id on payment_refunds(payment_id);
+
+-- The saga authorizes the reserved total and remembers the payment it holds
+alter table sagas add column if not exists total_amount bigint not null default 0;
+alter table sagas add column if not exists total_currency char(3) not null default 'RUB';
+alter table sagas add column if not exists payment_id uuid;
+alter table sagas add column if not exists payment_captured boolean not null default false;
+alter table sagas add column if not exists complete_requested boolean not null default false;
+alter table sagas add column if not exists cancel_requested boolean not null default false;
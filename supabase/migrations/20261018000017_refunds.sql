@@ -0,0 +1,56 @@
+-- Refunds of completed orders, in full or by line. Pending and completed
+-- refunds count towards what may still be refunded
+create table if not exists order_refunds (
+    id              uuid primary key default uuid_generate_v4(),
+    order_id        uuid not null references orders(id) on delete cascade,
+    amount_amount   bigint not null,
+    amount_currency char(3) not null default 'RUB',
+    restock         boolean not null default false,
+    reason          text,
+    status          varchar(20) not null,
+    failure_reason  text,
+    requested_by    text,
+    created_at      timestamptz not null default now(),
+    updated_at      timestamptz not null default now()
+);
+create index if not exists idx_order_refunds_order_id on order_refunds(order_id);
+
+create table if not exists order_refund_items (
+    id              uuid primary key default uuid_generate_v4(),
+    refund_id       uuid not null references order_refunds(id) on delete cascade,
+    product_id      uuid not null,
+    quantity        integer not null check (quantity > 0),
+    amount_amount   bigint not null,
+    amount_currency char(3) not null default 'RUB'
+);
+create index if not exists idx_order_refund_items_refund_id on order_refund_items(refund_id);
+
+-- total keeps what is left paid; refunded is what completed refunds returned
+alter table orders add column if not exists refunded_amount bigint not null default 0;
+alter table orders add column if not exists refunded_currency char(3) not null default 'RUB';
+
+-- Completed refunds add a history line that keeps the order status
+alter table order_status_history add column if not exists refund_id uuid;
+alter table order_status_history add column if not exists refund_amount_amount bigint not null default 0;
+alter table order_status_history add column if not exists refund_amount_currency varchar(3) not null default '';
+
+-- Refund sagas share the sagas table with order sagas; only order sagas are
+-- one per order
+alter table sagas add column if not exists kind varchar(20) not null default 'ORDER';
+alter table sagas add column if not exists refund_id uuid;
+alter table sagas add column if not exists restock boolean not null default false;
+drop index if exists idx_sagas_order_id;
+create unique index if not exists idx_sagas_order_id on sagas(order_id) where kind = 'ORDER';
+create unique index if not exists idx_sagas_refund_id on sagas(refund_id);
+
+-- A saga refunds a payment at most once
+alter table payment_refunds add column if not exists saga_id uuid;
+create unique index if not exists idx_payment_refunds_saga_id on payment_refunds(saga_id);
+
+-- Refund sagas whose items inventory-service already put back in stock
+create table if not exists restocks (
+    saga_id    uuid primary key,
+    order_id   uuid not null,
+    created_at timestamptz not null default now()
+);
+create index if not exists idx_restocks_order_id on restocks(order_id);
@@ -0,0 +1,22 @@
+-- Server side carts, one per user or anonymous by ID alone. A cart left
+-- untouched until expires_at is deleted by order-service
+create table if not exists carts (
+    id                uuid primary key default uuid_generate_v4(),
+    user_id           uuid,
+    checkout_key      text,
+    checkout_order_id uuid,
+    expires_at        timestamptz not null,
+    created_at        timestamptz not null default now(),
+    updated_at        timestamptz not null default now()
+);
+create unique index if not exists idx_carts_user_id on carts(user_id);
+create index if not exists idx_carts_expires_at on carts(expires_at);
+
+create table if not exists cart_items (
+    id         uuid primary key default uuid_generate_v4(),
+    cart_id    uuid not null references carts(id) on delete cascade,
+    product_id uuid not null,
+    quantity   integer not null check (quantity > 0),
+    created_at timestamptz not null default now()
+);
+create unique index if not exists idx_cart_items_cart_product on cart_items(cart_id, product_id);
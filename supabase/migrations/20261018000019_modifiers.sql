@@ -0,0 +1,86 @@
+-- Modifier groups offered for goods (milk type, syrups, extra shot). A group
+-- is shared between goods, so a tracked modifier draws on one stock
+create table if not exists modifier_groups (
+    id             uuid primary key default uuid_generate_v4(),
+    name           text not null,
+    selection_type varchar(20) not null,
+    min_selected   bigint not null default 0,
+    max_selected   bigint not null,
+    created_at     timestamptz not null default now(),
+    updated_at     timestamptz not null default now()
+);
+
+create table if not exists modifiers (
+    id                   uuid primary key default uuid_generate_v4(),
+    group_id             uuid not null references modifier_groups(id) on delete cascade,
+    name                 text not null,
+    price_delta_amount   bigint not null default 0,
+    price_delta_currency char(3) not null default 'RUB',
+    track_stock          boolean not null default false,
+    quantity_in_stock    bigint not null default 0,
+    position             bigint not null default 0
+);
+create index if not exists idx_modifiers_group_id on modifiers(group_id);
+
+create table if not exists good_modifier_groups (
+    good_id  uuid not null references goods(id) on delete cascade,
+    group_id uuid not null references modifier_groups(id) on delete cascade,
+    position bigint not null default 0,
+    primary key (good_id, group_id)
+);
+create index if not exists idx_good_modifier_groups_group_id on good_modifier_groups(group_id);
+
+-- Holds on tracked modifiers, kept like reservations of goods
+create table if not exists modifier_reservations (
+    id          uuid primary key default uuid_generate_v4(),
+    order_id    uuid not null,
+    saga_id     uuid not null,
+    modifier_id uuid not null,
+    quantity    bigint not null,
+    state       varchar(20) not null default 'HELD',
+    expires_at  timestamptz not null,
+    created_at  timestamptz not null default now(),
+    updated_at  timestamptz not null default now()
+);
+create unique index if not exists idx_modifier_reservations_order_modifier on modifier_reservations(order_id, modifier_id);
+create index if not exists idx_modifier_reservations_held_expires_at on modifier_reservations(expires_at) where state = 'HELD';
+
+-- Modifiers chosen for an order line, priced as they were at checkout
+create table if not exists order_item_modifiers (
+    id                   uuid primary key default uuid_generate_v4(),
+    order_item_id        uuid not null references order_items(id) on delete cascade,
+    modifier_id          uuid not null,
+    name                 text,
+    price_delta_amount   bigint not null default 0,
+    price_delta_currency char(3) not null default 'RUB'
+);
+create index if not exists idx_order_item_modifiers_order_item_id on order_item_modifiers(order_item_id);
+
+-- A cart line is a product with a choice of modifiers, kept as their sorted
+-- comma separated IDs
+alter table cart_items add column if not exists modifier_key text not null default '';
+drop index if exists idx_cart_items_cart_product;
+create unique index if not exists idx_cart_items_cart_line on cart_items(cart_id, product_id, modifier_key);
+
+-- Seed: plant milk moves from a separate good to a modifier of the milk drinks
+insert into modifier_groups (id, name, selection_type, min_selected, max_selected) values
+('6f1c2a44-1d0e-4c55-9a63-3b7f0c9e2a01', 'Молоко',  'SINGLE',   0, 1),
+('6f1c2a44-1d0e-4c55-9a63-3b7f0c9e2a02', 'Добавки', 'MULTIPLE', 0, 3)
+on conflict do nothing;
+
+insert into modifiers (id, group_id, name, price_delta_amount, track_stock, quantity_in_stock, position) values
+('9b4e7d10-5a2f-4e8c-b1d3-7c6a0f2e4b01', '6f1c2a44-1d0e-4c55-9a63-3b7f0c9e2a01', 'Растительное молоко', 6000, true,  200, 0),
+('9b4e7d10-5a2f-4e8c-b1d3-7c6a0f2e4b02', '6f1c2a44-1d0e-4c55-9a63-3b7f0c9e2a02', 'Сироп ванильный',     4000, false,   0, 0),
+('9b4e7d10-5a2f-4e8c-b1d3-7c6a0f2e4b03', '6f1c2a44-1d0e-4c55-9a63-3b7f0c9e2a02', 'Сироп карамельный',   4000, false,   0, 1),
+('9b4e7d10-5a2f-4e8c-b1d3-7c6a0f2e4b04', '6f1c2a44-1d0e-4c55-9a63-3b7f0c9e2a02', 'Дополнительный шот',  5000, false,   0, 2)
+on conflict do nothing;
+
+insert into good_modifier_groups (good_id, group_id, position)
+select g.id, mg.id, case mg.name when 'Молоко' then 0 else 1 end
+from goods g
+cross join modifier_groups mg
+where g.name in ('Капучино', 'Латте', 'Флэт уайт', 'Раф', 'Айс Латте', 'Матча Латте', 'Айс Матча Латте')
+  and mg.id in ('6f1c2a44-1d0e-4c55-9a63-3b7f0c9e2a01', '6f1c2a44-1d0e-4c55-9a63-3b7f0c9e2a02')
+on conflict do nothing;
+
+delete from goods where name = 'Растительное молоко';
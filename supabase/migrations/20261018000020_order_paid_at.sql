@@ -0,0 +1,7 @@
+-- The saga captures the payment when the order is READY, and the order is
+-- only completed once paid_at is set
+alter table orders add column if not exists paid_at timestamptz;
+
+-- Orders that reached READY before this change are captured on completion
+-- as before, so they are not held back
+update orders set paid_at = updated_at where paid_at is null and status in ('READY', 'COMPLETED');